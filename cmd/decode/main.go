@@ -0,0 +1,204 @@
+// decode выводит разбор данных камеры в JSON: заголовок, дерево TLV, определенный формат и получившийся случай.
+// Данные читаются из файла или, если файл не указан, из stdin:
+//
+//	go run ./cmd/decode payload.hex
+//	echo 0000000100000010... | go run ./cmd/decode --strict
+//
+// Способ кодирования задается флагом -encoding, иначе определяется по расширению файла
+// (.bin, .hex, .b64 / .base64, .bits / .txt), а для stdin и файлов без известного расширения - bits.
+// Без --strict ошибка заголовка не мешает разобрать тело, все ошибки выводятся в поле errors.
+// С --strict программа завершается с кодом 1 и объясняет первую ошибку декодирования
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/decoder"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/models"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Количество байт, выводимых до и после места ошибки
+const contextBytes = 8
+
+type report struct {
+	Encoding   string                 `json:"encoding"`
+	Size       int                    `json:"size"`
+	Header     *decoder.Header        `json:"header,omitempty"`
+	Tree       map[string]interface{} `json:"tree,omitempty"`
+	Format     string                 `json:"format,omitempty"`
+	CameraData decoder.CameraModel    `json:"camera_data,omitempty"`
+	Case       *models.CaseBase       `json:"case,omitempty"`
+	Errors     []string               `json:"errors,omitempty"`
+}
+
+func main() {
+	encoding := flag.String("encoding", "", "способ кодирования: bits, hex, base64 или raw")
+	strict := flag.Bool("strict", false, "завершиться с кодом 1 при первой ошибке декодирования")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Использование: %s [-encoding bits|hex|base64|raw] [--strict] [файл]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() > 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	data, err := readInput(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Не удалось прочитать данные: %v\n", err)
+		os.Exit(2)
+	}
+
+	if *encoding == "" {
+		*encoding = decoder.EncodingByExtension(filepath.Ext(flag.Arg(0)))
+	}
+	if *encoding == "" {
+		*encoding = decoder.EncodingBits
+	}
+
+	payload, err := decoder.DecodeEncoding(data, *encoding)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Не удалось раскодировать данные: %v\n", err)
+		os.Exit(1)
+	}
+
+	result, errs := inspect(payload, *strict)
+	result.Encoding = strings.ToLower(*encoding)
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		fmt.Fprintf(os.Stderr, "Не удалось вывести результат: %v\n", err)
+		os.Exit(2)
+	}
+
+	if *strict && len(errs) > 0 {
+		fmt.Fprintln(os.Stderr, explain(errs[0], payload))
+		os.Exit(1)
+	}
+}
+
+func readInput(name string) ([]byte, error) {
+	if name == "" || name == "-" {
+		return io.ReadAll(io.LimitReader(os.Stdin, decoder.MaxPayloadSize*8+1))
+	}
+	return os.ReadFile(name)
+}
+
+// inspect последовательно проходит этапы обработки данных камеры, как при создании случая.
+// В строгом режиме разбор останавливается на первой ошибке
+func inspect(payload []byte, strict bool) (report, []error) {
+	var (
+		result report
+		errs   []error
+	)
+	result.Size = len(payload)
+
+	finish := func() (report, []error) {
+		for _, err := range errs {
+			result.Errors = append(result.Errors, err.Error())
+		}
+		return result, errs
+	}
+
+	header, _, err := decoder.ParsePayload(payload)
+	if err == nil {
+		result.Header = &header
+	}
+
+	tree, err := decoder.DecodePayload(payload, decoder.DefaultLimits)
+	if err != nil {
+		errs = append(errs, err)
+		if strict || !isHeaderErr(err) {
+			return finish()
+		}
+
+		// Заголовок некорректен, но тело все равно разбирается, чтобы показать, что передала камера
+		tree, err = decodeBody(payload)
+		if err != nil {
+			errs = append(errs, err)
+			return finish()
+		}
+	}
+	result.Tree = tree
+
+	model, err := decoder.MapToStruct(tree)
+	if err != nil {
+		errs = append(errs, err)
+		return finish()
+	}
+	result.Format = strings.TrimPrefix(fmt.Sprintf("%T", model), "decoder.")
+	result.CameraData = model
+
+	caseData, err := model.CameraDataToCaseBase()
+	if err != nil {
+		errs = append(errs, err)
+		return finish()
+	}
+	result.Case = &caseData
+
+	return finish()
+}
+
+func isHeaderErr(err error) bool {
+	return errors.Is(err, decoder.ErrUnsupportedVersion) || errors.Is(err, decoder.ErrUnknownFlags) ||
+		errors.Is(err, decoder.ErrLengthMismatch) || errors.Is(err, decoder.ErrChecksum)
+}
+
+// decodeBody разбирает тело без проверки заголовка, отбрасывая контрольную сумму, если ее задают флаги
+func decodeBody(payload []byte) (map[string]interface{}, error) {
+	body := payload[2:]
+	if payload[0] == decoder.Version1 {
+		switch {
+		case payload[1]&decoder.FlagCRC32 != 0 && len(body) >= 4:
+			body = body[:len(body)-4]
+		case payload[1]&decoder.FlagCRC16 != 0 && len(body) >= 2:
+			body = body[:len(body)-2]
+		}
+	}
+
+	tree, err := decoder.NewStreamDecoder(bytes.NewReader(body), decoder.DefaultLimits).Decode()
+	var decodeErr *decoder.DecodeError
+	if errors.As(err, &decodeErr) {
+		decodeErr.Offset += 2
+	}
+	return tree, err
+}
+
+// explain описывает ошибку и, если известно смещение, показывает байты вокруг него
+func explain(err error, payload []byte) string {
+	message := fmt.Sprintf("Ошибка декодирования: %v", err)
+
+	var decodeErr *decoder.DecodeError
+	if !errors.As(err, &decodeErr) {
+		return message
+	}
+
+	offset := int(decodeErr.Offset)
+	if offset >= len(payload) {
+		return fmt.Sprintf("%s\nДанные закончились на %d байте", message, len(payload))
+	}
+
+	start := max(offset-contextBytes, 0)
+	end := min(offset+contextBytes+1, len(payload))
+
+	var sb strings.Builder
+	for i := start; i < end; i++ {
+		if i == offset {
+			fmt.Fprintf(&sb, "[%02x] ", payload[i])
+		} else {
+			fmt.Fprintf(&sb, "%02x ", payload[i])
+		}
+	}
+
+	return fmt.Sprintf("%s\nБайты %d-%d: %s", message, start, end-1, strings.TrimSpace(sb.String()))
+}
@@ -1,25 +1,41 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/gl1n0m3c/IT_LAB_INIT/internal/delivery/docs"
 	"github.com/gl1n0m3c/IT_LAB_INIT/internal/delivery/middleware"
 	"github.com/gl1n0m3c/IT_LAB_INIT/internal/delivery/routers"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/dispatcher"
 	"github.com/gl1n0m3c/IT_LAB_INIT/internal/reporting_period"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/repository"
 	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/config"
 	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/database"
 	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/log"
+	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/notifier"
 	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/tracing"
 	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/utils/jwt"
 	"github.com/spf13/viper"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 )
 
 const serviceName = "admin-panel"
 
+// Время, за которое сервер завершает обработку начатых запросов при остановке
+const shutdownTimeout = 10 * time.Second
+
 func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	router := gin.Default()
 
 	router.Static("/static", "../static")
@@ -56,7 +72,37 @@ func main() {
 
 	go reporting_period.StartReporting(db, logger)
 
-	if err := router.Run("0.0.0.0:8080"); err != nil {
-		panic(fmt.Sprintf("Failed to run client: %s", err.Error()))
+	notifyPolicy := notifier.InitPolicy(notifier.InitEmailNotifier(), notifier.InitSMSNotifier(), notifier.InitWebhookNotifier())
+	fineDispatcher := dispatcher.InitDispatcher(repository.InitNotificationRepo(db), repository.InitCaseRepo(db),
+		repository.InitTransactor(db), notifyPolicy, logger)
+	dispatcherDone := make(chan struct{})
+	go func() {
+		defer close(dispatcherDone)
+		fineDispatcher.Start(ctx)
+	}()
+	logger.InfoLogger.Info().Msg(fmt.Sprintf("Notification dispatcher started, channels: %v", notifyPolicy.Channels()))
+
+	server := &http.Server{
+		Addr:    "0.0.0.0:8080",
+		Handler: router,
+	}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			panic(fmt.Sprintf("Failed to run client: %s", err.Error()))
+		}
+	}()
+
+	<-ctx.Done()
+	logger.InfoLogger.Info().Msg("Shutting down")
+
+	shutdownCtx, shutdownCansel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer shutdownCansel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		logger.ErrorLogger.Error().Msg(fmt.Sprintf("Failed to shutdown server: %s", err.Error()))
 	}
+
+	// Диспетчер записывает результат начатой отправки и возвращает в очередь остальные закрепленные уведомления
+	<-dispatcherDone
+	logger.InfoLogger.Info().Msg("Notification dispatcher stopped")
 }
@@ -0,0 +1,52 @@
+// smtpsink принимает письма по SMTP и сохраняет их вместо отправки, чтобы проверять уведомления о штрафах локально:
+//
+//	go run ./cmd/smtpsink -addr :1025 -dir ../data/mail
+//
+// В конфиге приложения для этого указываются MAIL_HOST и MAIL_PORT сервера и пустой MAIL_PASSWORD
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/smtpsink"
+	"os"
+	"os/signal"
+	"strings"
+)
+
+func main() {
+	addr := flag.String("addr", ":1025", "адрес, на котором принимаются письма")
+	dir := flag.String("dir", "", "каталог для сохранения писем в формате .eml, по умолчанию письма только выводятся")
+	flag.Parse()
+
+	if *dir != "" {
+		if err := os.MkdirAll(*dir, 0755); err != nil {
+			fmt.Fprintf(os.Stderr, "Не удалось создать каталог для писем: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	server, err := smtpsink.Listen(*addr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Не удалось запустить SMTP сервер: %v\n", err)
+		os.Exit(1)
+	}
+	server.Dir = *dir
+	server.OnMessage = func(message smtpsink.Message) {
+		fmt.Printf("%s письмо от %s для %s, %d байт\n", message.Received.Format("15:04:05"),
+			message.From, strings.Join(message.To, ", "), len(message.Data))
+	}
+
+	go func() {
+		stop := make(chan os.Signal, 1)
+		signal.Notify(stop, os.Interrupt)
+		<-stop
+		server.Close()
+	}()
+
+	fmt.Printf("SMTP сервер принимает письма на %s\n", server.Addr())
+	if err := server.Serve(); err != nil {
+		fmt.Fprintf(os.Stderr, "Ошибка SMTP сервера: %v\n", err)
+		os.Exit(1)
+	}
+}
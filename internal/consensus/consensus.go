@@ -0,0 +1,138 @@
+package consensus
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/models"
+)
+
+// Названия политик, хранящиеся в violations.consensus_policy
+const (
+	PolicyUnanimous     = "unanimous"
+	PolicyMajority      = "majority"
+	PolicySupermajority = "supermajority"
+	PolicyWeighted      = "weighted"
+)
+
+// Квалифицированное большинство по умолчанию - не меньше 2/3 оценок
+const (
+	SupermajorityNumerator   = 2
+	SupermajorityDenominator = 3
+)
+
+var ErrUnknownPolicy = errors.New("неизвестная политика консенсуса")
+
+// Policy принимает решение по случаю, когда на его текущем уровне набралось K оценок.
+// votes содержит все оценки случая вместе с уровнями оценивших специалистов.
+// Если solved = false, случай передается на следующий уровень
+type Policy interface {
+	Decide(votes []models.CaseVote, level int) (choice bool, solved bool)
+}
+
+var policies = map[string]Policy{
+	PolicyUnanimous:     Unanimous{},
+	PolicyMajority:      Majority{},
+	PolicySupermajority: Supermajority{Numerator: SupermajorityNumerator, Denominator: SupermajorityDenominator},
+	PolicyWeighted:      LevelWeighted{},
+}
+
+// ByName возвращает политику по названию, пустое название - единогласие
+func ByName(name string) (Policy, error) {
+	if name == "" {
+		name = PolicyUnanimous
+	}
+
+	policy, ok := policies[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownPolicy, name)
+	}
+	return policy, nil
+}
+
+// Unanimous закрывает случай, только если все оценки текущего уровня совпадают
+type Unanimous struct{}
+
+func (Unanimous) Decide(votes []models.CaseVote, level int) (bool, bool) {
+	yes, no := countAtLevel(votes, level)
+	switch {
+	case no == 0 && yes > 0:
+		return true, true
+	case yes == 0 && no > 0:
+		return false, true
+	default:
+		return false, false
+	}
+}
+
+// Majority закрывает случай решением большинства оценок текущего уровня, при равенстве случай передается выше
+type Majority struct{}
+
+func (Majority) Decide(votes []models.CaseVote, level int) (bool, bool) {
+	yes, no := countAtLevel(votes, level)
+	return decideMajority(yes, no)
+}
+
+// Supermajority закрывает случай, если за одно из решений подано не меньше Numerator/Denominator оценок
+// текущего уровня. Доля должна быть больше половины, иначе решение может быть не единственным
+type Supermajority struct {
+	Numerator   int
+	Denominator int
+}
+
+func (s Supermajority) Decide(votes []models.CaseVote, level int) (bool, bool) {
+	yes, no := countAtLevel(votes, level)
+	total := yes + no
+	switch {
+	case total == 0 || 2*s.Numerator <= s.Denominator:
+		return false, false
+	case yes*s.Denominator >= total*s.Numerator:
+		return true, true
+	case no*s.Denominator >= total*s.Numerator:
+		return false, true
+	default:
+		return false, false
+	}
+}
+
+// LevelWeighted учитывает все оценки случая, в том числе с предыдущих уровней,
+// и взвешивает каждую уровнем оценившего специалиста
+type LevelWeighted struct{}
+
+func (LevelWeighted) Decide(votes []models.CaseVote, _ int) (bool, bool) {
+	var yes, no int
+	for _, vote := range votes {
+		weight := max(vote.Level, 1)
+		if vote.Choice {
+			yes += weight
+		} else {
+			no += weight
+		}
+	}
+	return decideMajority(yes, no)
+}
+
+func countAtLevel(votes []models.CaseVote, level int) (yes, no int) {
+	for _, vote := range votes {
+		if vote.Level != level {
+			continue
+		}
+		if vote.Choice {
+			yes++
+		} else {
+			no++
+		}
+	}
+	return yes, no
+}
+
+// decideMajority принимает решение, за которое подано больше голосов, при равенстве решение не принимается
+func decideMajority(yes, no int) (bool, bool) {
+	switch {
+	case yes > no:
+		return true, true
+	case no > yes:
+		return false, true
+	default:
+		return false, false
+	}
+}
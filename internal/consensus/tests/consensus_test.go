@@ -0,0 +1,72 @@
+package tests
+
+import (
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/consensus"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/models"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type decision struct {
+	choice bool
+	solved bool
+}
+
+func decide(t *testing.T, name string, votes []models.CaseVote, level int) decision {
+	policy, err := consensus.ByName(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	choice, solved := policy.Decide(votes, level)
+	return decision{choice: choice, solved: solved}
+}
+
+func TestUnanimous(t *testing.T) {
+	assert.Equal(t, decision{true, true}, decide(t, consensus.PolicyUnanimous, votesUnanimousTrue, caseLevel))
+	assert.Equal(t, decision{false, true}, decide(t, consensus.PolicyUnanimous, votesUnanimousFalse, caseLevel))
+	assert.False(t, decide(t, consensus.PolicyUnanimous, votesTwoOfThree, caseLevel).solved)
+
+	// Оценки других уровней не учитываются
+	assert.Equal(t, decision{true, true}, decide(t, consensus.PolicyUnanimous,
+		append(votesAt(caseLevel-1, false), votesUnanimousTrue...), caseLevel))
+}
+
+func TestUnanimousIsDefault(t *testing.T) {
+	policy, err := consensus.ByName("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, consensus.Unanimous{}, policy)
+}
+
+func TestMajority(t *testing.T) {
+	assert.Equal(t, decision{true, true}, decide(t, consensus.PolicyMajority, votesTwoOfThree, caseLevel))
+	assert.Equal(t, decision{false, true}, decide(t, consensus.PolicyMajority, votesThreeOfFive, caseLevel))
+	assert.False(t, decide(t, consensus.PolicyMajority, votesTie, caseLevel).solved)
+	assert.False(t, decide(t, consensus.PolicyMajority, nil, caseLevel).solved)
+}
+
+func TestSupermajority(t *testing.T) {
+	assert.Equal(t, decision{true, true}, decide(t, consensus.PolicySupermajority, votesTwoOfThree, caseLevel))
+	assert.Equal(t, decision{false, true}, decide(t, consensus.PolicySupermajority, votesUnanimousFalse, caseLevel))
+	// 3 из 5 меньше 2/3
+	assert.False(t, decide(t, consensus.PolicySupermajority, votesThreeOfFive, caseLevel).solved)
+
+	// Доля не больше половины не позволяет принять решение
+	choice, solved := consensus.Supermajority{Numerator: 1, Denominator: 2}.Decide(votesTie, caseLevel)
+	assert.False(t, solved)
+	assert.False(t, choice)
+}
+
+func TestLevelWeighted(t *testing.T) {
+	// Равенство на текущем уровне решается оценками предыдущего уровня
+	assert.Equal(t, decision{false, true}, decide(t, consensus.PolicyWeighted, votesEscalated, caseLevel))
+	assert.Equal(t, decision{false, true}, decide(t, consensus.PolicyWeighted, votesSeniorOutweigh, 3))
+	assert.False(t, decide(t, consensus.PolicyWeighted, votesTie, caseLevel).solved)
+}
+
+func TestUnknownPolicy(t *testing.T) {
+	_, err := consensus.ByName("random")
+	assert.ErrorIs(t, err, consensus.ErrUnknownPolicy)
+}
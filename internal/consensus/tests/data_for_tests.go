@@ -0,0 +1,26 @@
+package tests
+
+import "github.com/gl1n0m3c/IT_LAB_INIT/internal/models"
+
+const caseLevel = 2
+
+func votesAt(level int, choices ...bool) []models.CaseVote {
+	votes := make([]models.CaseVote, 0, len(choices))
+	for _, choice := range choices {
+		votes = append(votes, models.CaseVote{Choice: choice, Level: level})
+	}
+	return votes
+}
+
+var (
+	votesUnanimousTrue  = votesAt(caseLevel, true, true, true)
+	votesUnanimousFalse = votesAt(caseLevel, false, false, false)
+	votesTwoOfThree     = votesAt(caseLevel, true, false, true)
+	votesThreeOfFive    = votesAt(caseLevel, false, true, false, true, false)
+	votesTie            = votesAt(caseLevel, true, false, true, false)
+
+	// На первом уровне случай не был решен, на втором мнения разделились поровну
+	votesEscalated = append(votesAt(caseLevel-1, false, false, false, true), votesAt(caseLevel, true, true, false, false)...)
+	// Два специалиста третьего уровня перевешивают трех специалистов первого
+	votesSeniorOutweigh = append(votesAt(1, true, true, true), votesAt(3, false, false)...)
+)
@@ -3,92 +3,335 @@ package decoder
 import (
 	"bytes"
 	"encoding/binary"
-	"encoding/json"
+	"errors"
 	"fmt"
 	leb1282 "github.com/jcalabro/leb128"
+	"io"
+	"math"
+	"time"
 )
 
+const (
+	typeString    uint8 = 0x00
+	typeInt       uint8 = 0x01
+	typeMap       uint8 = 0x02
+	typeFloat     uint8 = 0x03
+	typeBool      uint8 = 0x04
+	typeBytes     uint8 = 0x05
+	typeArray     uint8 = 0x06
+	typeTimestamp uint8 = 0x07
+)
+
+// Размер заголовка записи: длина ключа (2 байта), длина значения (2 байта), тип значения (1 байт)
+const recordHeaderSize = 5
+
+// Размер длины элемента массива
+const elementHeaderSize = 2
+
+var (
+	ErrTruncated   = errors.New("данные обрываются раньше, чем указано в длине")
+	ErrOverflow    = errors.New("запись выходит за границы вложенной структуры")
+	ErrUnknownType = errors.New("неизвестный тип значения")
+	ErrBadInt      = errors.New("не удалось декодировать LEB128 значение")
+	ErrBadFloat    = errors.New("число с плавающей точкой должно занимать 8 байт")
+	ErrBadBool     = errors.New("логическое значение должно быть байтом 0x00 или 0x01")
+	ErrMaxDepth    = errors.New("превышена максимальная глубина вложенности")
+	ErrMaxSize     = errors.New("превышен максимальный размер данных")
+	ErrMaxKeys     = errors.New("превышено максимальное количество ключей и элементов массивов")
+)
+
+// Limits ограничивают декодируемые данные. MaxKeys задает общее количество ключей структур и элементов массивов
+type Limits struct {
+	MaxDepth int
+	MaxSize  int
+	MaxKeys  int
+}
+
+// DefaultLimits используются для полей Limits, равных нулю
+var DefaultLimits = Limits{
+	MaxDepth: 8,
+	MaxSize:  64 << 10,
+	MaxKeys:  256,
+}
+
+type DecodeError struct {
+	Offset int64
+	Path   string
+	Err    error
+}
+
+func (e *DecodeError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("смещение %d: %v", e.Offset, e.Err)
+	}
+	return fmt.Sprintf("смещение %d, ключ %s: %v", e.Offset, e.Path, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+type StreamDecoder struct {
+	reader io.Reader
+	limits Limits
+	offset int64
+	keys   int
+}
+
+func NewStreamDecoder(reader io.Reader, limits Limits) *StreamDecoder {
+	if limits.MaxDepth <= 0 {
+		limits.MaxDepth = DefaultLimits.MaxDepth
+	}
+	if limits.MaxSize <= 0 {
+		limits.MaxSize = DefaultLimits.MaxSize
+	}
+	if limits.MaxKeys <= 0 {
+		limits.MaxKeys = DefaultLimits.MaxKeys
+	}
+
+	return &StreamDecoder{
+		reader: reader,
+		limits: limits,
+	}
+}
+
 func Decoder(buf *bytes.Buffer) (map[string]interface{}, error) {
+	return NewStreamDecoder(buf, DefaultLimits).Decode()
+}
+
+// Decode читает записи до конца потока
+func (d *StreamDecoder) Decode() (map[string]interface{}, error) {
+	return d.decodeMap(-1, 0, "")
+}
+
+// decodeMap читает записи, пока не будет прочитано limit байт, при limit < 0 - до конца потока
+func (d *StreamDecoder) decodeMap(limit int64, depth int, path string) (map[string]interface{}, error) {
 	result := make(map[string]interface{})
-	reader := bytes.NewReader(buf.Bytes())
+	start := d.offset
 
-	for reader.Len() > 0 {
-		var keyLen, valueLen uint16
-		var valueType uint8
+	for limit < 0 || d.offset-start < limit {
+		recordOffset := d.offset
 
-		// Чтение длины ключа
-		if err := binary.Read(reader, binary.BigEndian, &keyLen); err != nil {
-			return nil, err
+		if limit >= 0 && limit-(d.offset-start) < recordHeaderSize {
+			return nil, d.errorAt(recordOffset, path, ErrOverflow)
 		}
 
-		// Чтение длины значения
-		if err := binary.Read(reader, binary.BigEndian, &valueLen); err != nil {
-			return nil, err
+		// Чтение заголовка записи
+		header, err := d.read(recordHeaderSize, limit < 0)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, d.errorAt(recordOffset, path, err)
 		}
 
-		// Чтение типа значения
-		if err := binary.Read(reader, binary.BigEndian, &valueType); err != nil {
-			return nil, err
+		keyLen := binary.BigEndian.Uint16(header[0:2])
+		valueLen := binary.BigEndian.Uint16(header[2:4])
+		valueType := header[4]
+
+		if limit >= 0 && d.offset-start+int64(keyLen)+int64(valueLen) > limit {
+			return nil, d.errorAt(recordOffset, path, ErrOverflow)
+		}
+
+		d.keys++
+		if d.keys > d.limits.MaxKeys {
+			return nil, d.errorAt(recordOffset, path, ErrMaxKeys)
 		}
 
 		// Чтение ключа
-		key := make([]byte, keyLen)
-		if _, err := reader.Read(key); err != nil {
+		keyOffset := d.offset
+		keyBytes, err := d.read(int(keyLen), false)
+		if err != nil {
+			return nil, d.errorAt(keyOffset, path, err)
+		}
+		key := string(keyBytes)
+		keyPath := joinPath(path, key)
+
+		value, err := d.decodeValue(valueType, int64(valueLen), depth, keyPath, recordOffset+recordHeaderSize-1)
+		if err != nil {
 			return nil, err
 		}
+		result[key] = value
+	}
 
-		// Чтение значения в зависимости от его типа
-		value := make([]byte, valueLen)
-		if _, err := reader.Read(value); err != nil {
+	return result, nil
+}
+
+// decodeValue читает значение длиной length, typeOffset указывает на байт с типом значения
+func (d *StreamDecoder) decodeValue(valueType uint8, length int64, depth int, path string, typeOffset int64) (interface{}, error) {
+	valueOffset := d.offset
+
+	switch valueType {
+	case typeMap: // Вложенная структура
+		if depth+1 > d.limits.MaxDepth {
+			return nil, d.errorAt(valueOffset, path, ErrMaxDepth)
+		}
+		return d.decodeMap(length, depth+1, path)
+	case typeArray: // Однородный массив
+		if depth+1 > d.limits.MaxDepth {
+			return nil, d.errorAt(valueOffset, path, ErrMaxDepth)
+		}
+		return d.decodeArray(length, depth+1, path)
+	case typeString, typeInt, typeFloat, typeBool, typeBytes, typeTimestamp:
+	default:
+		return nil, d.errorAt(typeOffset, path, fmt.Errorf("%w: %v", ErrUnknownType, valueType))
+	}
+
+	value, err := d.read(int(length), false)
+	if err != nil {
+		return nil, d.errorAt(valueOffset, path, err)
+	}
+
+	switch valueType {
+	case typeString: // Строка
+		return string(value), nil
+	case typeInt: // Целое число
+		intValue, err := decodeLEB128(value)
+		if err != nil {
+			return nil, d.errorAt(valueOffset, path, err)
+		}
+		return intValue, nil
+	case typeFloat: // Число с плавающей точкой (IEEE 754, 8 байт)
+		if len(value) != 8 {
+			return nil, d.errorAt(valueOffset, path, ErrBadFloat)
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(value)), nil
+	case typeBool: // Логическое значение
+		if len(value) != 1 || value[0] > 1 {
+			return nil, d.errorAt(valueOffset, path, ErrBadBool)
+		}
+		return value[0] == 1, nil
+	case typeBytes: // Набор байт
+		return value, nil
+	default: // Время в миллисекундах от начала эпохи Unix
+		millis, err := decodeLEB128(value)
+		if err != nil {
+			return nil, d.errorAt(valueOffset, path, err)
+		}
+		return time.UnixMilli(millis).UTC(), nil
+	}
+}
+
+// decodeArray читает тип элементов (1 байт) и элементы, каждому из которых предшествует его длина (2 байта)
+func (d *StreamDecoder) decodeArray(limit int64, depth int, path string) ([]interface{}, error) {
+	result := make([]interface{}, 0)
+	start := d.offset
+
+	typeOffset := d.offset
+	if limit < 1 {
+		return nil, d.errorAt(typeOffset, path, ErrOverflow)
+	}
+	header, err := d.read(1, false)
+	if err != nil {
+		return nil, d.errorAt(typeOffset, path, err)
+	}
+	elementType := header[0]
+	if elementType > typeTimestamp {
+		return nil, d.errorAt(typeOffset, path, fmt.Errorf("%w: %v", ErrUnknownType, elementType))
+	}
+
+	for i := 0; d.offset-start < limit; i++ {
+		elementPath := fmt.Sprintf("%s[%d]", path, i)
+		elementOffset := d.offset
+
+		if limit-(d.offset-start) < elementHeaderSize {
+			return nil, d.errorAt(elementOffset, elementPath, ErrOverflow)
+		}
+		lengthBytes, err := d.read(elementHeaderSize, false)
+		if err != nil {
+			return nil, d.errorAt(elementOffset, elementPath, err)
+		}
+		elementLen := int64(binary.BigEndian.Uint16(lengthBytes))
+		if d.offset-start+elementLen > limit {
+			return nil, d.errorAt(elementOffset, elementPath, ErrOverflow)
+		}
+
+		// Элементы массива расходуют тот же лимит, что и ключи, иначе массив коротких элементов
+		// позволил бы обойти MaxKeys
+		d.keys++
+		if d.keys > d.limits.MaxKeys {
+			return nil, d.errorAt(elementOffset, elementPath, ErrMaxKeys)
+		}
+
+		element, err := d.decodeValue(elementType, elementLen, depth, elementPath, typeOffset)
+		if err != nil {
 			return nil, err
 		}
+		result = append(result, element)
+	}
 
-		switch valueType {
-		case 0x00: // Строка
-			result[string(key)] = string(value)
-		case 0x01: // Целое число
-			intValueReader := bytes.NewReader(value)
-			intValue, err := leb1282.DecodeS64(intValueReader)
-			if err != nil {
-				return nil, fmt.Errorf("Не удалось декодировать LEB128 значение для ключа %s: %v", string(key), err)
-			}
-			result[string(key)] = intValue
-		case 0x02:
-			nestedBuf := bytes.NewBuffer(value)
-			nestedResult, err := Decoder(nestedBuf)
-			if err != nil {
-				return nil, err
+	return result, nil
+}
+
+// read читает ровно n байт, при allowEOF пустой поток возвращает io.EOF
+func (d *StreamDecoder) read(n int, allowEOF bool) ([]byte, error) {
+	if d.offset+int64(n) > int64(d.limits.MaxSize) {
+		if allowEOF {
+			// Данные могли закончиться ровно на границе лимита
+			var probe [1]byte
+			if read, _ := d.reader.Read(probe[:]); read == 0 {
+				return nil, io.EOF
 			}
-			result[string(key)] = nestedResult
+		}
+		return nil, ErrMaxSize
+	}
+
+	data := make([]byte, n)
+	read, err := io.ReadFull(d.reader, data)
+	d.offset += int64(read)
+	if err != nil {
+		switch {
+		case errors.Is(err, io.EOF) && allowEOF:
+			return nil, io.EOF
+		case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+			return nil, ErrTruncated
 		default:
-			return nil, fmt.Errorf("неизвестный тип значения: %v", valueType)
+			return nil, err
 		}
 	}
 
-	return result, nil
+	return data, nil
 }
 
-func MapToStruct(data map[string]interface{}) (CameraModel, error) {
-	jsonData, err := json.Marshal(data)
-	if err != nil {
-		return CaseDataType1{}, err
+func (d *StreamDecoder) errorAt(offset int64, path string, err error) error {
+	return &DecodeError{
+		Offset: offset,
+		Path:   path,
+		Err:    err,
 	}
+}
 
-	// Попытка десериализации в каждую структуру
-	var ct1 CaseDataType1
-	if err := json.Unmarshal(jsonData, &ct1); err == nil && ct1.CameraID != "" {
-		return ct1, nil
+func decodeLEB128(value []byte) (int64, error) {
+	if !isCompleteLEB128(value) {
+		return 0, ErrBadInt
 	}
+	intValue, err := leb1282.DecodeS64(bytes.NewReader(value))
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrBadInt, err)
+	}
+	return intValue, nil
+}
 
-	var ct2 CaseDataType2
-	if err := json.Unmarshal(jsonData, &ct2); err == nil && ct2.Camera.ID != "" {
-		return ct2, nil
+// isCompleteLEB128 проверяет, что число занимает все значение: старший бит выставлен у всех байт, кроме последнего
+func isCompleteLEB128(value []byte) bool {
+	if len(value) == 0 {
+		return false
 	}
+	for _, b := range value[:len(value)-1] {
+		if b&0x80 == 0 {
+			return false
+		}
+	}
+	return value[len(value)-1]&0x80 == 0
+}
 
-	var ct3 CaseDataType3
-	if err := json.Unmarshal(jsonData, &ct3); err == nil && ct3.Camera.ID != "" {
-		return ct3, nil
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
 	}
+	return path + "." + key
+}
 
-	return CaseDataType1{}, fmt.Errorf("Не удалось определить тип структуры")
+func MapToStruct(data map[string]interface{}) (CameraModel, error) {
+	return defaultRegistry.MapToStruct(data)
 }
@@ -0,0 +1,223 @@
+package decoder
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	leb1282 "github.com/jcalabro/leb128"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func Encoder(data map[string]interface{}) (*bytes.Buffer, error) {
+	buf := new(bytes.Buffer)
+
+	if err := encodeMap(buf, reflect.ValueOf(data)); err != nil {
+		return nil, err
+	}
+
+	return buf, nil
+}
+
+func EncodeCameraModel(model CameraModel) (*bytes.Buffer, error) {
+	buf := new(bytes.Buffer)
+
+	value := reflect.ValueOf(model)
+	if value.Kind() == reflect.Pointer {
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("Неподдерживаемый тип модели камеры: %T", model)
+	}
+
+	if err := encodeStruct(buf, value); err != nil {
+		return nil, err
+	}
+
+	return buf, nil
+}
+
+func AddPayloadPrefix(buf *bytes.Buffer) ([]byte, error) {
+	// Заголовок старых камер (VersionLegacy): 2 байта с длиной тела
+	if buf.Len() > math.MaxUint16 {
+		return nil, fmt.Errorf("Размер данных превышает допустимый: %d байт", buf.Len())
+	}
+
+	payload := make([]byte, 2, buf.Len()+2)
+	binary.BigEndian.PutUint16(payload, uint16(buf.Len()))
+
+	return append(payload, buf.Bytes()...), nil
+}
+
+func encodeMap(buf *bytes.Buffer, value reflect.Value) error {
+	// Ключи сортируются, чтобы результат кодирования был детерминированным
+	keys := make([]string, 0, value.Len())
+	for _, key := range value.MapKeys() {
+		keys = append(keys, key.String())
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if err := encodeField(buf, key, value.MapIndex(reflect.ValueOf(key))); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func encodeStruct(buf *bytes.Buffer, value reflect.Value) error {
+	// Поля кодируются в порядке объявления, ключом служит json тег
+	valueType := value.Type()
+	for i := 0; i < valueType.NumField(); i++ {
+		field := valueType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		key := strings.Split(field.Tag.Get("json"), ",")[0]
+		if key == "-" {
+			continue
+		}
+		if key == "" {
+			key = field.Name
+		}
+
+		if err := encodeField(buf, key, value.Field(i)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func encodeField(buf *bytes.Buffer, key string, value reflect.Value) error {
+	valueType, valueBytes, err := encodeValue(key, value)
+	if err != nil {
+		return err
+	}
+
+	keyBytes := []byte(key)
+	if len(keyBytes) > math.MaxUint16 {
+		return fmt.Errorf("Длина ключа %s превышает допустимую", key)
+	}
+	if len(valueBytes) > math.MaxUint16 {
+		return fmt.Errorf("Длина значения для ключа %s превышает допустимую", key)
+	}
+
+	// Длина ключа (2 байта)
+	_ = binary.Write(buf, binary.BigEndian, uint16(len(keyBytes)))
+	// Длина значения (2 байта)
+	_ = binary.Write(buf, binary.BigEndian, uint16(len(valueBytes)))
+	// Тип значения (1 байт)
+	buf.WriteByte(valueType)
+	// Ключ
+	buf.Write(keyBytes)
+	// Значение
+	buf.Write(valueBytes)
+
+	return nil
+}
+
+// encodeValue возвращает тип и байтовое представление значения, key используется в ошибках
+func encodeValue(key string, value reflect.Value) (uint8, []byte, error) {
+	for value.Kind() == reflect.Interface || value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return 0, nil, fmt.Errorf("Пустое значение для ключа %s", key)
+		}
+		value = value.Elem()
+	}
+
+	if value.Type() == timeType {
+		return typeTimestamp, leb1282.EncodeS64(value.Interface().(time.Time).UnixMilli()), nil
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		return typeString, []byte(value.String()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return typeInt, leb1282.EncodeS64(value.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if value.Uint() > math.MaxInt64 {
+			return 0, nil, fmt.Errorf("Значение для ключа %s не помещается в LEB128: %d", key, value.Uint())
+		}
+		return typeInt, leb1282.EncodeS64(int64(value.Uint())), nil
+	case reflect.Float32, reflect.Float64:
+		valueBytes := make([]byte, 8)
+		binary.BigEndian.PutUint64(valueBytes, math.Float64bits(value.Float()))
+		return typeFloat, valueBytes, nil
+	case reflect.Bool:
+		if value.Bool() {
+			return typeBool, []byte{1}, nil
+		}
+		return typeBool, []byte{0}, nil
+	case reflect.Slice, reflect.Array:
+		if value.Type().Elem().Kind() == reflect.Uint8 {
+			valueBytes := make([]byte, value.Len())
+			reflect.Copy(reflect.ValueOf(valueBytes), value)
+			return typeBytes, valueBytes, nil
+		}
+		valueBytes, err := encodeArray(key, value)
+		if err != nil {
+			return 0, nil, err
+		}
+		return typeArray, valueBytes, nil
+	case reflect.Map:
+		if value.Type().Key().Kind() != reflect.String {
+			return 0, nil, fmt.Errorf("Ключи вложенной структуры %s должны быть строками", key)
+		}
+		nestedBuf := new(bytes.Buffer)
+		if err := encodeMap(nestedBuf, value); err != nil {
+			return 0, nil, err
+		}
+		return typeMap, nestedBuf.Bytes(), nil
+	case reflect.Struct:
+		nestedBuf := new(bytes.Buffer)
+		if err := encodeStruct(nestedBuf, value); err != nil {
+			return 0, nil, err
+		}
+		return typeMap, nestedBuf.Bytes(), nil
+	default:
+		return 0, nil, fmt.Errorf("Неподдерживаемый тип значения для ключа %s: %s", key, value.Type())
+	}
+}
+
+// encodeArray кодирует однородный массив: тип элементов (1 байт), затем длина (2 байта) и значение каждого элемента
+func encodeArray(key string, value reflect.Value) ([]byte, error) {
+	buf := new(bytes.Buffer)
+
+	// Тип пустого массива не важен, по умолчанию - строка
+	elementType := typeString
+	for i := 0; i < value.Len(); i++ {
+		elementKey := fmt.Sprintf("%s[%d]", key, i)
+
+		valueType, valueBytes, err := encodeValue(elementKey, value.Index(i))
+		if err != nil {
+			return nil, err
+		}
+
+		if i == 0 {
+			elementType = valueType
+			buf.WriteByte(elementType)
+		} else if valueType != elementType {
+			return nil, fmt.Errorf("Массив %s должен содержать элементы одного типа", key)
+		}
+
+		if len(valueBytes) > math.MaxUint16 {
+			return nil, fmt.Errorf("Длина значения для ключа %s превышает допустимую", elementKey)
+		}
+		_ = binary.Write(buf, binary.BigEndian, uint16(len(valueBytes)))
+		buf.Write(valueBytes)
+	}
+
+	if value.Len() == 0 {
+		buf.WriteByte(elementType)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,108 @@
+package decoder
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+)
+
+// Способы передачи данных камеры
+const (
+	EncodingBits   = "bits"
+	EncodingHex    = "hex"
+	EncodingBase64 = "base64"
+	EncodingRaw    = "raw"
+)
+
+var (
+	ErrUnknownEncoding = errors.New("неизвестный способ кодирования данных")
+	ErrBadBitString    = errors.New("битовая строка некорректна")
+	ErrBadHex          = errors.New("hex строка некорректна")
+	ErrBadBase64       = errors.New("base64 строка некорректна")
+)
+
+// contentTypeEncodings сопоставляет Content-Type части с данными способ кодирования
+var contentTypeEncodings = map[string]string{
+	"application/octet-stream": EncodingRaw,
+	"text/x-bits":              EncodingBits,
+	"text/x-hex":               EncodingHex,
+	"application/x-hex":        EncodingHex,
+	"text/x-base64":            EncodingBase64,
+	"application/x-base64":     EncodingBase64,
+}
+
+// EncodingByContentType определяет способ кодирования по Content-Type, пустая строка - если он не известен
+func EncodingByContentType(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+	return contentTypeEncodings[mediaType]
+}
+
+// extensionEncodings сопоставляет расширению файла с данными способ кодирования
+var extensionEncodings = map[string]string{
+	".bin":    EncodingRaw,
+	".hex":    EncodingHex,
+	".b64":    EncodingBase64,
+	".base64": EncodingBase64,
+	".bits":   EncodingBits,
+	".txt":    EncodingBits,
+}
+
+// EncodingByExtension определяет способ кодирования по расширению файла, пустая строка - если он не известен
+func EncodingByExtension(ext string) string {
+	return extensionEncodings[strings.ToLower(ext)]
+}
+
+// DecodeEncoding преобразует данные, переданные указанным способом, в байты
+func DecodeEncoding(data []byte, encoding string) ([]byte, error) {
+	switch strings.ToLower(encoding) {
+	case EncodingRaw:
+		return data, nil
+	case EncodingBits:
+		return decodeBits(strings.TrimSpace(string(data)))
+	case EncodingHex:
+		result, err := hex.DecodeString(strings.TrimSpace(string(data)))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrBadHex, err)
+		}
+		return result, nil
+	case EncodingBase64:
+		text := strings.TrimSpace(string(data))
+		result, err := base64.StdEncoding.DecodeString(text)
+		if err != nil {
+			// Допускается также URL-safe алфавит и отсутствие выравнивания
+			result, err = base64.RawURLEncoding.DecodeString(strings.TrimRight(text, "="))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrBadBase64, err)
+		}
+		return result, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEncoding, encoding)
+	}
+}
+
+// decodeBits преобразует строку из символов '0' и '1', длина которой кратна 8
+func decodeBits(bitString string) ([]byte, error) {
+	if len(bitString)%8 != 0 {
+		return nil, fmt.Errorf("%w: длина %d не кратна 8", ErrBadBitString, len(bitString))
+	}
+
+	result := make([]byte, len(bitString)/8)
+	for i := 0; i < len(bitString); i++ {
+		switch bitString[i] {
+		case '0':
+		case '1':
+			result[i/8] |= 1 << (7 - i%8)
+		default:
+			return nil, fmt.Errorf("%w: недопустимый символ %q на позиции %d", ErrBadBitString, bitString[i], i)
+		}
+	}
+
+	return result, nil
+}
@@ -3,6 +3,8 @@ package decoder
 import (
 	"fmt"
 	"github.com/gl1n0m3c/IT_LAB_INIT/internal/models"
+	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/plates"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -67,6 +69,15 @@ type CaseDataType3 struct {
 	Datetime  int           `json:"datetime"`
 }
 
+type CaseDataType4 struct {
+	Plates    []string      `json:"plates"`
+	Camera    CameraInfo    `json:"camera"`
+	Violation ViolationInfo `json:"violation"`
+	Speed     float64       `json:"speed"`
+	Skill     int           `json:"skill"`
+	Datetime  time.Time     `json:"datetime"`
+}
+
 func (c CaseDataType1) CameraDataToCaseBase() (models.CaseBase, error) {
 	var caseData models.CaseBase
 
@@ -76,9 +87,13 @@ func (c CaseDataType1) CameraDataToCaseBase() (models.CaseBase, error) {
 	}
 	parsedTime = parsedTime.UTC()
 
-	charsRunes := []rune(c.TransportChars)
+	transport, err := plates.FromParts(c.TransportChars, c.TransportNumbers, c.TransportRegion)
+	if err != nil {
+		return models.CaseBase{}, fmt.Errorf("Некорректный номер транспорта: %w", err)
+	}
+
 	caseData.CameraID = c.CameraID
-	caseData.Transport = string(charsRunes[:1]) + c.TransportNumbers + string(charsRunes[1:]) + c.TransportRegion
+	caseData.Transport = transport
 	caseData.ViolationID = c.ViolationID
 	caseData.ViolationValue = c.ViolationValue
 	caseData.Level = c.SkillValue
@@ -109,9 +124,13 @@ func (c CaseDataType2) CameraDataToCaseBase() (models.CaseBase, error) {
 	}
 	parsedTime = parsedTime.UTC()
 
-	charsRunes := []rune(c.Transport.Chars)
+	transport, err := plates.FromParts(c.Transport.Chars, c.Transport.Numbers, c.Transport.Region)
+	if err != nil {
+		return models.CaseBase{}, fmt.Errorf("Некорректный номер транспорта: %w", err)
+	}
+
 	caseData.CameraID = c.Camera.ID
-	caseData.Transport = string(charsRunes[:1]) + c.Transport.Numbers + string(charsRunes[1:]) + c.Transport.Region
+	caseData.Transport = transport
 	caseData.ViolationID = c.Violation.ID
 	caseData.ViolationValue = c.Violation.Value
 	caseData.Level = c.Skill.Value
@@ -126,8 +145,13 @@ func (c CaseDataType3) CameraDataToCaseBase() (models.CaseBase, error) {
 
 	parsedTime := time.Unix(int64(c.Datetime), 0).UTC()
 
+	transport, err := plates.Normalize(c.Transport)
+	if err != nil {
+		return models.CaseBase{}, fmt.Errorf("Некорректный номер транспорта: %w", err)
+	}
+
 	caseData.CameraID = c.Camera.ID
-	caseData.Transport = c.Transport
+	caseData.Transport = transport
 	caseData.ViolationID = c.Violation.ID
 	caseData.ViolationValue = c.Violation.Value
 	caseData.Level = c.Skill
@@ -136,3 +160,41 @@ func (c CaseDataType3) CameraDataToCaseBase() (models.CaseBase, error) {
 
 	return caseData, nil
 }
+
+func (c CaseDataType4) CameraDataToCaseBase() (models.CaseBase, error) {
+	var caseData models.CaseBase
+
+	// Варианты номера отсортированы камерой по убыванию уверенности, берется первый корректный
+	if len(c.Plates) == 0 {
+		return models.CaseBase{}, fmt.Errorf("Отсутствуют варианты номера транспорта")
+	}
+
+	var transport string
+	var firstErr error
+	for _, plate := range c.Plates {
+		normalized, err := plates.Normalize(plate)
+		if err == nil {
+			transport = normalized
+			break
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	if transport == "" {
+		return models.CaseBase{}, fmt.Errorf("Некорректный номер транспорта: %w", firstErr)
+	}
+
+	caseData.CameraID = c.Camera.ID
+	caseData.Transport = transport
+	caseData.ViolationID = c.Violation.ID
+	caseData.ViolationValue = c.Violation.Value
+	if caseData.ViolationValue == "" {
+		caseData.ViolationValue = strconv.FormatFloat(c.Speed, 'f', -1, 64)
+	}
+	caseData.Level = c.Skill
+	caseData.CurrentLevel = c.Skill
+	caseData.Datetime = c.Datetime.UTC()
+
+	return caseData, nil
+}
@@ -0,0 +1,182 @@
+package decoder
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"math"
+)
+
+// Заголовок данных камеры: версия протокола (1 байт) и флаги (1 байт)
+const headerSize = 2
+
+// MaxPayloadSize - максимальный размер данных камеры вместе с заголовком и контрольной суммой
+const MaxPayloadSize = headerSize + math.MaxUint16 + crc32.Size
+
+const (
+	// VersionLegacy - исторический формат, в котором 2 байта заголовка содержат длину тела
+	VersionLegacy uint8 = 0x00
+	Version1      uint8 = 0x01
+)
+
+const (
+	// FlagCRC32 - тело завершается CRC32 (IEEE, 4 байта) от заголовка и тела
+	FlagCRC32 uint8 = 1 << 0
+	// FlagCRC16 - тело завершается CRC16 (CCITT-FALSE, 2 байта) от заголовка и тела
+	FlagCRC16 uint8 = 1 << 1
+
+	knownFlags = FlagCRC32 | FlagCRC16
+)
+
+var (
+	ErrShortPayload       = errors.New("данные короче заголовка")
+	ErrUnsupportedVersion = errors.New("неподдерживаемая версия протокола")
+	ErrUnknownFlags       = errors.New("неизвестные флаги заголовка")
+	ErrLengthMismatch     = errors.New("длина в заголовке не совпадает с длиной данных")
+	ErrChecksum           = errors.New("контрольная сумма не совпадает")
+)
+
+type Header struct {
+	Version uint8 `json:"version"`
+	Flags   uint8 `json:"flags"`
+}
+
+// payloadDecoders сопоставляет версии протокола декодеры тела
+var payloadDecoders = map[uint8]func(body []byte, limits Limits) (map[string]interface{}, error){
+	VersionLegacy: decodeTLV,
+	Version1:      decodeTLV,
+}
+
+// DecodePayload проверяет заголовок и контрольную сумму и декодирует тело в соответствии с версией.
+// Смещения в ошибках отсчитываются от начала данных вместе с заголовком
+func DecodePayload(payload []byte, limits Limits) (map[string]interface{}, error) {
+	header, body, err := ParsePayload(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	decode, ok := payloadDecoders[header.Version]
+	if !ok {
+		return nil, &DecodeError{Offset: 0, Err: fmt.Errorf("%w: %d", ErrUnsupportedVersion, header.Version)}
+	}
+
+	result, err := decode(body, limits)
+	if err != nil {
+		var decodeErr *DecodeError
+		if errors.As(err, &decodeErr) {
+			decodeErr.Offset += headerSize
+		}
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ParsePayload отделяет заголовок и контрольную сумму от тела.
+// Старые камеры передают в заголовке длину тела, такие данные распознаются по совпадению длины
+func ParsePayload(payload []byte) (Header, []byte, error) {
+	if len(payload) < headerSize {
+		return Header{}, nil, &DecodeError{Offset: 0, Err: ErrShortPayload}
+	}
+
+	header := Header{Version: payload[0], Flags: payload[1]}
+	legacy := int(binary.BigEndian.Uint16(payload[:headerSize])) == len(payload)-headerSize
+
+	if header.Version == Version1 {
+		body, err := parseVersion1(header, payload)
+		if err == nil {
+			return header, body, nil
+		}
+		// Заголовок старой камеры с длиной тела от 256 до 511 байт совпадает с заголовком версии 1
+		if !legacy {
+			return Header{}, nil, err
+		}
+	}
+
+	if legacy {
+		return Header{Version: VersionLegacy}, payload[headerSize:], nil
+	}
+	if header.Version == VersionLegacy {
+		return Header{}, nil, &DecodeError{Offset: 0, Err: ErrLengthMismatch}
+	}
+
+	return Header{}, nil, &DecodeError{Offset: 0, Err: fmt.Errorf("%w: %d", ErrUnsupportedVersion, header.Version)}
+}
+
+func parseVersion1(header Header, payload []byte) ([]byte, error) {
+	if header.Flags&^knownFlags != 0 || header.Flags&knownFlags == knownFlags {
+		return nil, &DecodeError{Offset: 1, Err: fmt.Errorf("%w: %08b", ErrUnknownFlags, header.Flags)}
+	}
+
+	trailerSize := checksumSize(header.Flags)
+	if len(payload) < headerSize+trailerSize {
+		return nil, &DecodeError{Offset: int64(len(payload)), Err: ErrTruncated}
+	}
+
+	dataEnd := len(payload) - trailerSize
+	if !bytes.Equal(checksum(header.Flags, payload[:dataEnd]), payload[dataEnd:]) {
+		return nil, &DecodeError{Offset: int64(dataEnd), Err: ErrChecksum}
+	}
+
+	return payload[headerSize:dataEnd], nil
+}
+
+// AddPayloadHeader добавляет заголовок версии 1 и, если указано во флагах, контрольную сумму
+func AddPayloadHeader(buf *bytes.Buffer, flags uint8) ([]byte, error) {
+	if flags&^knownFlags != 0 || flags&knownFlags == knownFlags {
+		return nil, fmt.Errorf("%w: %08b", ErrUnknownFlags, flags)
+	}
+	if buf.Len() > math.MaxUint16 {
+		return nil, fmt.Errorf("Размер данных превышает допустимый: %d байт", buf.Len())
+	}
+
+	payload := make([]byte, 0, headerSize+buf.Len()+checksumSize(flags))
+	payload = append(payload, Version1, flags)
+	payload = append(payload, buf.Bytes()...)
+
+	return append(payload, checksum(flags, payload)...), nil
+}
+
+func decodeTLV(body []byte, limits Limits) (map[string]interface{}, error) {
+	return NewStreamDecoder(bytes.NewReader(body), limits).Decode()
+}
+
+func checksumSize(flags uint8) int {
+	switch {
+	case flags&FlagCRC32 != 0:
+		return crc32.Size
+	case flags&FlagCRC16 != 0:
+		return 2
+	default:
+		return 0
+	}
+}
+
+func checksum(flags uint8, data []byte) []byte {
+	switch {
+	case flags&FlagCRC32 != 0:
+		return binary.BigEndian.AppendUint32(nil, crc32.ChecksumIEEE(data))
+	case flags&FlagCRC16 != 0:
+		return binary.BigEndian.AppendUint16(nil, crc16(data))
+	default:
+		return nil
+	}
+}
+
+// crc16 считает CRC-16/CCITT-FALSE: полином 0x1021, начальное значение 0xFFFF
+func crc16(data []byte) uint16 {
+	crc := uint16(0xFFFF)
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
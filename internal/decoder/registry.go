@@ -0,0 +1,219 @@
+package decoder
+
+import (
+	"encoding/json"
+	"fmt"
+	customErrors "github.com/gl1n0m3c/IT_LAB_INIT/pkg/utils/customerr"
+	"strings"
+	"sync"
+)
+
+// FormatKey - ключ в данных камеры, которым она может явно указать свой формат
+const FormatKey = "format"
+
+const (
+	FormatType1 = "type1"
+	FormatType2 = "type2"
+	FormatType3 = "type3"
+	FormatType4 = "type4"
+)
+
+type Format struct {
+	Name   string
+	Detect func(data map[string]interface{}) bool
+	New    func(data map[string]interface{}) (CameraModel, error)
+}
+
+type Registry struct {
+	mu      sync.RWMutex
+	formats []Format
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+var defaultRegistry = NewRegistry()
+
+func init() {
+	for _, format := range []Format{
+		{
+			Name: FormatType1,
+			Detect: func(data map[string]interface{}) bool {
+				return isString(lookup(data, "camera_id"))
+			},
+			New: newCameraModel[CaseDataType1],
+		},
+		{
+			Name: FormatType2,
+			Detect: func(data map[string]interface{}) bool {
+				return isMap(lookup(data, "camera")) && isMap(lookup(data, "transport"))
+			},
+			New: newCameraModel[CaseDataType2],
+		},
+		{
+			Name: FormatType3,
+			Detect: func(data map[string]interface{}) bool {
+				return isMap(lookup(data, "camera")) && isString(lookup(data, "transport"))
+			},
+			New: newCameraModel[CaseDataType3],
+		},
+		{
+			Name: FormatType4,
+			Detect: func(data map[string]interface{}) bool {
+				return isMap(lookup(data, "camera")) && isSlice(lookup(data, "plates"))
+			},
+			New: newCameraModel[CaseDataType4],
+		},
+	} {
+		if err := defaultRegistry.Register(format); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// Register добавляет формат в реестр, используемый MapToStruct
+func Register(format Format) error {
+	return defaultRegistry.Register(format)
+}
+
+// Formats возвращает имена форматов, зарегистрированных для MapToStruct
+func Formats() []string {
+	return defaultRegistry.Formats()
+}
+
+func (r *Registry) Register(format Format) error {
+	if format.Name == "" || format.Detect == nil || format.New == nil {
+		return fmt.Errorf("Формат должен иметь имя, детектор и конструктор модели")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, registered := range r.formats {
+		if registered.Name == format.Name {
+			return fmt.Errorf("Формат %s уже зарегистрирован", format.Name)
+		}
+	}
+
+	r.formats = append(r.formats, format)
+
+	return nil
+}
+
+func (r *Registry) Formats() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.formats))
+	for _, format := range r.formats {
+		names = append(names, format.Name)
+	}
+
+	return names
+}
+
+// Build создает модель камеры указанного формата
+func (r *Registry) Build(name string, data map[string]interface{}) (CameraModel, error) {
+	var (
+		found Format
+		ok    bool
+	)
+
+	r.mu.RLock()
+	for _, format := range r.formats {
+		if format.Name == name {
+			found, ok = format, true
+			break
+		}
+	}
+	r.mu.RUnlock()
+
+	if !ok {
+		return nil, r.unknownFormatErr(fmt.Sprintf("формат %q", name))
+	}
+
+	return found.New(data)
+}
+
+// MapToStruct определяет формат по ключу FormatKey, а при его отсутствии - по детекторам
+func (r *Registry) MapToStruct(data map[string]interface{}) (CameraModel, error) {
+	if rawName, ok := data[FormatKey]; ok {
+		name, ok := rawName.(string)
+		if !ok {
+			return nil, fmt.Errorf("Значение ключа %s должно быть строкой", FormatKey)
+		}
+		return r.Build(name, data)
+	}
+
+	r.mu.RLock()
+	var matched []Format
+	for _, format := range r.formats {
+		if format.Detect(data) {
+			matched = append(matched, format)
+		}
+	}
+	r.mu.RUnlock()
+
+	switch len(matched) {
+	case 0:
+		return nil, r.unknownFormatErr("данные не подходят ни под один формат")
+	case 1:
+		return matched[0].New(data)
+	default:
+		names := make([]string, 0, len(matched))
+		for _, format := range matched {
+			names = append(names, format.Name)
+		}
+		return nil, fmt.Errorf("Данные подходят под несколько форматов (%s), укажите формат в ключе %s",
+			strings.Join(names, ", "), FormatKey)
+	}
+}
+
+func (r *Registry) unknownFormatErr(reason string) error {
+	return fmt.Errorf("%w: %s. Зарегистрированные форматы: %s",
+		customErrors.UnknownCameraFormatErr, reason, strings.Join(r.Formats(), ", "))
+}
+
+func newCameraModel[T CameraModel](data map[string]interface{}) (CameraModel, error) {
+	var model T
+
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(jsonData, &model); err != nil {
+		return nil, fmt.Errorf("Данные не соответствуют формату %T: %v", model, err)
+	}
+
+	return model, nil
+}
+
+// lookup ищет ключ без учета регистра, так же как это делает json.Unmarshal
+func lookup(data map[string]interface{}, key string) interface{} {
+	if value, ok := data[key]; ok {
+		return value
+	}
+	for k, value := range data {
+		if strings.EqualFold(k, key) {
+			return value
+		}
+	}
+	return nil
+}
+
+func isString(value interface{}) bool {
+	str, ok := value.(string)
+	return ok && str != ""
+}
+
+func isSlice(value interface{}) bool {
+	_, ok := value.([]interface{})
+	return ok
+}
+
+func isMap(value interface{}) bool {
+	_, ok := value.(map[string]interface{})
+	return ok
+}
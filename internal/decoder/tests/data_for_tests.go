@@ -23,6 +23,7 @@ var resultCase1 = models.CaseBase{
 	ViolationID:    "VIO456",
 	ViolationValue: "Speeding",
 	Level:          5,
+	CurrentLevel:   5,
 	Datetime:       time.Date(2024, time.May, 20, 22, 6, 0, 0, time.UTC),
 	PhotoUrl:       "",
 }
@@ -58,10 +59,11 @@ var caseDataType2 = decoder.CaseDataType2{
 
 var resultCase2 = models.CaseBase{
 	CameraID:       "CAM123",
-	Transport:      "А123ВС77",
+	Transport:      "A123BC77",
 	ViolationID:    "VIO456",
 	ViolationValue: "Speeding",
 	Level:          5,
+	CurrentLevel:   5,
 	Datetime:       time.Date(2023, time.April, 15, 11, 30, 45, 0, time.UTC),
 	PhotoUrl:       "",
 }
@@ -83,10 +85,11 @@ var caseDataType3 = decoder.CaseDataType3{
 
 var resultCase3 = models.CaseBase{
 	CameraID:       "CAM123",
-	Transport:      "АВС12377",
+	Transport:      "A123BC77",
 	ViolationID:    "VIO456",
 	ViolationValue: "Speeding",
 	Level:          5,
+	CurrentLevel:   5,
 	Datetime:       time.Date(2023, time.April, 15, 11, 30, 45, 0, time.UTC),
 	PhotoUrl:       "", // Предполагается, что URL фотографии не предоставлен
 }
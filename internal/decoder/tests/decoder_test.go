@@ -5,6 +5,7 @@ import (
 	"encoding/binary"
 	"fmt"
 	"github.com/gl1n0m3c/IT_LAB_INIT/internal/decoder"
+	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/plates"
 	leb1282 "github.com/jcalabro/leb128"
 	"github.com/stretchr/testify/assert"
 	"strconv"
@@ -133,3 +134,27 @@ func TestCameraType3(t *testing.T) {
 	assert.Equal(t, caseDataType3, cameraModel)
 	assert.Equal(t, resultCase3, resCase)
 }
+
+func TestCameraTransportInvalid(t *testing.T) {
+	type1 := caseDataType1
+	type1.TransportChars = ""
+	_, err := type1.CameraDataToCaseBase()
+	assert.ErrorIs(t, err, plates.ErrBadFormat)
+
+	type3 := caseDataType3
+	type3.Transport = "А123ВС00"
+	_, err = type3.CameraDataToCaseBase()
+	assert.ErrorIs(t, err, plates.ErrBadRegion)
+
+	// Берется первый корректный вариант номера
+	type4 := decoder.CaseDataType4{Plates: []string{"А12ВС77", "в456ек199"}}
+	resCase, err := type4.CameraDataToCaseBase()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "B456EK199", resCase.Transport)
+
+	type4.Plates = []string{"А12ВС77"}
+	_, err = type4.CameraDataToCaseBase()
+	assert.ErrorIs(t, err, plates.ErrBadFormat)
+}
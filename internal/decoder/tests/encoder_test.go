@@ -0,0 +1,143 @@
+package tests
+
+import (
+	"bytes"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/decoder"
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"testing"
+)
+
+func bitStringToBytes(bitString string) []byte {
+	var dataBytes []byte
+	for i := 0; i+8 <= len(bitString); i += 8 {
+		byteValue, _ := strconv.ParseUint(bitString[i:i+8], 2, 8)
+		dataBytes = append(dataBytes, byte(byteValue))
+	}
+	return dataBytes
+}
+
+func bytesWithoutPrefix(bitString string) *bytes.Buffer {
+	return bytes.NewBuffer(bitStringToBytes(bitString)[2:])
+}
+
+func TestEncoderRoundTrip(t *testing.T) {
+	testCases := []map[string]interface{}{
+		{},
+		{
+			"camera_id":   "CAM123",
+			"skill_value": int64(5),
+			"negative":    int64(-1681558245),
+			"empty":       "",
+			"unicode":     "АВС",
+		},
+		{
+			"transport": map[string]interface{}{
+				"chars":   "АВС",
+				"numbers": "123",
+				"region":  "77",
+			},
+			"datetime": map[string]interface{}{
+				"year":       int64(2023),
+				"utc_offset": "+3",
+				"nested": map[string]interface{}{
+					"deep": int64(0),
+				},
+			},
+		},
+	}
+
+	for _, data := range testCases {
+		buf, err := decoder.Encoder(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := decoder.Decoder(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, data, result)
+	}
+}
+
+func TestEncoderDeterministic(t *testing.T) {
+	data := map[string]interface{}{"b": "2", "a": "1", "c": int64(3)}
+
+	first, err := decoder.Encoder(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 10; i++ {
+		next, err := decoder.Encoder(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, first.Bytes(), next.Bytes())
+	}
+}
+
+func TestEncoderUnsupportedType(t *testing.T) {
+	_, err := decoder.Encoder(map[string]interface{}{"channel": make(chan int)})
+	assert.Error(t, err)
+
+	_, err = decoder.Encoder(map[string]interface{}{"empty": nil})
+	assert.Error(t, err)
+}
+
+func TestEncodeCameraModelRoundTrip(t *testing.T) {
+	testCases := []struct {
+		model  decoder.CameraModel
+		result interface{}
+	}{
+		{caseDataType1, resultCase1},
+		{caseDataType2, resultCase2},
+		{caseDataType3, resultCase3},
+	}
+
+	for _, tc := range testCases {
+		buf, err := decoder.EncodeCameraModel(tc.model)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		payload, err := decoder.AddPayloadPrefix(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := decoder.Decoder(bytes.NewBuffer(payload[2:]))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cameraModel, err := decoder.MapToStruct(result)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resCase, err := cameraModel.CameraDataToCaseBase()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, tc.model, cameraModel)
+		assert.Equal(t, tc.result, resCase)
+	}
+}
+
+func TestEncodeCameraModelWireFormat(t *testing.T) {
+	buf, err := decoder.EncodeCameraModel(caseDataType2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload, err := decoder.AddPayloadPrefix(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, bitStringToBytes(byteString2), payload)
+}
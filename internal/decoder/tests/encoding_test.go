@@ -0,0 +1,70 @@
+package tests
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/decoder"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDecodeEncoding(t *testing.T) {
+	payload := bitStringToBytes(byteString2)
+
+	testCases := []struct {
+		encoding string
+		data     string
+	}{
+		{decoder.EncodingBits, byteString2},
+		{decoder.EncodingHex, hex.EncodeToString(payload)},
+		{decoder.EncodingHex, "  " + hex.EncodeToString(payload) + "\n"},
+		{decoder.EncodingBase64, base64.StdEncoding.EncodeToString(payload)},
+		{decoder.EncodingBase64, base64.RawURLEncoding.EncodeToString(payload)},
+		{"HEX", hex.EncodeToString(payload)},
+		{decoder.EncodingRaw, string(payload)},
+	}
+
+	for _, tc := range testCases {
+		result, err := decoder.DecodeEncoding([]byte(tc.data), tc.encoding)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, payload, result, tc.encoding)
+	}
+}
+
+func TestDecodeEncodingBadBitString(t *testing.T) {
+	// Хвост, не кратный 8 битам, раньше молча отбрасывался
+	_, err := decoder.DecodeEncoding([]byte(byteString2+"101"), decoder.EncodingBits)
+	assert.ErrorIs(t, err, decoder.ErrBadBitString)
+
+	_, err = decoder.DecodeEncoding([]byte("0000000200000000"), decoder.EncodingBits)
+	assert.ErrorIs(t, err, decoder.ErrBadBitString)
+}
+
+func TestDecodeEncodingErrors(t *testing.T) {
+	_, err := decoder.DecodeEncoding([]byte("abc"), decoder.EncodingHex)
+	assert.ErrorIs(t, err, decoder.ErrBadHex)
+
+	_, err = decoder.DecodeEncoding([]byte("@@@@"), decoder.EncodingBase64)
+	assert.ErrorIs(t, err, decoder.ErrBadBase64)
+
+	_, err = decoder.DecodeEncoding([]byte("00"), "octal")
+	assert.ErrorIs(t, err, decoder.ErrUnknownEncoding)
+}
+
+func TestEncodingByContentType(t *testing.T) {
+	assert.Equal(t, decoder.EncodingRaw, decoder.EncodingByContentType("application/octet-stream"))
+	assert.Equal(t, decoder.EncodingHex, decoder.EncodingByContentType("text/x-hex; charset=utf-8"))
+	assert.Equal(t, decoder.EncodingBase64, decoder.EncodingByContentType("application/x-base64"))
+	assert.Equal(t, "", decoder.EncodingByContentType("image/png"))
+	assert.Equal(t, "", decoder.EncodingByContentType(""))
+}
+
+func TestEncodingByExtension(t *testing.T) {
+	assert.Equal(t, decoder.EncodingRaw, decoder.EncodingByExtension(".bin"))
+	assert.Equal(t, decoder.EncodingHex, decoder.EncodingByExtension(".HEX"))
+	assert.Equal(t, decoder.EncodingBase64, decoder.EncodingByExtension(".b64"))
+	assert.Equal(t, decoder.EncodingBits, decoder.EncodingByExtension(".txt"))
+	assert.Equal(t, "", decoder.EncodingByExtension(".jpg"))
+}
@@ -0,0 +1,117 @@
+package tests
+
+import (
+	"bytes"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/decoder"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// Запуск: go test ./internal/decoder/tests -run '^$' -fuzz FuzzDecoder -fuzztime 1m
+// Найденные входы сохраняются в testdata/fuzz и проверяются обычным go test
+
+// addGoldenSeeds добавляет в начальный корпус данные из golden корпуса
+func addGoldenSeeds(f *testing.F) {
+	entries, err := os.ReadDir(goldenDir)
+	if err != nil {
+		f.Fatal(err)
+	}
+
+	for _, entry := range entries {
+		encoding := decoder.EncodingByExtension(filepath.Ext(entry.Name()))
+		if entry.IsDir() || encoding == "" {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(goldenDir, entry.Name()))
+		if err != nil {
+			f.Fatal(err)
+		}
+		payload, err := decoder.DecodeEncoding(data, encoding)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(payload)
+	}
+}
+
+// FuzzDecoder проверяет, что декодер не паникует на произвольных данных, а успешно декодированные данные
+// после повторного кодирования декодируются в то же самое представление
+func FuzzDecoder(f *testing.F) {
+	addGoldenSeeds(f)
+	f.Add([]byte{})
+	f.Add([]byte{0x00, 0x05, 0x00, 0x01, 0x00, 0x00, 0x02})
+
+	f.Fuzz(func(t *testing.T, payload []byte) {
+		result, err := decoder.DecodePayload(payload, decoder.DefaultLimits)
+		if err != nil {
+			return
+		}
+
+		encoded, err := decoder.Encoder(result)
+		if err != nil {
+			t.Fatalf("декодированные данные не кодируются обратно: %v", err)
+		}
+
+		decoded, err := decoder.Decoder(bytes.NewBuffer(encoded.Bytes()))
+		if err != nil {
+			t.Fatalf("повторно закодированные данные не декодируются: %v", err)
+		}
+
+		reencoded, err := decoder.Encoder(decoded)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(encoded.Bytes(), reencoded.Bytes()) {
+			t.Fatalf("кодирование нестабильно:\n%x\n%x", encoded.Bytes(), reencoded.Bytes())
+		}
+	})
+}
+
+// FuzzMapToStruct собирает данные всех форматов из произвольных значений полей
+// и проверяет, что определение формата и построение случая не паникуют
+func FuzzMapToStruct(f *testing.F) {
+	f.Add("ABC", "123", "77", "2024-05-20T22:06:00+00:00", "+3", int64(5), int64(1681558245))
+	f.Add("АВС", "000", "00", "", "", int64(-1), int64(0))
+	f.Add("", "", "", "2024-05-20", "+300", int64(1<<62), int64(-1<<62))
+	f.Add("а", "1", "7777", "not a time", "-", int64(0), int64(1<<40))
+
+	f.Fuzz(func(t *testing.T, chars, numbers, region, datetime, utcOffset string, skill, unix int64) {
+		camera := map[string]interface{}{"id": "CAM123"}
+		violation := map[string]interface{}{"id": "VIO456", "value": "Speeding"}
+
+		dataSets := []map[string]interface{}{
+			{
+				"transport_chars": chars, "transport_numbers": numbers, "transport_region": region,
+				"camera_id": "CAM123", "violation_id": "VIO456", "violation_value": "Speeding",
+				"skill_value": skill, "datetime": datetime,
+			},
+			{
+				"transport": map[string]interface{}{"chars": chars, "numbers": numbers, "region": region},
+				"camera":    camera, "violation": violation, "skill": map[string]interface{}{"value": skill},
+				"datetime": map[string]interface{}{
+					"year": unix, "month": skill, "day": skill, "hour": skill, "minute": skill, "seconds": skill,
+					"utc_offset": utcOffset,
+				},
+			},
+			{
+				"transport": chars + numbers + region, "camera": camera, "violation": violation,
+				"skill": skill, "datetime": unix,
+			},
+			{
+				"plates": []interface{}{chars, numbers + region}, "camera": camera, "violation": violation,
+				"speed": float64(skill) / 3, "skill": skill, "datetime": time.UnixMilli(unix),
+			},
+		}
+
+		for _, data := range dataSets {
+			model, err := decoder.MapToStruct(data)
+			if err != nil {
+				continue
+			}
+			_, _ = model.CameraDataToCaseBase()
+		}
+	})
+}
@@ -0,0 +1,101 @@
+package tests
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/decoder"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/models"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Ожидаемые результаты перезаписываются командой go test ./internal/decoder/tests -run TestGoldenCorpus -update
+var updateGolden = flag.Bool("update", false, "перезаписать ожидаемые результаты golden корпуса")
+
+const goldenDir = "testdata/golden"
+
+// goldenResult - ожидаемый результат обработки данных камеры: случай или текст ошибки
+type goldenResult struct {
+	Format string           `json:"format,omitempty"`
+	Case   *models.CaseBase `json:"case,omitempty"`
+	Error  string           `json:"error,omitempty"`
+}
+
+// decodeGolden проходит те же этапы, что и создание случая: раскодирование, заголовок, TLV, формат, случай
+func decodeGolden(data []byte, encoding string) goldenResult {
+	payload, err := decoder.DecodeEncoding(data, encoding)
+	if err != nil {
+		return goldenResult{Error: err.Error()}
+	}
+
+	tree, err := decoder.DecodePayload(payload, decoder.DefaultLimits)
+	if err != nil {
+		return goldenResult{Error: err.Error()}
+	}
+
+	model, err := decoder.MapToStruct(tree)
+	if err != nil {
+		return goldenResult{Error: err.Error()}
+	}
+	format := strings.TrimPrefix(fmt.Sprintf("%T", model), "decoder.")
+
+	caseData, err := model.CameraDataToCaseBase()
+	if err != nil {
+		return goldenResult{Format: format, Error: err.Error()}
+	}
+
+	return goldenResult{Format: format, Case: &caseData}
+}
+
+// TestGoldenCorpus сверяет результат обработки каждого файла корпуса с соседним файлом .json.
+// Способ кодирования определяется по расширению файла
+func TestGoldenCorpus(t *testing.T) {
+	entries, err := os.ReadDir(goldenDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var checked int
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		encoding := decoder.EncodingByExtension(ext)
+		if entry.IsDir() || encoding == "" {
+			continue
+		}
+		checked++
+
+		name := strings.TrimSuffix(entry.Name(), ext)
+		t.Run(name, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join(goldenDir, entry.Name()))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			actual, err := json.MarshalIndent(decodeGolden(data, encoding), "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			actual = append(actual, '\n')
+
+			expectedPath := filepath.Join(goldenDir, name+".json")
+			if *updateGolden {
+				if err := os.WriteFile(expectedPath, actual, 0644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			expected, err := os.ReadFile(expectedPath)
+			if err != nil {
+				t.Fatalf("нет ожидаемого результата %s, запустите тест с -update: %v", expectedPath, err)
+			}
+			assert.JSONEq(t, string(expected), string(actual))
+		})
+	}
+
+	assert.NotZero(t, checked, "golden корпус пуст")
+}
@@ -0,0 +1,138 @@
+package tests
+
+import (
+	"bytes"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/decoder"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestDecodePayloadLegacy(t *testing.T) {
+	for _, byteString := range []string{byteString2, byteString3} {
+		expected, err := decoder.Decoder(bytesWithoutPrefix(byteString))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := decoder.DecodePayload(bitStringToBytes(byteString), decoder.Limits{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, expected, result)
+	}
+}
+
+func TestDecodePayloadLegacyLongBody(t *testing.T) {
+	// Длина тела 257 байт дает заголовок 0x0101, совпадающий с версией 1 и флагом CRC32
+	var buf bytes.Buffer
+	writeRecord(&buf, "camera_id", 0x00, []byte(strings.Repeat("x", 257-5-len("camera_id"))))
+
+	payload, err := decoder.AddPayloadPrefix(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []byte{0x01, 0x01}, payload[:2])
+
+	header, _, err := decoder.ParsePayload(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, decoder.VersionLegacy, header.Version)
+}
+
+func TestDecodePayloadVersion1(t *testing.T) {
+	data := map[string]interface{}{"camera_id": "CAM123", "skill_value": int64(5)}
+
+	for _, flags := range []uint8{0, decoder.FlagCRC32, decoder.FlagCRC16} {
+		buf, err := decoder.Encoder(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		payload, err := decoder.AddPayloadHeader(buf, flags)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		header, _, err := decoder.ParsePayload(payload)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, decoder.Header{Version: decoder.Version1, Flags: flags}, header)
+
+		result, err := decoder.DecodePayload(payload, decoder.Limits{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, data, result)
+	}
+}
+
+func TestDecodePayloadChecksumMismatch(t *testing.T) {
+	for _, flags := range []uint8{decoder.FlagCRC32, decoder.FlagCRC16} {
+		buf, err := decoder.Encoder(map[string]interface{}{"camera_id": "CAM123"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		payload, err := decoder.AddPayloadHeader(buf, flags)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Повреждение одного бита в теле
+		payload[len(payload)/2] ^= 0x01
+
+		_, err = decoder.DecodePayload(payload, decoder.Limits{})
+		assert.ErrorIs(t, err, decoder.ErrChecksum)
+
+		// Повреждение флагов тоже обнаруживается
+		payload[len(payload)/2] ^= 0x01
+		payload[1] ^= decoder.FlagCRC32 | decoder.FlagCRC16
+		_, err = decoder.DecodePayload(payload, decoder.Limits{})
+		assert.ErrorIs(t, err, decoder.ErrChecksum)
+	}
+}
+
+func TestDecodePayloadBadHeader(t *testing.T) {
+	_, err := decoder.DecodePayload([]byte{0x01}, decoder.Limits{})
+	assert.ErrorIs(t, err, decoder.ErrShortPayload)
+
+	_, err = decoder.DecodePayload([]byte{0x01, 0x80, 0x00, 0x00}, decoder.Limits{})
+	assert.ErrorIs(t, err, decoder.ErrUnknownFlags)
+	assert.Equal(t, int64(1), decodeErrorOf(t, err).Offset)
+
+	_, err = decoder.DecodePayload([]byte{0x01, decoder.FlagCRC32 | decoder.FlagCRC16}, decoder.Limits{})
+	assert.ErrorIs(t, err, decoder.ErrUnknownFlags)
+
+	_, err = decoder.DecodePayload([]byte{0x01, decoder.FlagCRC32, 0x00}, decoder.Limits{})
+	assert.ErrorIs(t, err, decoder.ErrTruncated)
+
+	_, err = decoder.DecodePayload([]byte{0x07, 0x00, 0x00}, decoder.Limits{})
+	assert.ErrorIs(t, err, decoder.ErrUnsupportedVersion)
+
+	// Обрезанные данные старой камеры
+	payload := bitStringToBytes(byteString2)
+	_, err = decoder.DecodePayload(payload[:len(payload)-1], decoder.Limits{})
+	assert.ErrorIs(t, err, decoder.ErrLengthMismatch)
+
+	_, err = decoder.AddPayloadHeader(new(bytes.Buffer), 0x80)
+	assert.ErrorIs(t, err, decoder.ErrUnknownFlags)
+}
+
+func TestDecodePayloadErrorOffset(t *testing.T) {
+	var buf bytes.Buffer
+	writeRecord(&buf, "skill", 0x01, []byte{0x80})
+
+	payload, err := decoder.AddPayloadHeader(&buf, decoder.FlagCRC16)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Смещение отсчитывается от начала данных вместе с заголовком
+	_, err = decoder.DecodePayload(payload, decoder.Limits{})
+	assert.ErrorIs(t, err, decoder.ErrBadInt)
+	assert.Equal(t, int64(2+5+len("skill")), decodeErrorOf(t, err).Offset)
+}
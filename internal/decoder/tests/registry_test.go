@@ -0,0 +1,121 @@
+package tests
+
+import (
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/decoder"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/models"
+	customErrors "github.com/gl1n0m3c/IT_LAB_INIT/pkg/utils/customerr"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type customCamera struct {
+	Vendor string `json:"vendor"`
+}
+
+func (c customCamera) CameraDataToCaseBase() (models.CaseBase, error) {
+	return models.CaseBase{CameraID: c.Vendor}, nil
+}
+
+func newCustomCamera(data map[string]interface{}) (decoder.CameraModel, error) {
+	vendor, _ := data["vendor"].(string)
+	return customCamera{Vendor: vendor}, nil
+}
+
+func TestRegistryDefaultFormats(t *testing.T) {
+	assert.Equal(t, []string{decoder.FormatType1, decoder.FormatType2, decoder.FormatType3, decoder.FormatType4}, decoder.Formats())
+}
+
+func TestRegistryExplicitFormat(t *testing.T) {
+	data := map[string]interface{}{
+		decoder.FormatKey: decoder.FormatType3,
+		"transport":       "АВС12377",
+		"camera":          map[string]interface{}{"id": "CAM123"},
+		"violation":       map[string]interface{}{"id": "VIO456", "value": "Speeding"},
+		"skill":           int64(5),
+		"datetime":        int64(1681558245),
+	}
+
+	cameraModel, err := decoder.MapToStruct(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, caseDataType3, cameraModel)
+}
+
+func TestRegistryUnknownFormat(t *testing.T) {
+	_, err := decoder.MapToStruct(map[string]interface{}{decoder.FormatKey: "type42"})
+	assert.ErrorIs(t, err, customErrors.UnknownCameraFormatErr)
+	assert.Contains(t, err.Error(), "type42")
+	for _, name := range decoder.Formats() {
+		assert.Contains(t, err.Error(), name)
+	}
+
+	_, err = decoder.MapToStruct(map[string]interface{}{"something": "else"})
+	assert.ErrorIs(t, err, customErrors.UnknownCameraFormatErr)
+
+	_, err = decoder.MapToStruct(map[string]interface{}{decoder.FormatKey: int64(1)})
+	assert.Error(t, err)
+}
+
+func TestRegistryCaseInsensitiveDetection(t *testing.T) {
+	result, err := decoder.Decoder(bytesWithoutPrefix(byteString3))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cameraModel, err := decoder.MapToStruct(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.IsType(t, decoder.CaseDataType3{}, cameraModel)
+}
+
+func TestRegistryCustomFormat(t *testing.T) {
+	registry := decoder.NewRegistry()
+
+	format := decoder.Format{
+		Name: "vendor",
+		Detect: func(data map[string]interface{}) bool {
+			_, ok := data["vendor"]
+			return ok
+		},
+		New: newCustomCamera,
+	}
+
+	assert.NoError(t, registry.Register(format))
+	assert.Error(t, registry.Register(format))
+	assert.Error(t, registry.Register(decoder.Format{Name: "broken"}))
+
+	cameraModel, err := registry.MapToStruct(map[string]interface{}{"vendor": "ACME"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, customCamera{Vendor: "ACME"}, cameraModel)
+
+	cameraModel, err = registry.Build("vendor", map[string]interface{}{"vendor": "ACME"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, customCamera{Vendor: "ACME"}, cameraModel)
+}
+
+func TestRegistryAmbiguousDetection(t *testing.T) {
+	registry := decoder.NewRegistry()
+
+	always := func(data map[string]interface{}) bool { return true }
+	assert.NoError(t, registry.Register(decoder.Format{Name: "first", Detect: always, New: newCustomCamera}))
+	assert.NoError(t, registry.Register(decoder.Format{Name: "second", Detect: always, New: newCustomCamera}))
+
+	_, err := registry.MapToStruct(map[string]interface{}{"vendor": "ACME"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "first")
+	assert.Contains(t, err.Error(), "second")
+
+	cameraModel, err := registry.MapToStruct(map[string]interface{}{decoder.FormatKey: "second", "vendor": "ACME"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, customCamera{Vendor: "ACME"}, cameraModel)
+}
@@ -0,0 +1,159 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/binary"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/decoder"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"testing/iotest"
+)
+
+func writeRecord(buf *bytes.Buffer, key string, valueType uint8, value []byte) {
+	_ = binary.Write(buf, binary.BigEndian, uint16(len(key)))
+	_ = binary.Write(buf, binary.BigEndian, uint16(len(value)))
+	buf.WriteByte(valueType)
+	buf.WriteString(key)
+	buf.Write(value)
+}
+
+func decodeErrorOf(t *testing.T, err error) *decoder.DecodeError {
+	t.Helper()
+
+	decodeErr, ok := err.(*decoder.DecodeError)
+	if !ok {
+		t.Fatalf("expected *decoder.DecodeError, got %T: %v", err, err)
+	}
+	return decodeErr
+}
+
+func TestStreamDecoderOneByteReader(t *testing.T) {
+	payload := bitStringToBytes(byteString2)[2:]
+
+	expected, err := decoder.Decoder(bytes.NewBuffer(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := decoder.NewStreamDecoder(iotest.OneByteReader(bytes.NewReader(payload)), decoder.Limits{}).Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, expected, result)
+}
+
+func TestStreamDecoderTruncated(t *testing.T) {
+	var buf bytes.Buffer
+	writeRecord(&buf, "camera_id", 0x00, []byte("CAM123"))
+	payload := buf.Bytes()
+
+	// Обрыв внутри значения
+	_, err := decoder.Decoder(bytes.NewBuffer(payload[:len(payload)-2]))
+	decodeErr := decodeErrorOf(t, err)
+	assert.ErrorIs(t, err, decoder.ErrTruncated)
+	assert.Equal(t, int64(5+len("camera_id")), decodeErr.Offset)
+	assert.Equal(t, "camera_id", decodeErr.Path)
+
+	// Обрыв внутри заголовка следующей записи
+	_, err = decoder.Decoder(bytes.NewBuffer(append(append([]byte{}, payload...), 0x00, 0x01)))
+	decodeErr = decodeErrorOf(t, err)
+	assert.ErrorIs(t, err, decoder.ErrTruncated)
+	assert.Equal(t, int64(len(payload)), decodeErr.Offset)
+	assert.Equal(t, "", decodeErr.Path)
+}
+
+func TestStreamDecoderNestedPath(t *testing.T) {
+	var nested, buf bytes.Buffer
+	writeRecord(&nested, "year", 0x01, []byte{0x00})
+	writeRecord(&nested, "utc_offset", 0x09, []byte("+3"))
+	writeRecord(&buf, "datetime", 0x02, nested.Bytes())
+
+	_, err := decoder.Decoder(&buf)
+	decodeErr := decodeErrorOf(t, err)
+	assert.ErrorIs(t, err, decoder.ErrUnknownType)
+	assert.Equal(t, "datetime.utc_offset", decodeErr.Path)
+	assert.Equal(t, int64(5+len("datetime")+5+len("year")+1+4), decodeErr.Offset)
+}
+
+func TestStreamDecoderNestedOverflow(t *testing.T) {
+	var nested, buf bytes.Buffer
+	writeRecord(&nested, "id", 0x00, []byte("CAM123"))
+	// Длина вложенной структуры меньше, чем ее содержимое
+	value := nested.Bytes()[:len(nested.Bytes())-3]
+	writeRecord(&buf, "camera", 0x02, value)
+	buf.WriteString("123")
+
+	_, err := decoder.Decoder(&buf)
+	assert.ErrorIs(t, err, decoder.ErrOverflow)
+	assert.Equal(t, "camera", decodeErrorOf(t, err).Path)
+}
+
+func TestStreamDecoderMaxDepth(t *testing.T) {
+	var value bytes.Buffer
+	writeRecord(&value, "leaf", 0x00, []byte("x"))
+	for i := 0; i < 3; i++ {
+		var next bytes.Buffer
+		writeRecord(&next, "n", 0x02, value.Bytes())
+		value = next
+	}
+
+	_, err := decoder.NewStreamDecoder(bytes.NewReader(value.Bytes()), decoder.Limits{MaxDepth: 3}).Decode()
+	assert.NoError(t, err)
+
+	_, err = decoder.NewStreamDecoder(bytes.NewReader(value.Bytes()), decoder.Limits{MaxDepth: 2}).Decode()
+	assert.ErrorIs(t, err, decoder.ErrMaxDepth)
+	assert.Equal(t, "n.n.n", decodeErrorOf(t, err).Path)
+}
+
+func TestStreamDecoderMaxSize(t *testing.T) {
+	var buf bytes.Buffer
+	writeRecord(&buf, "a", 0x00, []byte("1234567890"))
+	payload := buf.Bytes()
+
+	_, err := decoder.NewStreamDecoder(bytes.NewReader(payload), decoder.Limits{MaxSize: len(payload)}).Decode()
+	assert.NoError(t, err)
+
+	_, err = decoder.NewStreamDecoder(bytes.NewReader(payload), decoder.Limits{MaxSize: len(payload) - 1}).Decode()
+	assert.ErrorIs(t, err, decoder.ErrMaxSize)
+}
+
+func TestStreamDecoderMaxKeys(t *testing.T) {
+	var nested, buf bytes.Buffer
+	writeRecord(&nested, "b", 0x00, []byte("2"))
+	writeRecord(&nested, "c", 0x00, []byte("3"))
+	writeRecord(&buf, "a", 0x02, nested.Bytes())
+
+	_, err := decoder.NewStreamDecoder(bytes.NewReader(buf.Bytes()), decoder.Limits{MaxKeys: 3}).Decode()
+	assert.NoError(t, err)
+
+	_, err = decoder.NewStreamDecoder(bytes.NewReader(buf.Bytes()), decoder.Limits{MaxKeys: 2}).Decode()
+	assert.ErrorIs(t, err, decoder.ErrMaxKeys)
+	assert.Equal(t, "a", decodeErrorOf(t, err).Path)
+}
+
+func TestStreamDecoderMaxKeysArray(t *testing.T) {
+	// Массив из трех строк по одному байту: тип элементов, затем длина (2 байта) и значение каждого элемента
+	array := []byte{0x00, 0x00, 0x01, 'x', 0x00, 0x01, 'y', 0x00, 0x01, 'z'}
+
+	var buf bytes.Buffer
+	writeRecord(&buf, "plates", 0x06, array)
+
+	result, err := decoder.NewStreamDecoder(bytes.NewReader(buf.Bytes()), decoder.Limits{MaxKeys: 4}).Decode()
+	if assert.NoError(t, err) {
+		assert.Equal(t, []interface{}{"x", "y", "z"}, result["plates"])
+	}
+
+	_, err = decoder.NewStreamDecoder(bytes.NewReader(buf.Bytes()), decoder.Limits{MaxKeys: 3}).Decode()
+	assert.ErrorIs(t, err, decoder.ErrMaxKeys)
+	assert.Equal(t, "plates[2]", decodeErrorOf(t, err).Path)
+}
+
+func TestStreamDecoderBadInt(t *testing.T) {
+	var buf bytes.Buffer
+	writeRecord(&buf, "skill", 0x01, []byte{0x80})
+
+	_, err := decoder.Decoder(&buf)
+	assert.ErrorIs(t, err, decoder.ErrBadInt)
+	assert.Equal(t, "skill", decodeErrorOf(t, err).Path)
+}
@@ -0,0 +1,147 @@
+package tests
+
+import (
+	"bytes"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/decoder"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/models"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestValueTypesRoundTrip(t *testing.T) {
+	data := map[string]interface{}{
+		"speed":    87.5,
+		"negative": -0.25,
+		"flash":    true,
+		"night":    false,
+		"photo":    []byte{0x00, 0xff, 0x10},
+		"empty":    []byte{},
+		"plates":   []interface{}{"А123ВС77", "А123ВС97"},
+		"scores":   []interface{}{int64(1), int64(-2)},
+		"nested": []interface{}{
+			map[string]interface{}{"id": "CAM1"},
+			map[string]interface{}{"id": "CAM2"},
+		},
+		"matrix":   []interface{}{[]interface{}{1.5}, []interface{}{}},
+		"nothing":  []interface{}{},
+		"datetime": time.Date(2023, 4, 15, 11, 30, 45, 123000000, time.UTC),
+	}
+
+	buf, err := decoder.Encoder(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := decoder.Decoder(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, data, result)
+}
+
+func TestValueTypesWireFormat(t *testing.T) {
+	buf, err := decoder.Encoder(map[string]interface{}{"a": []interface{}{"x", "yz"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var expected bytes.Buffer
+	writeRecord(&expected, "a", 0x06, []byte{0x00, 0x00, 0x01, 'x', 0x00, 0x02, 'y', 'z'})
+	assert.Equal(t, expected.Bytes(), buf.Bytes())
+}
+
+func TestValueTypesBadFloat(t *testing.T) {
+	var buf bytes.Buffer
+	writeRecord(&buf, "speed", 0x03, []byte{0x00, 0x01})
+
+	_, err := decoder.Decoder(&buf)
+	assert.ErrorIs(t, err, decoder.ErrBadFloat)
+	assert.Equal(t, "speed", decodeErrorOf(t, err).Path)
+}
+
+func TestValueTypesBadBool(t *testing.T) {
+	var buf bytes.Buffer
+	writeRecord(&buf, "flash", 0x04, []byte{0x02})
+
+	_, err := decoder.Decoder(&buf)
+	assert.ErrorIs(t, err, decoder.ErrBadBool)
+	assert.Equal(t, "flash", decodeErrorOf(t, err).Path)
+}
+
+func TestValueTypesBadArray(t *testing.T) {
+	// Неизвестный тип элементов
+	var buf bytes.Buffer
+	writeRecord(&buf, "plates", 0x06, []byte{0x09})
+
+	_, err := decoder.Decoder(&buf)
+	assert.ErrorIs(t, err, decoder.ErrUnknownType)
+	assert.Equal(t, "plates", decodeErrorOf(t, err).Path)
+
+	// Элемент выходит за границы массива
+	buf.Reset()
+	writeRecord(&buf, "plates", 0x06, []byte{0x00, 0x00, 0x05, 'x'})
+
+	_, err = decoder.Decoder(&buf)
+	assert.ErrorIs(t, err, decoder.ErrOverflow)
+	assert.Equal(t, "plates[0]", decodeErrorOf(t, err).Path)
+
+	// Некорректный элемент
+	buf.Reset()
+	writeRecord(&buf, "flags", 0x06, []byte{0x04, 0x00, 0x01, 0x01, 0x00, 0x01, 0x07})
+
+	_, err = decoder.Decoder(&buf)
+	assert.ErrorIs(t, err, decoder.ErrBadBool)
+	assert.Equal(t, "flags[1]", decodeErrorOf(t, err).Path)
+}
+
+func TestValueTypesMixedArray(t *testing.T) {
+	_, err := decoder.Encoder(map[string]interface{}{"mixed": []interface{}{"a", int64(1)}})
+	assert.Error(t, err)
+}
+
+func TestCaseDataType4(t *testing.T) {
+	model := decoder.CaseDataType4{
+		Plates:    []string{"А123ВС77", "А123ВС97"},
+		Camera:    decoder.CameraInfo{ID: "CAM123"},
+		Violation: decoder.ViolationInfo{ID: "VIO456"},
+		Speed:     87.5,
+		Skill:     3,
+		Datetime:  time.Date(2023, 4, 15, 11, 30, 45, 0, time.UTC),
+	}
+
+	buf, err := decoder.EncodeCameraModel(model)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := decoder.Decoder(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cameraModel, err := decoder.MapToStruct(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, model, cameraModel)
+
+	caseBase, err := cameraModel.CameraDataToCaseBase()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, models.CaseBase{
+		CameraID:       "CAM123",
+		Transport:      "A123BC77",
+		ViolationID:    "VIO456",
+		ViolationValue: "87.5",
+		Level:          3,
+		CurrentLevel:   3,
+		Datetime:       model.Datetime,
+	}, caseBase)
+
+	model.Plates = nil
+	_, err = model.CameraDataToCaseBase()
+	assert.Error(t, err)
+}
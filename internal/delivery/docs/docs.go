@@ -15,9 +15,9 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/manager/get_case": {
-            "get": {
-                "description": "Retrieves a case by its ID and returns detailed information about the case.\nField ` + "`" + `rated_covers` + "`" + ` could be null if there are no ratings",
+        "/manager/create_control_case": {
+            "post": {
+                "description": "Adds a case with a known answer to the specialists' queue. Specialists get it like any other case,\nbut its ratings never reach consensus, change the case or send fines. They are compared with ` + "`" + `choice` + "`" + `\nand returned as accuracy in /manager/get_specialists_rating.",
                 "consumes": [
                     "application/json"
                 ],
@@ -37,22 +37,24 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "ID of the case to retrieve",
-                        "name": "case_id",
-                        "in": "query",
-                        "required": true
+                        "description": "Case data and the known answer",
+                        "name": "control_case",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ControlCaseCreate"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Successfully retrieved the case details",
+                    "201": {
+                        "description": "Control case created, returning case ID",
                         "schema": {
-                            "$ref": "#/definitions/models.CaseFul"
+                            "$ref": "#/definitions/responses.CreationIntResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid query parameter or missing case_id",
+                        "description": "Invalid input data",
                         "schema": {
                             "$ref": "#/definitions/responses.MessageResponse"
                         }
@@ -63,6 +65,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/responses.MessageResponse"
                         }
                     },
+                    "422": {
+                        "description": "Camera, violation or transport contacts do not exist",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MissingReferencesResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -72,9 +80,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/manager/get_specialists_rating": {
-            "get": {
-                "description": "Retrieves a list of specialists ratings within a specified time range, paginated by a cursor.\nTime example (2023-04-12T15:04:05Z - without time zone / 2023-04-12T15:04:05+07:00 - with time zone)",
+        "/manager/create_rating_reason": {
+            "post": {
+                "description": "Adds a reason code to the catalogue. Specialists may attach active reasons to their ratings.",
                 "consumes": [
                     "application/json"
                 ],
@@ -94,35 +102,24 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Cursor for pagination",
-                        "name": "cursor",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Start time for filtering ratings (inclusive), in RFC3339 format",
-                        "name": "time_from",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "End time for filtering ratings (inclusive), in RFC3339 format",
-                        "name": "time_to",
-                        "in": "query",
-                        "required": true
+                        "description": "Reason code and description",
+                        "name": "reason",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RatingReasonCreate"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Successfully retrieved the specialists' ratings",
+                    "201": {
+                        "description": "Reason created",
                         "schema": {
-                            "$ref": "#/definitions/models.RatingSpecialistCountCursor"
+                            "$ref": "#/definitions/models.RatingReasonCreate"
                         }
                     },
                     "400": {
-                        "description": "Invalid query parameter or missing required fields",
+                        "description": "Invalid input data",
                         "schema": {
                             "$ref": "#/definitions/responses.MessageResponse"
                         }
@@ -133,6 +130,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/responses.MessageResponse"
                         }
                     },
+                    "409": {
+                        "description": "Reason code already exists",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -142,9 +145,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/public/camera_create": {
-            "post": {
-                "description": "Creates a new camera and returns its ID upon successful creation.",
+        "/manager/get_camera_skip_stats": {
+            "get": {
+                "description": "Retrieves how often specialists skip cases from each camera: number of cases, skipped cases, all skips,\nshare of skipped cases and skips by reason. Cameras with the highest share of skipped cases go first.",
                 "consumes": [
                     "application/json"
                 ],
@@ -152,29 +155,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "public"
+                    "managers"
                 ],
-                "summary": "Camera Creation",
                 "parameters": [
                     {
-                        "description": "Camera Creation",
-                        "name": "camera",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.CameraBase"
-                        }
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Successful creation, returning camera ID",
+                    "200": {
+                        "description": "Successfully retrieved the skip statistics",
                         "schema": {
-                            "$ref": "#/definitions/responses.CreationStringResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.CameraSkipStats"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Invalid input",
+                    "403": {
+                        "description": "JWT is invalid or expired",
                         "schema": {
                             "$ref": "#/definitions/responses.MessageResponse"
                         }
@@ -188,9 +192,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/public/camera_delete": {
-            "delete": {
-                "description": "Deletes an existing camera by its ID.",
+        "/manager/get_case": {
+            "get": {
+                "description": "Retrieves a case by its ID and returns detailed information about the case.\nField ` + "`" + `rated_covers` + "`" + ` could be null if there are no ratings\nField ` + "`" + `timeline` + "`" + ` lists case events in order: creation, ratings, escalations, decisions, notifications and overrides.",
                 "consumes": [
                     "application/json"
                 ],
@@ -198,24 +202,40 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "public"
+                    "managers"
                 ],
-                "summary": "Camera Deletion",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    },
                     {
                         "type": "integer",
-                        "description": "Camera ID",
-                        "name": "id",
+                        "description": "ID of the case to retrieve",
+                        "name": "case_id",
                         "in": "query",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "Successful deletion"
+                    "200": {
+                        "description": "Successfully retrieved the case details",
+                        "schema": {
+                            "$ref": "#/definitions/models.CaseFul"
+                        }
                     },
                     "400": {
-                        "description": "Invalid input or Camera ID not found",
+                        "description": "Invalid query parameter or missing case_id",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "JWT is invalid or expired",
                         "schema": {
                             "$ref": "#/definitions/responses.MessageResponse"
                         }
@@ -229,90 +249,59 @@ const docTemplate = `{
                 }
             }
         },
-        "/public/case_create": {
-            "post": {
-                "description": "Creates a new case with a photo (.jpeg / .jpg / .png / .svg) and case data in byte string.",
+        "/manager/get_case_votes": {
+            "get": {
+                "description": "Retrieves all ratings of a case with the choice of each specialist, in the order they were given.\nField ` + "`" + `level` + "`" + ` is the current level of the specialist.",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "public"
+                    "managers"
                 ],
-                "summary": "Case Creation",
                 "parameters": [
                     {
-                        "type": "file",
-                        "description": "Photo of the case",
-                        "name": "photo",
-                        "in": "formData",
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "authorization",
+                        "in": "header",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Case data in byte string format",
-                        "name": "byte_string",
-                        "in": "formData",
+                        "type": "integer",
+                        "description": "ID of the case",
+                        "name": "case_id",
+                        "in": "query",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Successful creation, returning case ID",
+                    "200": {
+                        "description": "Successfully retrieved the case votes",
                         "schema": {
-                            "$ref": "#/definitions/responses.CreationIntResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.CaseVoteFul"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Invalid input",
+                        "description": "Invalid query parameter or missing case_id",
                         "schema": {
                             "$ref": "#/definitions/responses.MessageResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "403": {
+                        "description": "JWT is invalid or expired",
                         "schema": {
                             "$ref": "#/definitions/responses.MessageResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/public/manager_login": {
-            "post": {
-                "description": "Logs in a specialist and returns a JWT and refresh token upon successful login.",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "public"
-                ],
-                "summary": "Manager Login",
-                "parameters": [
-                    {
-                        "description": "Manager Login",
-                        "name": "specialist",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.ManagerBase"
-                        }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "Successful login, returning JWT and refresh token",
-                        "schema": {
-                            "$ref": "#/definitions/responses.JWTRefresh"
-                        }
                     },
-                    "400": {
-                        "description": "Invalid input or incorrect password / login",
+                    "404": {
+                        "description": "Case not found",
                         "schema": {
                             "$ref": "#/definitions/responses.MessageResponse"
                         }
@@ -326,9 +315,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/public/refresh": {
-            "post": {
-                "description": "Refreshes access and refresh tokens using a refresh token provided in the Authorization header.",
+        "/manager/get_dead_notifications": {
+            "get": {
+                "description": "Retrieves fine notifications which failed on every attempt and will not be sent again, paginated by a cursor.\nField ` + "`" + `last_error` + "`" + ` contains the error of the last attempt. Returned cursor can be only int or null.",
                 "consumes": [
                     "application/json"
                 ],
@@ -336,33 +325,40 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "public"
+                    "managers"
                 ],
-                "summary": "Refresh Tokens",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Refresh Token",
-                        "name": "refresh",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "authorization",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Cursor for pagination",
+                        "name": "cursor",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successful token refresh, returning new jwt and refresh token",
+                        "description": "Successfully retrieved the notifications",
                         "schema": {
-                            "$ref": "#/definitions/responses.JWTRefresh"
+                            "$ref": "#/definitions/models.NotificationCursor"
                         }
                     },
                     "400": {
-                        "description": "No refresh token provided",
+                        "description": "Invalid query parameter or missing cursor",
                         "schema": {
                             "$ref": "#/definitions/responses.MessageResponse"
                         }
                     },
-                    "401": {
-                        "description": "Invalid or expired refresh token",
+                    "403": {
+                        "description": "JWT is invalid or expired",
                         "schema": {
                             "$ref": "#/definitions/responses.MessageResponse"
                         }
@@ -376,9 +372,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/public/specialist_login": {
-            "post": {
-                "description": "Logs in a specialist and returns a jwt and refresh token upon successful login.",
+        "/manager/get_rating_reasons": {
+            "get": {
+                "description": "Retrieves the whole reason catalogue, including deactivated reasons.",
                 "consumes": [
                     "application/json"
                 ],
@@ -386,29 +382,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "public"
+                    "managers"
                 ],
-                "summary": "Specialist Login",
                 "parameters": [
                     {
-                        "description": "Specialist Login",
-                        "name": "specialist",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.SpecialistLogin"
-                        }
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Successful login, returning jwt and refresh token",
+                    "200": {
+                        "description": "Successfully retrieved the rating reasons",
                         "schema": {
-                            "$ref": "#/definitions/responses.JWTRefresh"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.RatingReason"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Invalid input or incorrect password / login",
+                    "403": {
+                        "description": "JWT is invalid or expired",
                         "schema": {
                             "$ref": "#/definitions/responses.MessageResponse"
                         }
@@ -422,56 +419,50 @@ const docTemplate = `{
                 }
             }
         },
-        "/public/specialist_register": {
-            "post": {
-                "description": "Registers a new specialist, uploads their photo, and returns a jwt and refresh token upon successful registration.\nAutomatically level=1, is_verified=false.\nLogin and password are required, along with a photo upload.\nThere are some validation on password: More than 8 symbols, contain at least one number, one uppercase and one lowercase letter.",
+        "/manager/get_review_cases": {
+            "get": {
+                "description": "Retrieves unsolved cases on which specialists of the maximum level (MAX_CASE_LEVEL) did not reach consensus, paginated by a cursor.\nReturned cursor can be only int or null. It depends on existence of cases.",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "public"
+                    "managers"
                 ],
-                "summary": "Specialist Registration with Photo Upload",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Login",
-                        "name": "login",
-                        "in": "formData",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "authorization",
+                        "in": "header",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Password",
-                        "name": "password",
-                        "in": "formData",
+                        "type": "integer",
+                        "description": "Cursor for pagination",
+                        "name": "cursor",
+                        "in": "query",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Full Name",
-                        "name": "fullname",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "file",
-                        "description": "Photo Upload",
-                        "name": "photo",
-                        "in": "formData"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Successful registration, returning jwt and refresh token",
+                    "200": {
+                        "description": "Successfully retrieved the review cases",
                         "schema": {
-                            "$ref": "#/definitions/responses.JWTRefresh"
+                            "$ref": "#/definitions/models.CaseCursor"
                         }
                     },
                     "400": {
-                        "description": "Invalid input",
+                        "description": "Invalid query parameter or missing cursor",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "JWT is invalid or expired",
                         "schema": {
                             "$ref": "#/definitions/responses.MessageResponse"
                         }
@@ -485,9 +476,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/specialist/create_rated": {
-            "post": {
-                "description": "Creates a new rating entry based on the provided data.",
+        "/manager/get_specialists_rating": {
+            "get": {
+                "description": "Retrieves a list of specialists ratings within a specified time range, paginated by a cursor.\nTime example (2023-04-12T15:04:05Z - without time zone / 2023-04-12T15:04:05+07:00 - with time zone)\n` + "`" + `total` + "`" + `, ` + "`" + `correct` + "`" + ` and ` + "`" + `unknown` + "`" + ` count ratings of regular cases against the specialists' consensus.\n` + "`" + `control_total` + "`" + `, ` + "`" + `control_correct` + "`" + ` and ` + "`" + `accuracy` + "`" + ` count ratings of control cases against their known answer,\n` + "`" + `accuracy` + "`" + ` is null if the specialist rated no control cases in the range.",
                 "consumes": [
                     "application/json"
                 ],
@@ -495,18 +486,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "specialists"
+                    "managers"
                 ],
                 "parameters": [
-                    {
-                        "description": "Rated data",
-                        "name": "rated_data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.RatedCreate"
-                        }
-                    },
                     {
                         "type": "string",
                         "default": "Bearer \u003cAdd access token here\u003e",
@@ -514,17 +496,37 @@ const docTemplate = `{
                         "name": "authorization",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Cursor for pagination",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start time for filtering ratings (inclusive), in RFC3339 format",
+                        "name": "time_from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "End time for filtering ratings (inclusive), in RFC3339 format",
+                        "name": "time_to",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Successfully created the rating",
+                    "200": {
+                        "description": "Successfully retrieved the specialists' ratings",
                         "schema": {
-                            "$ref": "#/definitions/responses.CreationIntResponse"
+                            "$ref": "#/definitions/models.RatingSpecialistCountCursor"
                         }
                     },
                     "400": {
-                        "description": "Invalid input data",
+                        "description": "Invalid query parameter or missing required fields",
                         "schema": {
                             "$ref": "#/definitions/responses.MessageResponse"
                         }
@@ -544,9 +546,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/specialist/get_cases_by_level": {
+        "/manager/get_unmatched_cases": {
             "get": {
-                "description": "Retrieves cases based on the provided cursor ID and the user's ID. It returns cases that match the level of difficulty or rating specified for the user.\nReturned cursor can be only int or null. It depends on existence of cases.",
+                "description": "Retrieves cases held because their transport has no contacts, paginated by a cursor.\nReturned cursor can be only int or null. It depends on existence of cases.",
                 "consumes": [
                     "application/json"
                 ],
@@ -554,16 +556,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "specialists"
+                    "managers"
                 ],
                 "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Cursor ID for pagination",
-                        "name": "cursor",
-                        "in": "query",
-                        "required": true
-                    },
                     {
                         "type": "string",
                         "default": "Bearer \u003cAdd access token here\u003e",
@@ -571,29 +566,30 @@ const docTemplate = `{
                         "name": "authorization",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Cursor for pagination",
+                        "name": "cursor",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully retrieved the cases by level",
+                        "description": "Successfully retrieved the unmatched cases",
                         "schema": {
-                            "$ref": "#/definitions/models.CaseCursor"
+                            "$ref": "#/definitions/models.UnmatchedCaseCursor"
                         }
                     },
                     "400": {
-                        "description": "Invalid input data or bad query parameter",
-                        "schema": {
-                            "$ref": "#/definitions/responses.MessageResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "JWT is invalid or expired",
+                        "description": "Invalid query parameter or missing cursor",
                         "schema": {
                             "$ref": "#/definitions/responses.MessageResponse"
                         }
                     },
                     "403": {
-                        "description": "User is unverified",
+                        "description": "JWT is invalid or expired",
                         "schema": {
                             "$ref": "#/definitions/responses.MessageResponse"
                         }
@@ -607,9 +603,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/specialist/get_rated_solved": {
-            "get": {
-                "description": "Retrieves a rated solved entry based on the provided cursor ID.",
+        "/manager/override_case": {
+            "post": {
+                "description": "Revises a solved case with a mandatory justification. ` + "`" + `overturn` + "`" + ` replaces the decision with the opposite one,\n` + "`" + `reopen` + "`" + ` returns the case for rating at the next level, or to the review queue from the maximum level.\nRatings statuses and specialists' streaks are recomputed, the fine or cancellation notification is queued for sending.\nEvery override is kept in the case history returned by /manager/get_case.",
                 "consumes": [
                     "application/json"
                 ],
@@ -617,16 +613,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "specialists"
+                    "managers"
                 ],
                 "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Cursor ID for pagination",
-                        "name": "cursor",
-                        "in": "query",
-                        "required": true
-                    },
                     {
                         "type": "string",
                         "default": "Bearer \u003cAdd access token here\u003e",
@@ -634,13 +623,22 @@ const docTemplate = `{
                         "name": "authorization",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "description": "Override data, action is ` + "`" + `overturn` + "`" + ` or ` + "`" + `reopen` + "`" + `",
+                        "name": "override",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CaseOverrideCreate"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Successfully retrieved the rated solved",
+                    "201": {
+                        "description": "Case revised, returning the override record",
                         "schema": {
-                            "$ref": "#/definitions/models.RatedCursor"
+                            "$ref": "#/definitions/models.CaseOverride"
                         }
                     },
                     "400": {
@@ -649,12 +647,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/responses.MessageResponse"
                         }
                     },
-                    "401": {
+                    "403": {
                         "description": "JWT is invalid or expired",
                         "schema": {
                             "$ref": "#/definitions/responses.MessageResponse"
                         }
                     },
+                    "404": {
+                        "description": "Case not found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Case is not solved",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -664,9 +674,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/specialist/get_rating": {
+        "/manager/preview_notification": {
             "get": {
-                "description": "Give specialists rating",
+                "description": "Renders the notification of a case exactly as the offender would receive it, without sending it.\nTemplates are chosen by the violation type and the language: ` + "`" + `language` + "`" + ` overrides the language\nfrom the owner's contacts. Field ` + "`" + `photo` + "`" + ` is the photo attached to the email.",
                 "consumes": [
                     "application/json"
                 ],
@@ -674,7 +684,7 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "specialists"
+                    "managers"
                 ],
                 "parameters": [
                     {
@@ -684,32 +694,57 @@ const docTemplate = `{
                         "name": "authorization",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of the case",
+                        "name": "case_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "fine",
+                            "fine_cancel"
+                        ],
+                        "type": "string",
+                        "default": "fine",
+                        "description": "Notification kind",
+                        "name": "kind",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "ru",
+                            "en"
+                        ],
+                        "type": "string",
+                        "description": "Notification language",
+                        "name": "language",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully retrieved the cases by level",
+                        "description": "Successfully rendered the notification",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.RatingSpecialistFul"
-                            }
+                            "$ref": "#/definitions/models.NotificationPreview"
                         }
                     },
                     "400": {
-                        "description": "Invalid input data or bad query parameter",
+                        "description": "Invalid query parameter, unknown kind or language",
                         "schema": {
                             "$ref": "#/definitions/responses.MessageResponse"
                         }
                     },
-                    "401": {
+                    "403": {
                         "description": "JWT is invalid or expired",
                         "schema": {
                             "$ref": "#/definitions/responses.MessageResponse"
                         }
                     },
-                    "403": {
-                        "description": "User is unverified",
+                    "404": {
+                        "description": "Case not found",
                         "schema": {
                             "$ref": "#/definitions/responses.MessageResponse"
                         }
@@ -723,9 +758,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/specialist/me": {
-            "get": {
-                "description": "Retrieves information about the current specialist based on their user ID.",
+        "/manager/resolve_review_case": {
+            "post": {
+                "description": "Solves a case from the review queue with the manager's choice. Ratings are marked correct or incorrect\nagainst this choice, specialists' streaks are updated and the fine notification is queued for sending, as after specialists' consensus.",
                 "consumes": [
                     "application/json"
                 ],
@@ -733,7 +768,7 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "specialists"
+                    "managers"
                 ],
                 "parameters": [
                     {
@@ -743,13 +778,22 @@ const docTemplate = `{
                         "name": "authorization",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "description": "Manager decision",
+                        "name": "decision",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CaseReviewDecision"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully retrieved the specialist info",
+                        "description": "Case solved",
                         "schema": {
-                            "$ref": "#/definitions/models.Specialist"
+                            "$ref": "#/definitions/responses.MessageResponse"
                         }
                     },
                     "400": {
@@ -758,12 +802,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/responses.MessageResponse"
                         }
                     },
-                    "401": {
+                    "403": {
                         "description": "JWT is invalid or expired",
                         "schema": {
                             "$ref": "#/definitions/responses.MessageResponse"
                         }
                     },
+                    "404": {
+                        "description": "Case not found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Case is not awaiting a manager decision",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -773,19 +829,18 @@ const docTemplate = `{
                 }
             }
         },
-        "/specialist/update": {
-            "put": {
-                "description": "Updates an existing specialist's information including their password, full name, and photo.\nThe password must be more than 8 symbols and contain at least one number, one uppercase, and one lowercase letter.\nThe photo upload is optional but must be a valid image file if provided.",
+        "/manager/resolve_unmatched_case": {
+            "post": {
+                "description": "Attaches an unmatched case to an existing contact or creates a new one, then moves the case to review.\n` + "`" + `transport` + "`" + ` replaces the recognized plate if the camera was wrong, by default the recognized plate is used.\nIf ` + "`" + `contacts` + "`" + ` are provided, a new contact is created for the transport, otherwise the contact must already exist.",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "specialists"
+                    "managers"
                 ],
-                "summary": "UpdateMain Specialist Information with Photo Upload",
                 "parameters": [
                     {
                         "type": "string",
@@ -796,27 +851,21 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Password",
-                        "name": "password",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Full Name",
-                        "name": "fullname",
-                        "in": "formData"
-                    },
-                    {
-                        "type": "file",
-                        "description": "Photo Upload",
-                        "name": "photo",
-                        "in": "formData"
+                        "description": "Unmatched case resolution",
+                        "name": "resolve_data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UnmatchedCaseResolve"
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "Successful update, no content returned"
+                    "201": {
+                        "description": "Case moved to review, returning case ID",
+                        "schema": {
+                            "$ref": "#/definitions/responses.CreationIntResponse"
+                        }
                     },
                     "400": {
                         "description": "Invalid input data",
@@ -824,153 +873,1713 @@ const docTemplate = `{
                             "$ref": "#/definitions/responses.MessageResponse"
                         }
                     },
-                    "401": {
+                    "403": {
                         "description": "JWT is invalid or expired",
                         "schema": {
                             "$ref": "#/definitions/responses.MessageResponse"
                         }
                     },
+                    "404": {
+                        "description": "Unmatched case or contact not found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Contact for the transport already exists",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    },
                     "500": {
-                        "description": "Internal server error, could not process the request",
+                        "description": "Internal server error",
                         "schema": {
                             "$ref": "#/definitions/responses.MessageResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.CameraBase": {
-            "type": "object",
-            "required": [
-                "coordinates",
+        },
+        "/manager/retry_notification": {
+            "post": {
+                "description": "Returns a notification which failed on every attempt to the outbox with a fresh attempt counter.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "managers"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Notification ID",
+                        "name": "retry",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.NotificationRetry"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Notification returned to the outbox",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "JWT is invalid or expired",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Failed notification not found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/manager/update_rating_reason": {
+            "put": {
+                "description": "Changes the description of a reason or (de)activates it. Deactivated reasons can't be attached to new ratings,\nratings which already have them are kept unchanged.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "managers"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason code and changed fields",
+                        "name": "reason",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RatingReasonUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Reason updated",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "JWT is invalid or expired",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Reason not found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/public/camera_create": {
+            "post": {
+                "description": "Creates a new camera and returns its ID upon successful creation.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "public"
+                ],
+                "summary": "Camera Creation",
+                "parameters": [
+                    {
+                        "description": "Camera Creation",
+                        "name": "camera",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CameraBase"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successful creation, returning camera ID",
+                        "schema": {
+                            "$ref": "#/definitions/responses.CreationStringResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/public/camera_delete": {
+            "delete": {
+                "description": "Deletes an existing camera by its ID.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "public"
+                ],
+                "summary": "Camera Deletion",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Camera ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Successful deletion"
+                    },
+                    "400": {
+                        "description": "Invalid input or Camera ID not found",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/public/case_create": {
+            "post": {
+                "description": "Creates a new case with a photo (.jpeg / .jpg / .png / .svg) and case data in byte string. The first two bytes are the header: protocol version and flags (optional CRC32/CRC16 trailer).",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "public"
+                ],
+                "summary": "Case Creation",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Photo of the case",
+                        "name": "photo",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Case data as text, bit string by default",
+                        "name": "byte_string",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Case data as a file, raw bytes by default",
+                        "name": "payload",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Case data encoding: bits, hex, base64 or raw. Defaults to the payload Content-Type",
+                        "name": "encoding",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successful creation, returning case ID",
+                        "schema": {
+                            "$ref": "#/definitions/responses.CreationIntResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Transport has no contacts, the case is held until a manager resolves it",
+                        "schema": {
+                            "$ref": "#/definitions/responses.UnmatchedCaseResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Case data is too large",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Camera or violation of the case does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MissingReferencesResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/public/case_create_batch": {
+            "post": {
+                "description": "Creates cases from a zip archive or from lists of photos and case data.\nIn the archive the photo and the case data of one case share a file name: ` + "`" + `001.jpg` + "`" + ` + ` + "`" + `001.bin` + "`" + `.\nCase data extension sets its encoding: .bin (raw), .hex, .b64 / .base64, .bits / .txt (bit string).\nWithout an archive ` + "`" + `photos` + "`" + ` are paired by order with ` + "`" + `payloads` + "`" + ` files or ` + "`" + `byte_strings` + "`" + ` fields.\nCases whose transport has no contacts are held with ` + "`" + `unmatched_id` + "`" + ` until a manager resolves them.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "public"
+                ],
+                "summary": "Batch Case Creation",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Zip archive with photos and case data",
+                        "name": "archive",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "file"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Photos of the cases",
+                        "name": "photos",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "file"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Case data files in the order of photos",
+                        "name": "payloads",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Case data as text in the order of photos",
+                        "name": "byte_strings",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Case data encoding for all cases: bits, hex, base64 or raw",
+                        "name": "encoding",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Result for every case: created ID, unmatched ID or error",
+                        "schema": {
+                            "$ref": "#/definitions/models.CaseBatchResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/public/manager_login": {
+            "post": {
+                "description": "Logs in a specialist and returns a JWT and refresh token upon successful login.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "public"
+                ],
+                "summary": "Manager Login",
+                "parameters": [
+                    {
+                        "description": "Manager Login",
+                        "name": "specialist",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ManagerBase"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successful login, returning JWT and refresh token",
+                        "schema": {
+                            "$ref": "#/definitions/responses.JWTRefresh"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input or incorrect password / login",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/public/refresh": {
+            "post": {
+                "description": "Refreshes access and refresh tokens using a refresh token provided in the Authorization header.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "public"
+                ],
+                "summary": "Refresh Tokens",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Refresh Token",
+                        "name": "refresh",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successful token refresh, returning new jwt and refresh token",
+                        "schema": {
+                            "$ref": "#/definitions/responses.JWTRefresh"
+                        }
+                    },
+                    "400": {
+                        "description": "No refresh token provided",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid or expired refresh token",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/public/specialist_login": {
+            "post": {
+                "description": "Logs in a specialist and returns a jwt and refresh token upon successful login.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "public"
+                ],
+                "summary": "Specialist Login",
+                "parameters": [
+                    {
+                        "description": "Specialist Login",
+                        "name": "specialist",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SpecialistLogin"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successful login, returning jwt and refresh token",
+                        "schema": {
+                            "$ref": "#/definitions/responses.JWTRefresh"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input or incorrect password / login",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/public/specialist_register": {
+            "post": {
+                "description": "Registers a new specialist, uploads their photo, and returns a jwt and refresh token upon successful registration.\nAutomatically level=1, is_verified=false.\nLogin and password are required, along with a photo upload.\nThere are some validation on password: More than 8 symbols, contain at least one number, one uppercase and one lowercase letter.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "public"
+                ],
+                "summary": "Specialist Registration with Photo Upload",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Login",
+                        "name": "login",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Password",
+                        "name": "password",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Full Name",
+                        "name": "fullname",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Photo Upload",
+                        "name": "photo",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successful registration, returning jwt and refresh token",
+                        "schema": {
+                            "$ref": "#/definitions/responses.JWTRefresh"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/specialist/create_rated": {
+            "post": {
+                "description": "Creates a new rating entry based on the provided data.\nThe rating may contain an active reason code from /specialist/get_rating_reasons and a free-text comment.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "specialists"
+                ],
+                "parameters": [
+                    {
+                        "description": "Rated data",
+                        "name": "rated_data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RatedCreate"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successfully created the rating",
+                        "schema": {
+                            "$ref": "#/definitions/responses.CreationIntResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data, unknown or inactive reason code",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "JWT is invalid or expired",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/specialist/get_cases_by_level": {
+            "get": {
+                "description": "Retrieves cases based on the provided cursor ID and the user's ID. It returns cases that match the level of difficulty or rating specified for the user.\nReturned cursor can be only int or null. It depends on existence of cases.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "specialists"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cursor ID for pagination",
+                        "name": "cursor",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved the cases by level",
+                        "schema": {
+                            "$ref": "#/definitions/models.CaseCursor"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data or bad query parameter",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "JWT is invalid or expired",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "User is unverified",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/specialist/get_rated_solved": {
+            "get": {
+                "description": "Retrieves a rated solved entry based on the provided cursor ID.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "specialists"
+                ],
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cursor ID for pagination",
+                        "name": "cursor",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved the rated solved",
+                        "schema": {
+                            "$ref": "#/definitions/models.RatedCursor"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "JWT is invalid or expired",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/specialist/get_rating": {
+            "get": {
+                "description": "Give specialists rating",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "specialists"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved the cases by level",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.RatingSpecialistFul"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data or bad query parameter",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "JWT is invalid or expired",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "User is unverified",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/specialist/get_rating_reasons": {
+            "get": {
+                "description": "Retrieves the active reason codes which can be attached to a rating.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "specialists"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved the rating reasons",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.RatingReason"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "JWT is invalid or expired",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/specialist/me": {
+            "get": {
+                "description": "Retrieves information about the current specialist based on their user ID.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "specialists"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved the specialist info",
+                        "schema": {
+                            "$ref": "#/definitions/models.Specialist"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "JWT is invalid or expired",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/specialist/skip_case": {
+            "post": {
+                "description": "Marks a case as skipped by the specialist when it can't be rated: ` + "`" + `bad_photo` + "`" + `, ` + "`" + `plate_unreadable` + "`" + ` or ` + "`" + `wrong_violation` + "`" + `.\nA skip is not a rating: it does not count towards consensus or the specialist's rating, but the case is no longer offered to the specialist.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "specialists"
+                ],
+                "parameters": [
+                    {
+                        "description": "Skip data",
+                        "name": "skip_data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CaseSkipCreate"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successfully skipped the case",
+                        "schema": {
+                            "$ref": "#/definitions/responses.CreationIntResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data, case is solved, rated or already skipped",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "JWT is invalid or expired",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/specialist/take_next_case": {
+            "post": {
+                "description": "Reserves the next open case of the specialist's level for CASE_RESERVATION_TTL minutes. Cases already rated by the specialist or reserved by other specialists are skipped.\nIf the specialist already holds an active reservation, the reserved case is returned again. The reservation is released when the case is rated or when it expires.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "specialists"
+                ],
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully reserved the case",
+                        "schema": {
+                            "$ref": "#/definitions/models.CaseReservation"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "JWT is invalid or expired",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "User is unverified",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "There are no cases to reserve",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/specialist/update": {
+            "put": {
+                "description": "Updates an existing specialist's information including their password, full name, and photo.\nThe password must be more than 8 symbols and contain at least one number, one uppercase, and one lowercase letter.\nThe photo upload is optional but must be a valid image file if provided.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "specialists"
+                ],
+                "summary": "UpdateMain Specialist Information with Photo Upload",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer \u003cAdd access token here\u003e",
+                        "description": "Insert your access token",
+                        "name": "authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Password",
+                        "name": "password",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Full Name",
+                        "name": "fullname",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Photo Upload",
+                        "name": "photo",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Successful update, no content returned"
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "JWT is invalid or expired",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error, could not process the request",
+                        "schema": {
+                            "$ref": "#/definitions/responses.MessageResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "customerr.MissingReference": {
+            "type": "object",
+            "properties": {
+                "entity": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CameraBase": {
+            "type": "object",
+            "required": [
+                "coordinates",
                 "description",
                 "type"
             ],
             "properties": {
-                "coordinates": {
+                "coordinates": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "description": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CameraSkipStats": {
+            "type": "object",
+            "properties": {
+                "camera_id": {
+                    "type": "string"
+                },
+                "cases": {
+                    "type": "integer"
+                },
+                "reasons": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "skip_rate": {
+                    "type": "number"
+                },
+                "skipped_cases": {
+                    "type": "integer"
+                },
+                "skips": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CaseBatchItem": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "missing": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/customerr.MissingReference"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "unmatched_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CaseBatchResult": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CaseBatchItem"
+                    }
+                },
+                "unmatched": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CaseCursor": {
+            "type": "object",
+            "properties": {
+                "cases": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CaseViolations"
+                    }
+                },
+                "cursor": {
+                    "$ref": "#/definitions/null.Int"
+                }
+            }
+        },
+        "models.CaseEvent": {
+            "type": "object",
+            "properties": {
+                "case_id": {
+                    "type": "integer"
+                },
+                "choice": {
+                    "$ref": "#/definitions/null.Bool"
+                },
+                "datetime": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "level": {
+                    "$ref": "#/definitions/null.Int"
+                },
+                "manager_id": {
+                    "$ref": "#/definitions/null.Int"
+                },
+                "message": {
+                    "$ref": "#/definitions/null.String"
+                },
+                "specialist_id": {
+                    "$ref": "#/definitions/null.Int"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CaseFul": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "camera_id": {
+                    "type": "string"
+                },
+                "control_choice": {
+                    "$ref": "#/definitions/null.Bool"
+                },
+                "current_level": {
+                    "type": "integer"
+                },
+                "datetime": {
+                    "type": "string"
+                },
+                "is_solved": {
+                    "type": "boolean"
+                },
+                "level": {
+                    "type": "integer"
+                },
+                "overrides": {
                     "type": "array",
                     "items": {
-                        "type": "number"
+                        "$ref": "#/definitions/models.CaseOverride"
+                    }
+                },
+                "photo_url": {
+                    "type": "string"
+                },
+                "rated_covers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RatedCover"
+                    }
+                },
+                "timeline": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CaseEvent"
                     }
                 },
-                "description": {
+                "transport": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "violation_id": {
+                    "type": "string"
+                },
+                "violation_value": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CaseOverride": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "case_id": {
+                    "type": "integer"
+                },
+                "choice": {
+                    "$ref": "#/definitions/null.Bool"
+                },
+                "datetime": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "justification": {
+                    "type": "string"
+                },
+                "manager_id": {
+                    "$ref": "#/definitions/null.Int"
+                },
+                "previous_choice": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.CaseOverrideCreate": {
+            "type": "object",
+            "required": [
+                "action",
+                "case_id",
+                "justification"
+            ],
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "enum": [
+                        "overturn",
+                        "reopen"
+                    ]
+                },
+                "case_id": {
+                    "type": "integer"
+                },
+                "justification": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CaseReservation": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "camera_id": {
+                    "type": "string"
+                },
+                "current_level": {
+                    "type": "integer"
+                },
+                "datetime": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "level": {
+                    "type": "integer"
+                },
+                "photo_url": {
+                    "type": "string"
+                },
+                "transport": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "violation_id": {
+                    "type": "string"
+                },
+                "violation_value": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CaseReviewDecision": {
+            "type": "object",
+            "required": [
+                "case_id"
+            ],
+            "properties": {
+                "case_id": {
+                    "type": "integer"
+                },
+                "choice": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.CaseSkipCreate": {
+            "type": "object",
+            "required": [
+                "case_id",
+                "reason"
+            ],
+            "properties": {
+                "case_id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string",
+                    "enum": [
+                        "bad_photo",
+                        "plate_unreadable",
+                        "wrong_violation"
+                    ]
+                }
+            }
+        },
+        "models.CaseViolations": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "camera_id": {
+                    "type": "string"
+                },
+                "current_level": {
+                    "type": "integer"
+                },
+                "datetime": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "level": {
+                    "type": "integer"
+                },
+                "photo_url": {
+                    "type": "string"
+                },
+                "transport": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "violation_id": {
+                    "type": "string"
+                },
+                "violation_value": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CaseVoteFul": {
+            "type": "object",
+            "properties": {
+                "choice": {
+                    "type": "boolean"
+                },
+                "comment": {
+                    "$ref": "#/definitions/null.String"
+                },
+                "datetime": {
+                    "type": "string"
+                },
+                "fullname": {
+                    "$ref": "#/definitions/null.String"
+                },
+                "level": {
+                    "type": "integer"
+                },
+                "rated_id": {
+                    "type": "integer"
+                },
+                "reason_code": {
+                    "$ref": "#/definitions/null.String"
+                },
+                "specialist_id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ControlCaseCreate": {
+            "type": "object",
+            "required": [
+                "camera_id",
+                "datetime",
+                "level",
+                "photo_url",
+                "transport",
+                "violation_id",
+                "violation_value"
+            ],
+            "properties": {
+                "camera_id": {
+                    "type": "string"
+                },
+                "choice": {
+                    "type": "boolean"
+                },
+                "datetime": {
                     "type": "string"
                 },
-                "type": {
+                "level": {
+                    "type": "integer"
+                },
+                "photo_url": {
+                    "type": "string"
+                },
+                "transport": {
+                    "type": "string"
+                },
+                "violation_id": {
+                    "type": "string"
+                },
+                "violation_value": {
                     "type": "string"
                 }
             }
         },
-        "models.CaseCursor": {
+        "models.ManagerBase": {
             "type": "object",
+            "required": [
+                "login",
+                "password"
+            ],
             "properties": {
-                "cases": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.CaseViolations"
-                    }
+                "login": {
+                    "type": "string"
                 },
-                "cursor": {
-                    "$ref": "#/definitions/null.Int"
+                "password": {
+                    "type": "string"
                 }
             }
         },
-        "models.CaseFul": {
+        "models.Notification": {
             "type": "object",
             "properties": {
-                "amount": {
+                "attempts": {
                     "type": "integer"
                 },
-                "camera_id": {
-                    "type": "string"
-                },
-                "current_level": {
+                "case_id": {
                     "type": "integer"
                 },
-                "datetime": {
+                "created_at": {
                     "type": "string"
                 },
-                "is_solved": {
-                    "type": "boolean"
+                "deliveries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.NotificationDelivery"
+                    }
                 },
-                "level": {
+                "id": {
                     "type": "integer"
                 },
-                "photo_url": {
+                "idempotency_key": {
                     "type": "string"
                 },
-                "rated_covers": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.RatedCover"
-                    }
-                },
-                "transport": {
+                "kind": {
                     "type": "string"
                 },
-                "type": {
+                "last_error": {
+                    "$ref": "#/definitions/null.String"
+                },
+                "next_attempt_at": {
                     "type": "string"
                 },
-                "violation_id": {
+                "sent_at": {
                     "type": "string"
                 },
-                "violation_value": {
+                "status": {
                     "type": "string"
                 }
             }
         },
-        "models.CaseViolations": {
+        "models.NotificationCursor": {
             "type": "object",
             "properties": {
-                "amount": {
-                    "type": "integer"
+                "cursor": {
+                    "$ref": "#/definitions/null.Int"
                 },
-                "camera_id": {
+                "notifications": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Notification"
+                    }
+                }
+            }
+        },
+        "models.NotificationDelivery": {
+            "type": "object",
+            "properties": {
+                "channel": {
                     "type": "string"
                 },
-                "current_level": {
-                    "type": "integer"
-                },
-                "datetime": {
+                "created_at": {
                     "type": "string"
                 },
+                "delivered": {
+                    "type": "boolean"
+                },
+                "error": {
+                    "$ref": "#/definitions/null.String"
+                },
                 "id": {
                     "type": "integer"
                 },
-                "level": {
+                "notification_id": {
                     "type": "integer"
                 },
-                "photo_url": {
+                "recipient": {
                     "type": "string"
+                }
+            }
+        },
+        "models.NotificationPreview": {
+            "type": "object",
+            "properties": {
+                "case_id": {
+                    "type": "integer"
                 },
-                "transport": {
+                "html": {
                     "type": "string"
                 },
-                "type": {
+                "kind": {
                     "type": "string"
                 },
-                "violation_id": {
+                "language": {
                     "type": "string"
                 },
-                "violation_value": {
+                "photo": {
+                    "type": "string"
+                },
+                "subject": {
+                    "type": "string"
+                },
+                "text": {
                     "type": "string"
                 }
             }
         },
-        "models.ManagerBase": {
+        "models.NotificationRetry": {
             "type": "object",
             "required": [
-                "login",
-                "password"
+                "notification_id"
             ],
             "properties": {
-                "login": {
-                    "type": "string"
-                },
-                "password": {
-                    "type": "string"
+                "notification_id": {
+                    "type": "integer"
                 }
             }
         },
@@ -992,6 +2601,9 @@ const docTemplate = `{
                 "choice": {
                     "type": "boolean"
                 },
+                "comment": {
+                    "$ref": "#/definitions/null.String"
+                },
                 "date": {
                     "type": "string"
                 },
@@ -1004,6 +2616,12 @@ const docTemplate = `{
                 "photo_url": {
                     "type": "string"
                 },
+                "reason": {
+                    "$ref": "#/definitions/null.String"
+                },
+                "reason_code": {
+                    "$ref": "#/definitions/null.String"
+                },
                 "specialist_id": {
                     "type": "integer"
                 },
@@ -1021,6 +2639,12 @@ const docTemplate = `{
         "models.RatedCover": {
             "type": "object",
             "properties": {
+                "choice": {
+                    "type": "boolean"
+                },
+                "comment": {
+                    "$ref": "#/definitions/null.String"
+                },
                 "date": {
                     "type": "string"
                 },
@@ -1036,6 +2660,12 @@ const docTemplate = `{
                 "photo_url": {
                     "$ref": "#/definitions/null.String"
                 },
+                "reason": {
+                    "$ref": "#/definitions/null.String"
+                },
+                "reason_code": {
+                    "$ref": "#/definitions/null.String"
+                },
                 "row": {
                     "type": "integer"
                 },
@@ -1055,6 +2685,12 @@ const docTemplate = `{
                 },
                 "choice": {
                     "type": "boolean"
+                },
+                "comment": {
+                    "$ref": "#/definitions/null.String"
+                },
+                "reason_code": {
+                    "$ref": "#/definitions/null.String"
                 }
             }
         },
@@ -1072,9 +2708,70 @@ const docTemplate = `{
                 }
             }
         },
+        "models.RatingReason": {
+            "type": "object",
+            "required": [
+                "code",
+                "description"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "description": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.RatingReasonCreate": {
+            "type": "object",
+            "required": [
+                "code",
+                "description"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "description": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RatingReasonUpdate": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "description": {
+                    "$ref": "#/definitions/null.String"
+                },
+                "is_active": {
+                    "$ref": "#/definitions/null.Bool"
+                }
+            }
+        },
         "models.RatingSpecialistCount": {
             "type": "object",
             "properties": {
+                "accuracy": {
+                    "$ref": "#/definitions/null.Float"
+                },
+                "control_correct": {
+                    "type": "integer"
+                },
+                "control_total": {
+                    "type": "integer"
+                },
                 "correct": {
                     "type": "integer"
                 },
@@ -1180,6 +2877,87 @@ const docTemplate = `{
                 }
             }
         },
+        "models.UnmatchedCase": {
+            "type": "object",
+            "properties": {
+                "camera_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "current_level": {
+                    "type": "integer"
+                },
+                "datetime": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "level": {
+                    "type": "integer"
+                },
+                "photo_url": {
+                    "type": "string"
+                },
+                "transport": {
+                    "type": "string"
+                },
+                "violation_id": {
+                    "type": "string"
+                },
+                "violation_value": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.UnmatchedCaseCursor": {
+            "type": "object",
+            "properties": {
+                "cases": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.UnmatchedCase"
+                    }
+                },
+                "cursor": {
+                    "$ref": "#/definitions/null.Int"
+                }
+            }
+        },
+        "models.UnmatchedCaseResolve": {
+            "type": "object",
+            "required": [
+                "unmatched_id"
+            ],
+            "properties": {
+                "contacts": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "transport": {
+                    "type": "string"
+                },
+                "unmatched_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "null.Bool": {
+            "type": "object",
+            "properties": {
+                "bool": {
+                    "type": "boolean"
+                },
+                "valid": {
+                    "description": "Valid is true if Bool is not NULL",
+                    "type": "boolean"
+                }
+            }
+        },
         "null.Float": {
             "type": "object",
             "properties": {
@@ -1250,6 +3028,31 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "responses.MissingReferencesResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "missing": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/customerr.MissingReference"
+                    }
+                }
+            }
+        },
+        "responses.UnmatchedCaseResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "unmatched_id": {
+                    "type": "integer"
+                }
+            }
         }
     }
 }`
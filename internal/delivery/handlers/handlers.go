@@ -5,6 +5,26 @@ import "github.com/gin-gonic/gin"
 type Managers interface {
 	GetFulCaseByID(c *gin.Context)
 	GetSpecialistRating(c *gin.Context)
+
+	GetUnmatchedCases(c *gin.Context)
+	ResolveUnmatchedCase(c *gin.Context)
+
+	GetReviewCases(c *gin.Context)
+	GetCaseVotes(c *gin.Context)
+	ResolveReviewCase(c *gin.Context)
+	OverrideCase(c *gin.Context)
+
+	GetCameraSkipStats(c *gin.Context)
+
+	CreateRatingReason(c *gin.Context)
+	GetRatingReasons(c *gin.Context)
+	UpdateRatingReason(c *gin.Context)
+
+	CreateControlCase(c *gin.Context)
+
+	GetDeadNotifications(c *gin.Context)
+	RetryNotification(c *gin.Context)
+	PreviewNotification(c *gin.Context)
 }
 
 type Public interface {
@@ -17,6 +37,7 @@ type Public interface {
 	CameraDelete(c *gin.Context)
 
 	CaseCreate(c *gin.Context)
+	CaseCreateBatch(c *gin.Context)
 
 	Refresh(c *gin.Context)
 }
@@ -27,7 +48,10 @@ type Specialists interface {
 
 	GetRating(c *gin.Context)
 	GetCasesByLevel(c *gin.Context)
+	TakeNextCase(c *gin.Context)
 
+	GetRatingReasons(c *gin.Context)
 	CreateRated(c *gin.Context)
+	SkipCase(c *gin.Context)
 	GetRatedSolved(c *gin.Context)
 }
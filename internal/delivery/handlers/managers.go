@@ -4,10 +4,14 @@ import (
 	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/models"
 	"github.com/gl1n0m3c/IT_LAB_INIT/internal/services"
+	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/plates"
 	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/tracing"
 	customErrors "github.com/gl1n0m3c/IT_LAB_INIT/pkg/utils/customerr"
 	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/utils/responses"
+	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/validators"
+	"github.com/go-playground/validator/v10"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
@@ -34,6 +38,7 @@ func InitManagerHandler(
 // GetFulCaseByID @Summary Retrieve a case by ID
 // @Description Retrieves a case by its ID and returns detailed information about the case.
 // @Description Field `rated_covers` could be null if there are no ratings
+// @Description Field `timeline` lists case events in order: creation, ratings, escalations, decisions, notifications and overrides.
 // @Tags managers
 // @Accept  json
 // @Produce  json
@@ -95,6 +100,9 @@ func (m managerHandler) GetFulCaseByID(c *gin.Context) {
 // GetSpecialistRating @Summary Retrieve specialists' ratings
 // @Description Retrieves a list of specialists ratings within a specified time range, paginated by a cursor.
 // @Description Time example (2023-04-12T15:04:05Z - without time zone / 2023-04-12T15:04:05+07:00 - with time zone)
+// @Description `total`, `correct` and `unknown` count ratings of regular cases against the specialists' consensus.
+// @Description `control_total`, `control_correct` and `accuracy` count ratings of control cases against their known answer,
+// @Description `accuracy` is null if the specialist rated no control cases in the range.
 // @Tags managers
 // @Accept  json
 // @Produce  json
@@ -206,3 +214,859 @@ func (m managerHandler) GetSpecialistRating(c *gin.Context) {
 
 	c.JSON(http.StatusOK, specialists)
 }
+
+// GetUnmatchedCases @Summary Retrieve unmatched cases
+// @Description Retrieves cases held because their transport has no contacts, paginated by a cursor.
+// @Description Returned cursor can be only int or null. It depends on existence of cases.
+// @Tags managers
+// @Accept  json
+// @Produce  json
+// @Param authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+// @Param cursor query int true "Cursor for pagination"
+// @Success 200 {object} models.UnmatchedCaseCursor "Successfully retrieved the unmatched cases"
+// @Failure 400 {object} responses.MessageResponse "Invalid query parameter or missing cursor"
+// @Failure 403 {object} responses.MessageResponse "JWT is invalid or expired"
+// @Failure 500 {object} responses.MessageResponse "Internal server error"
+// @Router /manager/get_unmatched_cases [get]
+func (m managerHandler) GetUnmatchedCases(c *gin.Context) {
+	ctx, span := m.tracer.Start(c.Request.Context(), tracing.GetUnmatchedCases)
+	defer span.End()
+
+	cursorStr, ok := c.GetQuery("cursor")
+	if !ok {
+		er := fmt.Errorf("bad `cursor` query provided")
+		span.RecordError(er, trace.WithAttributes(
+			attribute.String(tracing.QueryType, er.Error())),
+		)
+		span.SetStatus(codes.Error, er.Error())
+
+		c.JSON(http.StatusBadRequest, responses.NewMessageResponse(responses.ResponseBadQuery))
+		return
+	}
+
+	cursor, err := strconv.Atoi(cursorStr)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.QueryType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+
+		c.JSON(http.StatusBadRequest, responses.NewMessageResponse(responses.ResponseBadQuery))
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	cases, err := m.service.GetUnmatchedCases(ctx, cursor)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.GetUnmatchedCasesType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+
+		c.JSON(http.StatusInternalServerError, responses.NewMessageResponse(responses.Response500))
+		return
+	}
+
+	span.SetStatus(codes.Ok, tracing.SuccessfulCompleting)
+
+	c.JSON(http.StatusOK, cases)
+}
+
+// ResolveUnmatchedCase @Summary Resolve an unmatched case
+// @Description Attaches an unmatched case to an existing contact or creates a new one, then moves the case to review.
+// @Description `transport` replaces the recognized plate if the camera was wrong, by default the recognized plate is used.
+// @Description If `contacts` are provided, a new contact is created for the transport, otherwise the contact must already exist.
+// @Tags managers
+// @Accept  json
+// @Produce  json
+// @Param authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+// @Param resolve_data body models.UnmatchedCaseResolve true "Unmatched case resolution"
+// @Success 201 {object} responses.CreationIntResponse "Case moved to review, returning case ID"
+// @Failure 400 {object} responses.MessageResponse "Invalid input data"
+// @Failure 403 {object} responses.MessageResponse "JWT is invalid or expired"
+// @Failure 404 {object} responses.MessageResponse "Unmatched case or contact not found"
+// @Failure 409 {object} responses.MessageResponse "Contact for the transport already exists"
+// @Failure 500 {object} responses.MessageResponse "Internal server error"
+// @Router /manager/resolve_unmatched_case [post]
+func (m managerHandler) ResolveUnmatchedCase(c *gin.Context) {
+	var resolve models.UnmatchedCaseResolve
+
+	ctx, span := m.tracer.Start(c.Request.Context(), tracing.ResolveUnmatched)
+	defer span.End()
+
+	if err := c.ShouldBindJSON(&resolve); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+
+		c.JSON(http.StatusBadRequest, responses.NewMessageResponse(fmt.Sprintf(responses.Response400, err)))
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(resolve); err != nil {
+		customErrMsg := validators.CustomErrorMessage(err)
+
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ValidationType, customErrMsg)),
+		)
+		span.SetStatus(codes.Error, customErrMsg)
+
+		c.JSON(http.StatusBadRequest, responses.NewMessageResponse(customErrMsg))
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	createdCaseID, err := m.service.ResolveUnmatchedCase(ctx, resolve)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ResolveUnmatchedType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+
+		switch {
+		case errors.Is(err, plates.ErrBadFormat), errors.Is(err, plates.ErrBadNumber), errors.Is(err, plates.ErrBadRegion):
+			c.JSON(http.StatusBadRequest, responses.NewMessageResponse(err.Error()))
+			return
+		case errors.Is(err, customErrors.NoRowsUnmatchedCaseErr), errors.Is(err, customErrors.NoRowsContactErr):
+			c.JSON(http.StatusNotFound, responses.NewMessageResponse(err.Error()))
+			return
+		case errors.Is(err, customErrors.UniqueContactErr):
+			c.JSON(http.StatusConflict, responses.NewMessageResponse(err.Error()))
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, responses.NewMessageResponse(responses.Response500))
+			return
+		}
+	}
+
+	span.SetStatus(codes.Ok, tracing.SuccessfulCompleting)
+
+	c.JSON(http.StatusCreated, responses.CreationIntResponse{ID: createdCaseID})
+}
+
+// GetReviewCases @Summary Retrieve cases awaiting a manager decision
+// @Description Retrieves unsolved cases on which specialists of the maximum level (MAX_CASE_LEVEL) did not reach consensus, paginated by a cursor.
+// @Description Returned cursor can be only int or null. It depends on existence of cases.
+// @Tags managers
+// @Accept  json
+// @Produce  json
+// @Param authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+// @Param cursor query int true "Cursor for pagination"
+// @Success 200 {object} models.CaseCursor "Successfully retrieved the review cases"
+// @Failure 400 {object} responses.MessageResponse "Invalid query parameter or missing cursor"
+// @Failure 403 {object} responses.MessageResponse "JWT is invalid or expired"
+// @Failure 500 {object} responses.MessageResponse "Internal server error"
+// @Router /manager/get_review_cases [get]
+func (m managerHandler) GetReviewCases(c *gin.Context) {
+	ctx, span := m.tracer.Start(c.Request.Context(), tracing.GetReviewCases)
+	defer span.End()
+
+	cursorStr, ok := c.GetQuery("cursor")
+	if !ok {
+		er := fmt.Errorf("bad `cursor` query provided")
+		span.RecordError(er, trace.WithAttributes(
+			attribute.String(tracing.QueryType, er.Error())),
+		)
+		span.SetStatus(codes.Error, er.Error())
+
+		c.JSON(http.StatusBadRequest, responses.NewMessageResponse(responses.ResponseBadQuery))
+		return
+	}
+
+	cursor, err := strconv.Atoi(cursorStr)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.QueryType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+
+		c.JSON(http.StatusBadRequest, responses.NewMessageResponse(responses.ResponseBadQuery))
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	cases, err := m.service.GetReviewCases(ctx, cursor)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.GetReviewCasesType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+
+		c.JSON(http.StatusInternalServerError, responses.NewMessageResponse(responses.Response500))
+		return
+	}
+
+	span.SetStatus(codes.Ok, tracing.SuccessfulCompleting)
+
+	c.JSON(http.StatusOK, cases)
+}
+
+// GetCaseVotes @Summary Retrieve every vote of a case
+// @Description Retrieves all ratings of a case with the choice of each specialist, in the order they were given.
+// @Description Field `level` is the current level of the specialist.
+// @Tags managers
+// @Accept  json
+// @Produce  json
+// @Param authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+// @Param case_id query int true "ID of the case"
+// @Success 200 {object} []models.CaseVoteFul "Successfully retrieved the case votes"
+// @Failure 400 {object} responses.MessageResponse "Invalid query parameter or missing case_id"
+// @Failure 403 {object} responses.MessageResponse "JWT is invalid or expired"
+// @Failure 404 {object} responses.MessageResponse "Case not found"
+// @Failure 500 {object} responses.MessageResponse "Internal server error"
+// @Router /manager/get_case_votes [get]
+func (m managerHandler) GetCaseVotes(c *gin.Context) {
+	ctx, span := m.tracer.Start(c.Request.Context(), tracing.GetCaseVotes)
+	defer span.End()
+
+	caseIDStr, ok := c.GetQuery("case_id")
+	if !ok {
+		er := fmt.Errorf("bad `case_id` query provided")
+		span.RecordError(er, trace.WithAttributes(
+			attribute.String(tracing.QueryType, er.Error())),
+		)
+		span.SetStatus(codes.Error, er.Error())
+
+		c.JSON(http.StatusBadRequest, responses.NewMessageResponse(responses.ResponseBadQuery))
+		return
+	}
+
+	caseID, err := strconv.Atoi(caseIDStr)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.QueryType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+
+		c.JSON(http.StatusBadRequest, responses.NewMessageResponse(responses.ResponseBadQuery))
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	votes, err := m.service.GetCaseVotes(ctx, caseID)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.GetCaseVotesType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+
+		if errors.Is(err, customErrors.NoRowsCaseErr) {
+			c.JSON(http.StatusNotFound, responses.NewMessageResponse(err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, responses.NewMessageResponse(responses.Response500))
+		return
+	}
+
+	span.SetStatus(codes.Ok, tracing.SuccessfulCompleting)
+
+	c.JSON(http.StatusOK, votes)
+}
+
+// ResolveReviewCase @Summary Issue the final decision on a review case
+// @Description Solves a case from the review queue with the manager's choice. Ratings are marked correct or incorrect
+// @Description against this choice, specialists' streaks are updated and the fine notification is queued for sending, as after specialists' consensus.
+// @Tags managers
+// @Accept  json
+// @Produce  json
+// @Param authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+// @Param decision body models.CaseReviewDecision true "Manager decision"
+// @Success 200 {object} responses.MessageResponse "Case solved"
+// @Failure 400 {object} responses.MessageResponse "Invalid input data"
+// @Failure 403 {object} responses.MessageResponse "JWT is invalid or expired"
+// @Failure 404 {object} responses.MessageResponse "Case not found"
+// @Failure 409 {object} responses.MessageResponse "Case is not awaiting a manager decision"
+// @Failure 500 {object} responses.MessageResponse "Internal server error"
+// @Router /manager/resolve_review_case [post]
+func (m managerHandler) ResolveReviewCase(c *gin.Context) {
+	var decision models.CaseReviewDecision
+
+	ctx, span := m.tracer.Start(c.Request.Context(), tracing.ResolveReviewCase)
+	defer span.End()
+
+	if err := c.ShouldBindJSON(&decision); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+
+		c.JSON(http.StatusBadRequest, responses.NewMessageResponse(fmt.Sprintf(responses.Response400, err)))
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(decision); err != nil {
+		customErrMsg := validators.CustomErrorMessage(err)
+
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ValidationType, customErrMsg)),
+		)
+		span.SetStatus(codes.Error, customErrMsg)
+
+		c.JSON(http.StatusBadRequest, responses.NewMessageResponse(customErrMsg))
+		return
+	}
+
+	managerID := c.GetInt("userID")
+
+	span.AddEvent(tracing.CallToService)
+	err := m.service.ResolveReviewCase(ctx, managerID, decision)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ResolveReviewCaseType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+
+		switch {
+		case errors.Is(err, customErrors.NoRowsCaseErr):
+			c.JSON(http.StatusNotFound, responses.NewMessageResponse(err.Error()))
+			return
+		case errors.Is(err, customErrors.CaseNotInReview):
+			c.JSON(http.StatusConflict, responses.NewMessageResponse(err.Error()))
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, responses.NewMessageResponse(responses.Response500))
+			return
+		}
+	}
+
+	span.SetStatus(codes.Ok, tracing.SuccessfulCompleting)
+
+	c.JSON(http.StatusOK, responses.NewMessageResponse(fmt.Sprintf(responses.ResponseSuccessUpdate, "case")))
+}
+
+// OverrideCase @Summary Overturn or reopen a solved case
+// @Description Revises a solved case with a mandatory justification. `overturn` replaces the decision with the opposite one,
+// @Description `reopen` returns the case for rating at the next level, or to the review queue from the maximum level.
+// @Description Ratings statuses and specialists' streaks are recomputed, the fine or cancellation notification is queued for sending.
+// @Description Every override is kept in the case history returned by /manager/get_case.
+// @Tags managers
+// @Accept  json
+// @Produce  json
+// @Param authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+// @Param override body models.CaseOverrideCreate true "Override data, action is `overturn` or `reopen`"
+// @Success 201 {object} models.CaseOverride "Case revised, returning the override record"
+// @Failure 400 {object} responses.MessageResponse "Invalid input data"
+// @Failure 403 {object} responses.MessageResponse "JWT is invalid or expired"
+// @Failure 404 {object} responses.MessageResponse "Case not found"
+// @Failure 409 {object} responses.MessageResponse "Case is not solved"
+// @Failure 500 {object} responses.MessageResponse "Internal server error"
+// @Router /manager/override_case [post]
+func (m managerHandler) OverrideCase(c *gin.Context) {
+	var override models.CaseOverrideCreate
+
+	ctx, span := m.tracer.Start(c.Request.Context(), tracing.OverrideCase)
+	defer span.End()
+
+	if err := c.ShouldBindJSON(&override); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+
+		c.JSON(http.StatusBadRequest, responses.NewMessageResponse(fmt.Sprintf(responses.Response400, err)))
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(override); err != nil {
+		customErrMsg := validators.CustomErrorMessage(err)
+
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ValidationType, customErrMsg)),
+		)
+		span.SetStatus(codes.Error, customErrMsg)
+
+		c.JSON(http.StatusBadRequest, responses.NewMessageResponse(customErrMsg))
+		return
+	}
+
+	managerID := c.GetInt("userID")
+
+	span.AddEvent(tracing.CallToService)
+	caseOverride, err := m.service.OverrideCase(ctx, managerID, override)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.OverrideCaseType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+
+		switch {
+		case errors.Is(err, customErrors.NoRowsCaseErr):
+			c.JSON(http.StatusNotFound, responses.NewMessageResponse(err.Error()))
+			return
+		case errors.Is(err, customErrors.CaseNotSolved):
+			c.JSON(http.StatusConflict, responses.NewMessageResponse(err.Error()))
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, responses.NewMessageResponse(responses.Response500))
+			return
+		}
+	}
+
+	span.SetStatus(codes.Ok, tracing.SuccessfulCompleting)
+
+	c.JSON(http.StatusCreated, caseOverride)
+}
+
+// GetCameraSkipStats @Summary Retrieve skip statistics per camera
+// @Description Retrieves how often specialists skip cases from each camera: number of cases, skipped cases, all skips,
+// @Description share of skipped cases and skips by reason. Cameras with the highest share of skipped cases go first.
+// @Tags managers
+// @Accept  json
+// @Produce  json
+// @Param authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+// @Success 200 {object} []models.CameraSkipStats "Successfully retrieved the skip statistics"
+// @Failure 403 {object} responses.MessageResponse "JWT is invalid or expired"
+// @Failure 500 {object} responses.MessageResponse "Internal server error"
+// @Router /manager/get_camera_skip_stats [get]
+func (m managerHandler) GetCameraSkipStats(c *gin.Context) {
+	ctx, span := m.tracer.Start(c.Request.Context(), tracing.GetCameraSkipStats)
+	defer span.End()
+
+	span.AddEvent(tracing.CallToService)
+	stats, err := m.service.GetCameraSkipStats(ctx)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.GetCameraSkipStatsType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+
+		c.JSON(http.StatusInternalServerError, responses.NewMessageResponse(responses.Response500))
+		return
+	}
+
+	span.SetStatus(codes.Ok, tracing.SuccessfulCompleting)
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// CreateRatingReason @Summary Add a rating reason
+// @Description Adds a reason code to the catalogue. Specialists may attach active reasons to their ratings.
+// @Tags managers
+// @Accept  json
+// @Produce  json
+// @Param authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+// @Param reason body models.RatingReasonCreate true "Reason code and description"
+// @Success 201 {object} models.RatingReasonCreate "Reason created"
+// @Failure 400 {object} responses.MessageResponse "Invalid input data"
+// @Failure 403 {object} responses.MessageResponse "JWT is invalid or expired"
+// @Failure 409 {object} responses.MessageResponse "Reason code already exists"
+// @Failure 500 {object} responses.MessageResponse "Internal server error"
+// @Router /manager/create_rating_reason [post]
+func (m managerHandler) CreateRatingReason(c *gin.Context) {
+	var reason models.RatingReasonCreate
+
+	ctx, span := m.tracer.Start(c.Request.Context(), tracing.CreateRatingReason)
+	defer span.End()
+
+	if err := c.ShouldBindJSON(&reason); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+
+		c.JSON(http.StatusBadRequest, responses.NewMessageResponse(fmt.Sprintf(responses.Response400, err)))
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(reason); err != nil {
+		customErrMsg := validators.CustomErrorMessage(err)
+
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ValidationType, customErrMsg)),
+		)
+		span.SetStatus(codes.Error, customErrMsg)
+
+		c.JSON(http.StatusBadRequest, responses.NewMessageResponse(customErrMsg))
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	err := m.service.CreateRatingReason(ctx, reason)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.CreateRatingReasonType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+
+		switch {
+		case errors.Is(err, customErrors.UniqueRatingReasonErr):
+			c.JSON(http.StatusConflict, responses.NewMessageResponse(err.Error()))
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, responses.NewMessageResponse(responses.Response500))
+			return
+		}
+	}
+
+	span.SetStatus(codes.Ok, tracing.SuccessfulCompleting)
+
+	c.JSON(http.StatusCreated, reason)
+}
+
+// GetRatingReasons @Summary Retrieve all rating reasons
+// @Description Retrieves the whole reason catalogue, including deactivated reasons.
+// @Tags managers
+// @Accept  json
+// @Produce  json
+// @Param authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+// @Success 200 {object} []models.RatingReason "Successfully retrieved the rating reasons"
+// @Failure 403 {object} responses.MessageResponse "JWT is invalid or expired"
+// @Failure 500 {object} responses.MessageResponse "Internal server error"
+// @Router /manager/get_rating_reasons [get]
+func (m managerHandler) GetRatingReasons(c *gin.Context) {
+	ctx, span := m.tracer.Start(c.Request.Context(), tracing.GetRatingReasons)
+	defer span.End()
+
+	span.AddEvent(tracing.CallToService)
+	reasons, err := m.service.GetRatingReasons(ctx)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.GetRatingReasonsType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+
+		c.JSON(http.StatusInternalServerError, responses.NewMessageResponse(responses.Response500))
+		return
+	}
+
+	span.SetStatus(codes.Ok, tracing.SuccessfulCompleting)
+
+	c.JSON(http.StatusOK, reasons)
+}
+
+// UpdateRatingReason @Summary Update a rating reason
+// @Description Changes the description of a reason or (de)activates it. Deactivated reasons can't be attached to new ratings,
+// @Description ratings which already have them are kept unchanged.
+// @Tags managers
+// @Accept  json
+// @Produce  json
+// @Param authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+// @Param reason body models.RatingReasonUpdate true "Reason code and changed fields"
+// @Success 200 {object} responses.MessageResponse "Reason updated"
+// @Failure 400 {object} responses.MessageResponse "Invalid input data"
+// @Failure 403 {object} responses.MessageResponse "JWT is invalid or expired"
+// @Failure 404 {object} responses.MessageResponse "Reason not found"
+// @Failure 500 {object} responses.MessageResponse "Internal server error"
+// @Router /manager/update_rating_reason [put]
+func (m managerHandler) UpdateRatingReason(c *gin.Context) {
+	var reason models.RatingReasonUpdate
+
+	ctx, span := m.tracer.Start(c.Request.Context(), tracing.UpdateRatingReason)
+	defer span.End()
+
+	if err := c.ShouldBindJSON(&reason); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+
+		c.JSON(http.StatusBadRequest, responses.NewMessageResponse(fmt.Sprintf(responses.Response400, err)))
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(reason); err != nil {
+		customErrMsg := validators.CustomErrorMessage(err)
+
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ValidationType, customErrMsg)),
+		)
+		span.SetStatus(codes.Error, customErrMsg)
+
+		c.JSON(http.StatusBadRequest, responses.NewMessageResponse(customErrMsg))
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	err := m.service.UpdateRatingReason(ctx, reason)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.UpdateRatingReasonType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+
+		switch {
+		case errors.Is(err, customErrors.NoRowsRatingReasonErr):
+			c.JSON(http.StatusNotFound, responses.NewMessageResponse(err.Error()))
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, responses.NewMessageResponse(responses.Response500))
+			return
+		}
+	}
+
+	span.SetStatus(codes.Ok, tracing.SuccessfulCompleting)
+
+	c.JSON(http.StatusOK, responses.NewMessageResponse(fmt.Sprintf(responses.ResponseSuccessUpdate, "rating reason")))
+}
+
+// CreateControlCase @Summary Seed a control case with a known answer
+// @Description Adds a case with a known answer to the specialists' queue. Specialists get it like any other case,
+// @Description but its ratings never reach consensus, change the case or send fines. They are compared with `choice`
+// @Description and returned as accuracy in /manager/get_specialists_rating.
+// @Tags managers
+// @Accept  json
+// @Produce  json
+// @Param authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+// @Param control_case body models.ControlCaseCreate true "Case data and the known answer"
+// @Success 201 {object} responses.CreationIntResponse "Control case created, returning case ID"
+// @Failure 400 {object} responses.MessageResponse "Invalid input data"
+// @Failure 403 {object} responses.MessageResponse "JWT is invalid or expired"
+// @Failure 422 {object} responses.MissingReferencesResponse "Camera, violation or transport contacts do not exist"
+// @Failure 500 {object} responses.MessageResponse "Internal server error"
+// @Router /manager/create_control_case [post]
+func (m managerHandler) CreateControlCase(c *gin.Context) {
+	var control models.ControlCaseCreate
+
+	ctx, span := m.tracer.Start(c.Request.Context(), tracing.CreateControlCase)
+	defer span.End()
+
+	if err := c.ShouldBindJSON(&control); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+
+		c.JSON(http.StatusBadRequest, responses.NewMessageResponse(fmt.Sprintf(responses.Response400, err)))
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(control); err != nil {
+		customErrMsg := validators.CustomErrorMessage(err)
+
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ValidationType, customErrMsg)),
+		)
+		span.SetStatus(codes.Error, customErrMsg)
+
+		c.JSON(http.StatusBadRequest, responses.NewMessageResponse(customErrMsg))
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	createdCaseID, err := m.service.CreateControlCase(ctx, control)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.CreateControlCaseType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+
+		var refErr customErrors.MissingReferencesErr
+		switch {
+		case errors.Is(err, plates.ErrBadFormat), errors.Is(err, plates.ErrBadNumber), errors.Is(err, plates.ErrBadRegion):
+			c.JSON(http.StatusBadRequest, responses.NewMessageResponse(err.Error()))
+			return
+		case errors.As(err, &refErr):
+			c.JSON(http.StatusUnprocessableEntity, responses.NewMissingReferencesResponse(refErr))
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, responses.NewMessageResponse(responses.Response500))
+			return
+		}
+	}
+
+	span.SetStatus(codes.Ok, tracing.SuccessfulCompleting)
+
+	c.JSON(http.StatusCreated, responses.CreationIntResponse{ID: createdCaseID})
+}
+
+// GetDeadNotifications @Summary Retrieve notifications that could not be sent
+// @Description Retrieves fine notifications which failed on every attempt and will not be sent again, paginated by a cursor.
+// @Description Field `last_error` contains the error of the last attempt. Returned cursor can be only int or null.
+// @Tags managers
+// @Accept  json
+// @Produce  json
+// @Param authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+// @Param cursor query int true "Cursor for pagination"
+// @Success 200 {object} models.NotificationCursor "Successfully retrieved the notifications"
+// @Failure 400 {object} responses.MessageResponse "Invalid query parameter or missing cursor"
+// @Failure 403 {object} responses.MessageResponse "JWT is invalid or expired"
+// @Failure 500 {object} responses.MessageResponse "Internal server error"
+// @Router /manager/get_dead_notifications [get]
+func (m managerHandler) GetDeadNotifications(c *gin.Context) {
+	ctx, span := m.tracer.Start(c.Request.Context(), tracing.GetDeadNotifications)
+	defer span.End()
+
+	cursorStr, ok := c.GetQuery("cursor")
+	if !ok {
+		er := fmt.Errorf("bad `cursor` query provided")
+		span.RecordError(er, trace.WithAttributes(
+			attribute.String(tracing.QueryType, er.Error())),
+		)
+		span.SetStatus(codes.Error, er.Error())
+
+		c.JSON(http.StatusBadRequest, responses.NewMessageResponse(responses.ResponseBadQuery))
+		return
+	}
+
+	cursor, err := strconv.Atoi(cursorStr)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.QueryType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+
+		c.JSON(http.StatusBadRequest, responses.NewMessageResponse(responses.ResponseBadQuery))
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	notifications, err := m.service.GetDeadNotifications(ctx, cursor)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.GetDeadNotificationsType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+
+		c.JSON(http.StatusInternalServerError, responses.NewMessageResponse(responses.Response500))
+		return
+	}
+
+	span.SetStatus(codes.Ok, tracing.SuccessfulCompleting)
+
+	c.JSON(http.StatusOK, notifications)
+}
+
+// RetryNotification @Summary Send a failed notification again
+// @Description Returns a notification which failed on every attempt to the outbox with a fresh attempt counter.
+// @Tags managers
+// @Accept  json
+// @Produce  json
+// @Param authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+// @Param retry body models.NotificationRetry true "Notification ID"
+// @Success 200 {object} responses.MessageResponse "Notification returned to the outbox"
+// @Failure 400 {object} responses.MessageResponse "Invalid input data"
+// @Failure 403 {object} responses.MessageResponse "JWT is invalid or expired"
+// @Failure 404 {object} responses.MessageResponse "Failed notification not found"
+// @Failure 500 {object} responses.MessageResponse "Internal server error"
+// @Router /manager/retry_notification [post]
+func (m managerHandler) RetryNotification(c *gin.Context) {
+	var retry models.NotificationRetry
+
+	ctx, span := m.tracer.Start(c.Request.Context(), tracing.RetryNotification)
+	defer span.End()
+
+	if err := c.ShouldBindJSON(&retry); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+
+		c.JSON(http.StatusBadRequest, responses.NewMessageResponse(fmt.Sprintf(responses.Response400, err)))
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(retry); err != nil {
+		customErrMsg := validators.CustomErrorMessage(err)
+
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ValidationType, customErrMsg)),
+		)
+		span.SetStatus(codes.Error, customErrMsg)
+
+		c.JSON(http.StatusBadRequest, responses.NewMessageResponse(customErrMsg))
+		return
+	}
+
+	span.AddEvent(tracing.CallToService)
+	err := m.service.RetryNotification(ctx, retry.NotificationID)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.RetryNotificationType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+
+		switch {
+		case errors.Is(err, customErrors.NoRowsNotificationErr):
+			c.JSON(http.StatusNotFound, responses.NewMessageResponse(err.Error()))
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, responses.NewMessageResponse(responses.Response500))
+			return
+		}
+	}
+
+	span.SetStatus(codes.Ok, tracing.SuccessfulCompleting)
+
+	c.JSON(http.StatusOK, responses.NewMessageResponse(fmt.Sprintf(responses.ResponseSuccessUpdate, "notification")))
+}
+
+// PreviewNotification @Summary Preview the notification of a case
+// @Description Renders the notification of a case exactly as the offender would receive it, without sending it.
+// @Description Templates are chosen by the violation type and the language: `language` overrides the language
+// @Description from the owner's contacts. Field `photo` is the photo attached to the email.
+// @Tags managers
+// @Accept  json
+// @Produce  json
+// @Param authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+// @Param case_id query int true "ID of the case"
+// @Param kind query string false "Notification kind" Enums(fine, fine_cancel) default(fine)
+// @Param language query string false "Notification language" Enums(ru, en)
+// @Success 200 {object} models.NotificationPreview "Successfully rendered the notification"
+// @Failure 400 {object} responses.MessageResponse "Invalid query parameter, unknown kind or language"
+// @Failure 403 {object} responses.MessageResponse "JWT is invalid or expired"
+// @Failure 404 {object} responses.MessageResponse "Case not found"
+// @Failure 500 {object} responses.MessageResponse "Internal server error"
+// @Router /manager/preview_notification [get]
+func (m managerHandler) PreviewNotification(c *gin.Context) {
+	ctx, span := m.tracer.Start(c.Request.Context(), tracing.PreviewNotification)
+	defer span.End()
+
+	caseIDStr, ok := c.GetQuery("case_id")
+	if !ok {
+		er := fmt.Errorf("bad `case_id` query provided")
+		span.RecordError(er, trace.WithAttributes(
+			attribute.String(tracing.QueryType, er.Error())),
+		)
+		span.SetStatus(codes.Error, er.Error())
+
+		c.JSON(http.StatusBadRequest, responses.NewMessageResponse(responses.ResponseBadQuery))
+		return
+	}
+
+	caseID, err := strconv.Atoi(caseIDStr)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.QueryType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+
+		c.JSON(http.StatusBadRequest, responses.NewMessageResponse(responses.ResponseBadQuery))
+		return
+	}
+
+	kind := c.DefaultQuery("kind", models.NotificationFine)
+	language := c.Query("language")
+
+	span.AddEvent(tracing.CallToService)
+	preview, err := m.service.PreviewNotification(ctx, caseID, kind, language)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.PreviewNotificationType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+
+		switch {
+		case errors.Is(err, customErrors.NoRowsCaseErr):
+			c.JSON(http.StatusNotFound, responses.NewMessageResponse(err.Error()))
+			return
+		case errors.Is(err, customErrors.UnknownNotificationKindErr), errors.Is(err, customErrors.UnknownLanguageErr):
+			c.JSON(http.StatusBadRequest, responses.NewMessageResponse(err.Error()))
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, responses.NewMessageResponse(responses.Response500))
+			return
+		}
+	}
+
+	span.SetStatus(codes.Ok, tracing.SuccessfulCompleting)
+
+	c.JSON(http.StatusOK, preview)
+}
@@ -1,7 +1,7 @@
 package handlers
 
 import (
-	"bytes"
+	"archive/zip"
 	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
@@ -9,6 +9,7 @@ import (
 	"github.com/gl1n0m3c/IT_LAB_INIT/internal/models"
 	_ "github.com/gl1n0m3c/IT_LAB_INIT/internal/models/swagger"
 	"github.com/gl1n0m3c/IT_LAB_INIT/internal/services"
+	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/config"
 	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/database"
 	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/tracing"
 	customErrors "github.com/gl1n0m3c/IT_LAB_INIT/pkg/utils/customerr"
@@ -18,19 +19,32 @@ import (
 	"github.com/go-playground/validator/v10"
 	"github.com/gofrs/uuid"
 	"github.com/guregu/null"
+	"github.com/spf13/viper"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
+	"io"
+	"mime/multipart"
 	"net/http"
+	"os"
+	"path"
 	"path/filepath"
-	"strconv"
+	"sort"
+	"strings"
 )
 
+// Ограничение на размер фото - 2МБ
+const maxPhotoSize = 2 << 20
+
+const defaultBatchMaxItems = 100
+
 type publicHandler struct {
-	service services.Public
-	session database.Session
-	JWTUtil jwt.JWT
-	tracer  trace.Tracer
+	service       services.Public
+	session       database.Session
+	JWTUtil       jwt.JWT
+	tracer        trace.Tracer
+	decoderLimits decoder.Limits
+	batchMaxItems int
 }
 
 func InitPublicHandler(
@@ -39,11 +53,22 @@ func InitPublicHandler(
 	JWTUtil jwt.JWT,
 	tracer trace.Tracer,
 ) Public {
+	batchMaxItems := viper.GetInt(config.BatchMaxItems)
+	if batchMaxItems <= 0 {
+		batchMaxItems = defaultBatchMaxItems
+	}
+
 	return publicHandler{
 		service: service,
 		session: session,
 		JWTUtil: JWTUtil,
 		tracer:  tracer,
+		decoderLimits: decoder.Limits{
+			MaxDepth: viper.GetInt(config.DecoderMaxDepth),
+			MaxSize:  viper.GetInt(config.DecoderMaxSize),
+			MaxKeys:  viper.GetInt(config.DecoderMaxKeys),
+		},
+		batchMaxItems: batchMaxItems,
 	}
 }
 
@@ -468,14 +493,19 @@ func (p publicHandler) CameraDelete(c *gin.Context) {
 
 // CaseCreate creates a new case and returns its ID upon successful creation.
 // @Summary Case Creation
-// @Description Creates a new case with a photo (.jpeg / .jpg / .png / .svg) and case data in byte string.
+// @Description Creates a new case with a photo (.jpeg / .jpg / .png / .svg) and case data in byte string. The first two bytes are the header: protocol version and flags (optional CRC32/CRC16 trailer).
 // @Tags public
 // @Accept multipart/form-data
 // @Produce json
 // @Param photo formData file true "Photo of the case"
-// @Param byte_string formData string true "Case data in byte string format"
+// @Param byte_string formData string false "Case data as text, bit string by default"
+// @Param payload formData file false "Case data as a file, raw bytes by default"
+// @Param encoding formData string false "Case data encoding: bits, hex, base64 or raw. Defaults to the payload Content-Type"
 // @Success 201 {object} responses.CreationIntResponse "Successful creation, returning case ID"
+// @Success 202 {object} responses.UnmatchedCaseResponse "Transport has no contacts, the case is held until a manager resolves it"
 // @Failure 400 {object} responses.MessageResponse "Invalid input"
+// @Failure 413 {object} responses.MessageResponse "Case data is too large"
+// @Failure 422 {object} responses.MissingReferencesResponse "Camera or violation of the case does not exist"
 // @Failure 500 {object} responses.MessageResponse "Internal server error"
 // @Router /public/case_create [post]
 func (p publicHandler) CaseCreate(c *gin.Context) {
@@ -531,40 +561,50 @@ func (p publicHandler) CaseCreate(c *gin.Context) {
 		return
 	}
 
-	bitString := c.PostForm("byte_string")
-	if bitString == "" {
-		c.JSON(http.StatusBadRequest, responses.NewMessageResponse(responses.ResponseNoByteStringProvided))
-		return
-	}
+	// Если случай не будет создан, сохраненное фото удаляется
+	caseCreated := false
+	defer func() {
+		if !caseCreated {
+			_ = os.Remove(".." + filePath)
+		}
+	}()
 
-	var dataBytes []byte
-	for i := 0; i+8 <= len(bitString); i += 8 {
-		byteString := bitString[i : i+8]
-		byteValue, err := strconv.ParseUint(byteString, 2, 8)
-		if err != nil {
-			span.RecordError(err, trace.WithAttributes(
-				attribute.String(tracing.InternalErr, err.Error())),
-			)
-			span.SetStatus(codes.Error, err.Error())
+	dataBytes, err := readPayload(c)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.DecoderType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
 
-			c.JSON(http.StatusInternalServerError, responses.NewMessageResponse("Ошибка при преобразовании битовой строки в байты"))
+		switch {
+		case errors.Is(err, customErrors.NoPayloadErr):
+			c.JSON(http.StatusBadRequest, responses.NewMessageResponse(responses.ResponseNoByteStringProvided))
+			return
+		case errors.Is(err, customErrors.PayloadTooLargeErr):
+			c.JSON(http.StatusRequestEntityTooLarge, responses.NewMessageResponse(responses.ResponseBadPayloadSize))
+			return
+		case errors.Is(err, decoder.ErrUnknownEncoding), errors.Is(err, decoder.ErrBadBitString),
+			errors.Is(err, decoder.ErrBadHex), errors.Is(err, decoder.ErrBadBase64):
+			c.JSON(http.StatusBadRequest, responses.NewMessageResponse(fmt.Sprintf(responses.ResponseBadByteStringReason, err)))
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, responses.NewMessageResponse(responses.Response500))
 			return
 		}
-		dataBytes = append(dataBytes, byte(byteValue))
 	}
 
-	if len(dataBytes) <= 7 {
-		c.JSON(http.StatusBadRequest, responses.NewMessageResponse(responses.ResponseBadByteString))
-		return
-	}
-
-	result, err := decoder.Decoder(bytes.NewBuffer(dataBytes[2:]))
+	result, err := decoder.DecodePayload(dataBytes, p.decoderLimits)
 	if err != nil {
 		span.RecordError(err, trace.WithAttributes(
 			attribute.String(tracing.DecoderType, err.Error())),
 		)
 		span.SetStatus(codes.Error, err.Error())
 
+		var decodeErr *decoder.DecodeError
+		if errors.As(err, &decodeErr) {
+			c.JSON(http.StatusBadRequest, responses.NewMessageResponse(fmt.Sprintf(responses.ResponseBadByteStringReason, decodeErr)))
+			return
+		}
 		c.JSON(http.StatusBadRequest, responses.NewMessageResponse(responses.ResponseBadByteString))
 		return
 	}
@@ -593,20 +633,158 @@ func (p publicHandler) CaseCreate(c *gin.Context) {
 	caseData.PhotoUrl = filePath
 
 	span.AddEvent(tracing.CallToService)
-	createdCaseID, err := p.service.CaseCreate(ctx, caseData)
+	created, err := p.service.CaseCreate(ctx, caseData)
 	if err != nil {
 		span.RecordError(err, trace.WithAttributes(
 			attribute.String(tracing.CaseCreateType, err.Error())),
 		)
 		span.SetStatus(codes.Error, err.Error())
 
+		var refErr customErrors.MissingReferencesErr
+		if errors.As(err, &refErr) {
+			c.JSON(http.StatusUnprocessableEntity, responses.NewMissingReferencesResponse(refErr))
+			return
+		}
 		c.JSON(http.StatusInternalServerError, responses.NewMessageResponse(responses.Response500))
 		return
 	}
+	caseCreated = true
 
 	span.SetStatus(codes.Ok, tracing.SuccessfulCompleting)
 
-	c.JSON(http.StatusCreated, responses.CreationIntResponse{ID: createdCaseID})
+	if created.Unmatched {
+		c.JSON(http.StatusAccepted, responses.UnmatchedCaseResponse{UnmatchedID: created.ID, Message: responses.ResponseCaseUnmatched})
+		return
+	}
+	c.JSON(http.StatusCreated, responses.CreationIntResponse{ID: created.ID})
+}
+
+// CaseCreateBatch creates cases uploaded in one request and returns a result for every case.
+// @Summary Batch Case Creation
+// @Description Creates cases from a zip archive or from lists of photos and case data.
+// @Description In the archive the photo and the case data of one case share a file name: `001.jpg` + `001.bin`.
+// @Description Case data extension sets its encoding: .bin (raw), .hex, .b64 / .base64, .bits / .txt (bit string).
+// @Description Without an archive `photos` are paired by order with `payloads` files or `byte_strings` fields.
+// @Description Cases whose transport has no contacts are held with `unmatched_id` until a manager resolves them.
+// @Tags public
+// @Accept multipart/form-data
+// @Produce json
+// @Param archive formData file false "Zip archive with photos and case data"
+// @Param photos formData []file false "Photos of the cases" collectionFormat(multi)
+// @Param payloads formData []file false "Case data files in the order of photos" collectionFormat(multi)
+// @Param byte_strings formData []string false "Case data as text in the order of photos" collectionFormat(multi)
+// @Param encoding formData string false "Case data encoding for all cases: bits, hex, base64 or raw"
+// @Success 200 {object} models.CaseBatchResult "Result for every case: created ID, unmatched ID or error"
+// @Failure 400 {object} responses.MessageResponse "Invalid input"
+// @Failure 500 {object} responses.MessageResponse "Internal server error"
+// @Router /public/case_create_batch [post]
+func (p publicHandler) CaseCreateBatch(c *gin.Context) {
+	ctx, span := p.tracer.Start(c.Request.Context(), tracing.CaseCreateBatch)
+	defer span.End()
+
+	encoding := c.PostForm("encoding")
+
+	archive, err := c.FormFile("archive")
+	var items []batchItem
+	switch {
+	case err == nil:
+		items, err = readBatchArchive(archive, encoding, p.batchMaxItems)
+	case errors.Is(err, http.ErrMissingFile):
+		items, err = readBatchList(c, encoding, p.batchMaxItems)
+	}
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.FileType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+
+		switch {
+		case errors.Is(err, customErrors.NoPayloadErr), errors.Is(err, customErrors.BadBatchArchiveErr),
+			errors.Is(err, customErrors.BatchTooLargeErr), errors.Is(err, customErrors.BatchMismatchErr):
+			c.JSON(http.StatusBadRequest, responses.NewMessageResponse(err.Error()))
+			return
+		case errors.Is(err, http.ErrNotMultipart):
+			c.JSON(http.StatusBadRequest, responses.NewMessageResponse(fmt.Sprintf(responses.Response400, err)))
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, responses.NewMessageResponse(responses.Response500))
+			return
+		}
+	}
+
+	result := models.CaseBatchResult{Items: make([]models.CaseBatchItem, len(items))}
+
+	var cases []models.CaseBase
+	var caseIndexes []int
+	for i, item := range items {
+		result.Items[i] = models.CaseBatchItem{Index: i, Name: item.name}
+
+		var caseData models.CaseBase
+		if item.err == nil {
+			caseData, item.err = p.decodeCase(item.payload)
+		}
+		if item.err == nil {
+			caseData.PhotoUrl, item.err = saveCasePhoto(item.photo, item.photoExt)
+		}
+		if item.err != nil {
+			result.Items[i].Error = item.err.Error()
+			continue
+		}
+
+		cases = append(cases, caseData)
+		caseIndexes = append(caseIndexes, i)
+	}
+
+	if len(cases) > 0 {
+		span.AddEvent(tracing.CallToService)
+		created, errs, err := p.service.CaseCreateBatch(ctx, cases)
+		if err != nil {
+			span.RecordError(err, trace.WithAttributes(
+				attribute.String(tracing.CaseCreateBatchType, err.Error())),
+			)
+			span.SetStatus(codes.Error, err.Error())
+
+			for _, caseData := range cases {
+				_ = os.Remove(".." + caseData.PhotoUrl)
+			}
+
+			c.JSON(http.StatusInternalServerError, responses.NewMessageResponse(responses.Response500))
+			return
+		}
+
+		for j, i := range caseIndexes {
+			var refErr customErrors.MissingReferencesErr
+			switch {
+			case errors.As(errs[j], &refErr):
+				result.Items[i].Error = refErr.Error()
+				result.Items[i].Missing = refErr.References
+			case errs[j] != nil:
+				result.Items[i].Error = responses.ResponseCaseNotCreated
+			case created[j].Unmatched:
+				result.Items[i].UnmatchedID = created[j].ID
+				continue
+			default:
+				result.Items[i].ID = created[j].ID
+				continue
+			}
+			_ = os.Remove(".." + cases[j].PhotoUrl)
+		}
+	}
+
+	for _, item := range result.Items {
+		switch {
+		case item.Error != "":
+			result.Failed++
+		case item.UnmatchedID != 0:
+			result.Unmatched++
+		default:
+			result.Created++
+		}
+	}
+
+	span.SetStatus(codes.Ok, tracing.SuccessfulCompleting)
+
+	c.JSON(http.StatusOK, result)
 }
 
 // Refresh updates access and refresh tokens
@@ -655,3 +833,285 @@ func (p publicHandler) Refresh(c *gin.Context) {
 
 	c.JSON(http.StatusOK, responses.NewJWTRefreshResponse(newAccessToken, newRefreshToken))
 }
+
+// readPayload возвращает данные камеры из файла `payload` или поля `byte_string`.
+// Способ кодирования задается полем `encoding`, иначе определяется по Content-Type файла;
+// поле `byte_string` по умолчанию содержит битовую строку, как у старых камер
+func readPayload(c *gin.Context) ([]byte, error) {
+	encoding := c.PostForm("encoding")
+
+	file, err := c.FormFile("payload")
+	switch {
+	case err == nil:
+		return readPayloadFile(file, encoding)
+	case !errors.Is(err, http.ErrMissingFile):
+		return nil, err
+	}
+
+	return decodeByteString(c.PostForm("byte_string"), encoding)
+}
+
+func readPayloadFile(file *multipart.FileHeader, encoding string) ([]byte, error) {
+	if encoding == "" {
+		encoding = decoder.EncodingByContentType(file.Header.Get("Content-Type"))
+	}
+	if encoding == "" {
+		encoding = decoder.EncodingRaw
+	}
+
+	reader, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	data, err := readLimited(reader, payloadMaxSize(encoding), customErrors.PayloadTooLargeErr)
+	if err != nil {
+		return nil, err
+	}
+
+	return decoder.DecodeEncoding(data, encoding)
+}
+
+func decodeByteString(byteString, encoding string) ([]byte, error) {
+	if byteString == "" {
+		return nil, customErrors.NoPayloadErr
+	}
+	if encoding == "" {
+		encoding = decoder.EncodingBits
+	}
+
+	return decoder.DecodeEncoding([]byte(byteString), encoding)
+}
+
+// payloadMaxSize возвращает допустимый размер закодированных данных, битовая строка в 8 раз длиннее исходных
+func payloadMaxSize(encoding string) int64 {
+	if encoding == decoder.EncodingRaw {
+		return decoder.MaxPayloadSize
+	}
+	return decoder.MaxPayloadSize * 8
+}
+
+// readLimited читает не более maxSize байт, при превышении возвращает tooLargeErr
+func readLimited(reader io.Reader, maxSize int64, tooLargeErr error) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(reader, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxSize {
+		return nil, tooLargeErr
+	}
+	return data, nil
+}
+
+// batchItem - пара фото и данных камеры из пакетной загрузки
+type batchItem struct {
+	name     string
+	photo    []byte
+	photoExt string
+	payload  []byte
+	err      error
+}
+
+func (b *batchItem) setErr(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+var batchPhotoExtensions = map[string]bool{
+	".jpeg": true,
+	".jpg":  true,
+	".png":  true,
+	".svg":  true,
+}
+
+// readBatchArchive читает zip архив, в котором фото и данные камеры одного случая имеют одинаковое имя
+// и различаются расширением, например `001.jpg` и `001.bin`
+func readBatchArchive(file *multipart.FileHeader, encoding string, maxItems int) ([]batchItem, error) {
+	reader, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	archive, err := zip.NewReader(reader, file.Size)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", customErrors.BadBatchArchiveErr, err)
+	}
+
+	items := make(map[string]*batchItem)
+	var names []string
+	for _, entry := range archive.File {
+		if entry.FileInfo().IsDir() {
+			continue
+		}
+
+		ext := strings.ToLower(path.Ext(entry.Name))
+		name := strings.TrimSuffix(entry.Name, path.Ext(entry.Name))
+
+		item, ok := items[name]
+		if !ok {
+			if len(names) == maxItems {
+				return nil, customErrors.BatchTooLargeErr
+			}
+			item = &batchItem{name: name}
+			items[name] = item
+			names = append(names, name)
+		}
+
+		switch {
+		case batchPhotoExtensions[ext]:
+			item.photoExt = ext
+			item.photo, err = readZipEntry(entry, maxPhotoSize, errors.New(responses.ResponseBadFileSize))
+			if err != nil {
+				item.setErr(err)
+			}
+		case decoder.EncodingByExtension(ext) != "":
+			entryEncoding := encoding
+			if entryEncoding == "" {
+				entryEncoding = decoder.EncodingByExtension(ext)
+			}
+
+			data, err := readZipEntry(entry, payloadMaxSize(entryEncoding), customErrors.PayloadTooLargeErr)
+			if err != nil {
+				item.setErr(err)
+				continue
+			}
+			item.payload, err = decoder.DecodeEncoding(data, entryEncoding)
+			if err != nil {
+				item.setErr(fmt.Errorf(responses.ResponseBadByteStringReason, err))
+			}
+		default:
+			item.setErr(fmt.Errorf(responses.ResponseBadBatchFile, entry.Name))
+		}
+	}
+
+	if len(names) == 0 {
+		return nil, customErrors.NoPayloadErr
+	}
+
+	sort.Strings(names)
+	result := make([]batchItem, 0, len(names))
+	for _, name := range names {
+		item := items[name]
+		if item.photo == nil {
+			item.setErr(errors.New(responses.ResponseNoBatchPhoto))
+		}
+		if item.payload == nil {
+			item.setErr(errors.New(responses.ResponseNoBatchPayload))
+		}
+		result = append(result, *item)
+	}
+
+	return result, nil
+}
+
+func readZipEntry(entry *zip.File, maxSize int64, tooLargeErr error) ([]byte, error) {
+	if entry.UncompressedSize64 > uint64(maxSize) {
+		return nil, tooLargeErr
+	}
+
+	reader, err := entry.Open()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", customErrors.BadBatchArchiveErr, err)
+	}
+	defer reader.Close()
+
+	// Размер в заголовке архива может не совпадать с реальным
+	return readLimited(reader, maxSize, tooLargeErr)
+}
+
+// readBatchList читает списки файлов `photos` и данных камер `payloads` (или полей `byte_strings`),
+// фото и данные сопоставляются по порядку
+func readBatchList(c *gin.Context, encoding string, maxItems int) ([]batchItem, error) {
+	form, err := c.MultipartForm()
+	if err != nil {
+		return nil, err
+	}
+
+	photos := form.File["photos"]
+	payloads := form.File["payloads"]
+	byteStrings := form.Value["byte_strings"]
+
+	if len(photos) == 0 && len(payloads) == 0 && len(byteStrings) == 0 {
+		return nil, customErrors.NoPayloadErr
+	}
+	if len(photos) > maxItems {
+		return nil, customErrors.BatchTooLargeErr
+	}
+	if len(photos) != len(payloads)+len(byteStrings) || (len(payloads) > 0 && len(byteStrings) > 0) {
+		return nil, customErrors.BatchMismatchErr
+	}
+
+	result := make([]batchItem, len(photos))
+	for i, photo := range photos {
+		item := &result[i]
+		item.name = photo.Filename
+		item.photoExt = strings.ToLower(filepath.Ext(photo.Filename))
+
+		var payloadErr error
+		if len(payloads) > 0 {
+			item.payload, payloadErr = readPayloadFile(payloads[i], encoding)
+		} else {
+			item.payload, payloadErr = decodeByteString(byteStrings[i], encoding)
+		}
+
+		switch {
+		case !validators.ValidateFileTypeExtension(photo):
+			item.setErr(errors.New(responses.ResponseBadPhotoFile))
+		case photo.Size > maxPhotoSize:
+			item.setErr(errors.New(responses.ResponseBadFileSize))
+		default:
+			reader, err := photo.Open()
+			if err != nil {
+				return nil, err
+			}
+			item.photo, err = readLimited(reader, maxPhotoSize, errors.New(responses.ResponseBadFileSize))
+			reader.Close()
+			if err != nil {
+				item.setErr(err)
+			}
+		}
+
+		switch {
+		case payloadErr == nil:
+		case errors.Is(payloadErr, customErrors.NoPayloadErr), errors.Is(payloadErr, customErrors.PayloadTooLargeErr):
+			item.setErr(payloadErr)
+		default:
+			item.setErr(fmt.Errorf(responses.ResponseBadByteStringReason, payloadErr))
+		}
+	}
+
+	return result, nil
+}
+
+// decodeCase декодирует данные камеры в случай
+func (p publicHandler) decodeCase(payload []byte) (models.CaseBase, error) {
+	result, err := decoder.DecodePayload(payload, p.decoderLimits)
+	if err != nil {
+		return models.CaseBase{}, fmt.Errorf(responses.ResponseBadByteStringReason, err)
+	}
+
+	cameraType, err := decoder.MapToStruct(result)
+	if err != nil {
+		return models.CaseBase{}, err
+	}
+
+	return cameraType.CameraDataToCaseBase()
+}
+
+// saveCasePhoto сохраняет фото случая под уникальным именем и возвращает его путь
+func saveCasePhoto(photo []byte, ext string) (string, error) {
+	uuidBytes, err := uuid.NewV4()
+	if err != nil {
+		return "", err
+	}
+
+	filePath := fmt.Sprintf("/static/img/cases/%s", uuidBytes.String()+ext)
+	if err := os.WriteFile(".."+filePath, photo, 0o644); err != nil {
+		return "", err
+	}
+
+	return filePath, nil
+}
@@ -40,15 +40,47 @@ func InitSpecialistsHandler(
 	}
 }
 
+// GetRatingReasons @Summary Retrieve rating reasons
+// @Description Retrieves the active reason codes which can be attached to a rating.
+// @Tags specialists
+// @Accept  json
+// @Produce  json
+// @Param authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+// @Success 200 {object} []models.RatingReason "Successfully retrieved the rating reasons"
+// @Failure 403 {object} responses.MessageResponse "JWT is invalid or expired"
+// @Failure 500 {object} responses.MessageResponse "Internal server error"
+// @Router /specialist/get_rating_reasons [get]
+func (s specialistsHandler) GetRatingReasons(c *gin.Context) {
+	ctx, span := s.tracer.Start(c.Request.Context(), tracing.GetRatingReasons)
+	defer span.End()
+
+	span.AddEvent(tracing.CallToService)
+	reasons, err := s.service.GetRatingReasons(ctx)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.GetRatingReasonsType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+
+		c.JSON(http.StatusInternalServerError, responses.NewMessageResponse(responses.Response500))
+		return
+	}
+
+	span.SetStatus(codes.Ok, tracing.SuccessfulCompleting)
+
+	c.JSON(http.StatusOK, reasons)
+}
+
 // CreateRated @Summary Create a new rating
 // @Description Creates a new rating entry based on the provided data.
+// @Description The rating may contain an active reason code from /specialist/get_rating_reasons and a free-text comment.
 // @Tags specialists
 // @Accept  json
 // @Produce  json
 // @Param rated_data body models.RatedCreate true "Rated data"
 // @Param authorization header string true "Insert your access token" default(Bearer <Add access token here>)
 // @Success 201 {object} responses.CreationIntResponse "Successfully created the rating"
-// @Failure 400 {object} responses.MessageResponse "Invalid input data"
+// @Failure 400 {object} responses.MessageResponse "Invalid input data, unknown or inactive reason code"
 // @Failure 403 {object} responses.MessageResponse "JWT is invalid or expired"
 // @Failure 500 {object} responses.MessageResponse "Internal server error"
 // @Router /specialist/create_rated [post]
@@ -116,6 +148,9 @@ func (s specialistsHandler) CreateRated(c *gin.Context) {
 		case errors.Is(err, customErrors.UserBadLevel):
 			c.JSON(http.StatusBadRequest, responses.NewMessageResponse(err.Error()))
 			return
+		case errors.Is(err, customErrors.NoRowsRatingReasonErr), errors.Is(err, customErrors.InactiveRatingReasonErr):
+			c.JSON(http.StatusBadRequest, responses.NewMessageResponse(err.Error()))
+			return
 		default:
 			c.JSON(http.StatusInternalServerError, responses.NewMessageResponse(responses.Response500))
 			return
@@ -127,6 +162,78 @@ func (s specialistsHandler) CreateRated(c *gin.Context) {
 	c.JSON(http.StatusCreated, responses.CreationIntResponse{ID: createdRatedID})
 }
 
+// SkipCase @Summary Skip a case that can't be rated
+// @Description Marks a case as skipped by the specialist when it can't be rated: `bad_photo`, `plate_unreadable` or `wrong_violation`.
+// @Description A skip is not a rating: it does not count towards consensus or the specialist's rating, but the case is no longer offered to the specialist.
+// @Tags specialists
+// @Accept  json
+// @Produce  json
+// @Param skip_data body models.CaseSkipCreate true "Skip data"
+// @Param authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+// @Success 201 {object} responses.CreationIntResponse "Successfully skipped the case"
+// @Failure 400 {object} responses.MessageResponse "Invalid input data, case is solved, rated or already skipped"
+// @Failure 403 {object} responses.MessageResponse "JWT is invalid or expired"
+// @Failure 500 {object} responses.MessageResponse "Internal server error"
+// @Router /specialist/skip_case [post]
+func (s specialistsHandler) SkipCase(c *gin.Context) {
+	var skip models.CaseSkip
+
+	ctx, span := s.tracer.Start(c.Request.Context(), tracing.SkipCase)
+	defer span.End()
+
+	if err := c.ShouldBindJSON(&skip.CaseSkipCreate); err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.BindType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+
+		c.JSON(http.StatusBadRequest, responses.NewMessageResponse(fmt.Sprintf(responses.Response400, err)))
+		return
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(skip.CaseSkipCreate); err != nil {
+		customErrMsg := validators.CustomErrorMessage(err)
+
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.ValidationType, customErrMsg)),
+		)
+		span.SetStatus(codes.Error, customErrMsg)
+
+		c.JSON(http.StatusBadRequest, responses.NewMessageResponse(customErrMsg))
+		return
+	}
+
+	skip.SpecialistID = c.GetInt("userID")
+
+	span.AddEvent(tracing.CallToService)
+	createdSkipID, err := s.service.SkipCase(ctx, skip)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.SkipCaseType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+
+		switch {
+		case errors.Is(err, customErrors.UserUnverified):
+			c.JSON(http.StatusForbidden, responses.NewMessageResponse(err.Error()))
+			return
+		case errors.Is(err, customErrors.NoRowsCaseErr), errors.Is(err, customErrors.NoRowsSpecialistIDErr),
+			errors.Is(err, customErrors.CaseAlreadySolved), errors.Is(err, customErrors.UserBadLevel),
+			errors.Is(err, customErrors.UniqueRatedErr), errors.Is(err, customErrors.UniqueSkipErr):
+			c.JSON(http.StatusBadRequest, responses.NewMessageResponse(err.Error()))
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, responses.NewMessageResponse(responses.Response500))
+			return
+		}
+	}
+
+	span.SetStatus(codes.Ok, tracing.SuccessfulCompleting)
+
+	c.JSON(http.StatusCreated, responses.CreationIntResponse{ID: createdSkipID})
+}
+
 // GetCasesByLevel @Summary Retrieves cases by level
 // @Description Retrieves cases based on the provided cursor ID and the user's ID. It returns cases that match the level of difficulty or rating specified for the user.
 // @Description Returned cursor can be only int or null. It depends on existence of cases.
@@ -196,6 +303,55 @@ func (s specialistsHandler) GetCasesByLevel(c *gin.Context) {
 	c.JSON(http.StatusOK, cases)
 }
 
+// TakeNextCase @Summary Reserve the next case for rating
+// @Description Reserves the next open case of the specialist's level for CASE_RESERVATION_TTL minutes. Cases already rated by the specialist or reserved by other specialists are skipped.
+// @Description If the specialist already holds an active reservation, the reserved case is returned again. The reservation is released when the case is rated or when it expires.
+// @Tags specialists
+// @Accept  json
+// @Produce  json
+// @Param authorization header string true "Insert your access token" default(Bearer <Add access token here>)
+// @Success 200 {object} models.CaseReservation "Successfully reserved the case"
+// @Failure 400 {object} responses.MessageResponse "Invalid input data"
+// @Failure 401 {object} responses.MessageResponse "JWT is invalid or expired"
+// @Failure 403 {object} responses.MessageResponse "User is unverified"
+// @Failure 404 {object} responses.MessageResponse "There are no cases to reserve"
+// @Failure 500 {object} responses.MessageResponse "Internal server error"
+// @Router /specialist/take_next_case [post]
+func (s specialistsHandler) TakeNextCase(c *gin.Context) {
+	ctx, span := s.tracer.Start(c.Request.Context(), tracing.TakeNextCase)
+	defer span.End()
+
+	userID := c.GetInt("userID")
+
+	span.AddEvent(tracing.CallToService)
+	reservation, err := s.service.TakeNextCase(ctx, userID)
+	if err != nil {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String(tracing.TakeNextCaseType, err.Error())),
+		)
+		span.SetStatus(codes.Error, err.Error())
+
+		switch {
+		case errors.Is(err, customErrors.UserUnverified):
+			c.JSON(http.StatusForbidden, responses.NewMessageResponse(err.Error()))
+			return
+		case errors.Is(err, customErrors.NoRowsSpecialistIDErr):
+			c.JSON(http.StatusBadRequest, responses.NewMessageResponse(err.Error()))
+			return
+		case errors.Is(err, customErrors.NoCasesToReserve):
+			c.JSON(http.StatusNotFound, responses.NewMessageResponse(err.Error()))
+			return
+		default:
+			c.JSON(http.StatusInternalServerError, responses.NewMessageResponse(responses.Response500))
+			return
+		}
+	}
+
+	span.SetStatus(codes.Ok, tracing.SuccessfulCompleting)
+
+	c.JSON(http.StatusOK, reservation)
+}
+
 // GetRating @Summary Give specialists rating
 // @Description Give specialists rating
 // @Tags specialists
@@ -14,9 +14,29 @@ func InitManagersRouting(group *gin.RouterGroup, db *sqlx.DB, logger *log.Logs,
 	specialistsRepo := repository.InitSpecialistsRepo(db)
 	caseRepo := repository.InitCaseRepo(db)
 
-	managerService := services.InitManagerService(caseRepo, specialistsRepo, logger)
+	unmatchedRepo := repository.InitUnmatchedCaseRepo(db)
+	reasonRepo := repository.InitRatingReasonRepo(db)
+	notificationRepo := repository.InitNotificationRepo(db)
+	managerService := services.InitManagerService(caseRepo, specialistsRepo, unmatchedRepo, reasonRepo, notificationRepo, logger)
 	managerHandler := handlers.InitManagerHandler(managerService, tracer)
 
 	group.GET("/get_case", managerHandler.GetFulCaseByID)
 	group.GET("/get_specialists_rating", managerHandler.GetSpecialistRating)
+	group.GET("/get_unmatched_cases", managerHandler.GetUnmatchedCases)
+	group.POST("/resolve_unmatched_case", managerHandler.ResolveUnmatchedCase)
+	group.GET("/get_review_cases", managerHandler.GetReviewCases)
+	group.GET("/get_case_votes", managerHandler.GetCaseVotes)
+	group.POST("/resolve_review_case", managerHandler.ResolveReviewCase)
+	group.POST("/override_case", managerHandler.OverrideCase)
+	group.GET("/get_camera_skip_stats", managerHandler.GetCameraSkipStats)
+
+	group.POST("/create_rating_reason", managerHandler.CreateRatingReason)
+	group.GET("/get_rating_reasons", managerHandler.GetRatingReasons)
+	group.PUT("/update_rating_reason", managerHandler.UpdateRatingReason)
+
+	group.POST("/create_control_case", managerHandler.CreateControlCase)
+
+	group.GET("/get_dead_notifications", managerHandler.GetDeadNotifications)
+	group.POST("/retry_notification", managerHandler.RetryNotification)
+	group.GET("/preview_notification", managerHandler.PreviewNotification)
 }
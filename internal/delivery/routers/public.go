@@ -17,8 +17,12 @@ func InitPublicRouting(group *gin.RouterGroup, db *sqlx.DB, session database.Ses
 	specialistRepo := repository.InitSpecialistsRepo(db)
 	cameraRepo := repository.InitCameraRepo(db)
 	caseRepo := repository.InitCaseRepo(db)
+	violationRepo := repository.InitViolationRepo(db)
+	contactRepo := repository.InitContactRepo(db)
 
-	publicService := services.InitPublicService(managerRepo, specialistRepo, cameraRepo, caseRepo, logger)
+	unmatchedRepo := repository.InitUnmatchedCaseRepo(db)
+	transactor := repository.InitTransactor(db)
+	publicService := services.InitPublicService(managerRepo, specialistRepo, cameraRepo, caseRepo, violationRepo, contactRepo, unmatchedRepo, transactor, logger)
 	publicHandler := handlers.InitPublicHandler(publicService, session, JWTUtil, tracer)
 
 	group.POST("/manager_login", publicHandler.ManagerLogin)
@@ -30,6 +34,7 @@ func InitPublicRouting(group *gin.RouterGroup, db *sqlx.DB, session database.Ses
 	group.DELETE("/camera_delete", publicHandler.CameraDelete)
 
 	group.POST("/case_create", publicHandler.CaseCreate)
+	group.POST("/case_create_batch", publicHandler.CaseCreateBatch)
 
 	group.POST("/refresh", publicHandler.Refresh)
 }
@@ -14,8 +14,9 @@ import (
 func InitSpecialistsRouting(group *gin.RouterGroup, db *sqlx.DB, session database.Session, logger *log.Logs, tracer trace.Tracer) {
 	specialistRepo := repository.InitSpecialistsRepo(db)
 	caseRepo := repository.InitCaseRepo(db)
+	reasonRepo := repository.InitRatingReasonRepo(db)
 
-	specialistService := services.InitSpecialistService(specialistRepo, caseRepo, logger)
+	specialistService := services.InitSpecialistService(specialistRepo, caseRepo, reasonRepo, logger)
 	specialistHandler := handlers.InitSpecialistsHandler(specialistService, session, tracer)
 
 	group.GET("/me", specialistHandler.GetMe)
@@ -23,7 +24,10 @@ func InitSpecialistsRouting(group *gin.RouterGroup, db *sqlx.DB, session databas
 
 	group.GET("/get_rating", specialistHandler.GetRating)
 	group.GET("/get_cases_by_level", specialistHandler.GetCasesByLevel)
+	group.POST("/take_next_case", specialistHandler.TakeNextCase)
 
+	group.GET("/get_rating_reasons", specialistHandler.GetRatingReasons)
 	group.POST("/create_rated", specialistHandler.CreateRated)
+	group.POST("/skip_case", specialistHandler.SkipCase)
 	group.GET("/get_rated_solved", specialistHandler.GetRatedSolved)
 }
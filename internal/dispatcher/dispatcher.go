@@ -0,0 +1,220 @@
+package dispatcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/models"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/repository"
+	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/config"
+	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/log"
+	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/notifier"
+	customErrors "github.com/gl1n0m3c/IT_LAB_INIT/pkg/utils/customerr"
+	"github.com/spf13/viper"
+	"time"
+)
+
+// Значения по умолчанию, если в конфиге указан 0
+const (
+	defaultInterval      = 10 * time.Second
+	defaultBatchSize     = 20
+	defaultMaxAttempts   = 8
+	defaultRetryDelay    = 30 * time.Second
+	defaultMaxRetryDelay = time.Hour
+)
+
+// Время, на которое уведомление закрепляется за диспетчером на время отправки. Если диспетчер не успел
+// записать результат, по истечении этого времени уведомление будет отправлено повторно
+const sendLease = 5 * time.Minute
+
+// Dispatcher отправляет уведомления из очереди notifications. Неудачная попытка повторяется с экспоненциально
+// растущей задержкой, после MaxAttempts попыток уведомление больше не отправляется и ждет руководителя
+type Dispatcher struct {
+	notificationRepo repository.Notifications
+	caseRepo         repository.Cases
+	transactor       repository.Transactor
+	policy           notifier.Policy
+	interval         time.Duration
+	batchSize        int
+	maxAttempts      int
+	retryDelay       time.Duration
+	maxRetryDelay    time.Duration
+	dbResponseTime   time.Duration
+	logger           *log.Logs
+}
+
+// InitDispatcher создает диспетчер, policy выбирает каналы доставки каждого уведомления
+func InitDispatcher(
+	notificationRepo repository.Notifications,
+	caseRepo repository.Cases,
+	transactor repository.Transactor,
+	policy notifier.Policy,
+	logger *log.Logs,
+) Dispatcher {
+	return Dispatcher{
+		notificationRepo: notificationRepo,
+		caseRepo:         caseRepo,
+		transactor:       transactor,
+		policy:           policy,
+		interval:         durationOrDefault(config.NotifyInterval, time.Second, defaultInterval),
+		batchSize:        intOrDefault(config.NotifyBatchSize, defaultBatchSize),
+		maxAttempts:      intOrDefault(config.NotifyMaxAttempts, defaultMaxAttempts),
+		retryDelay:       durationOrDefault(config.NotifyRetryDelay, time.Second, defaultRetryDelay),
+		maxRetryDelay:    durationOrDefault(config.NotifyMaxRetryDelay, time.Second, defaultMaxRetryDelay),
+		dbResponseTime:   time.Duration(viper.GetInt(config.DBResponseTime)) * time.Second,
+		logger:           logger,
+	}
+}
+
+func intOrDefault(key string, def int) int {
+	value := viper.GetInt(key)
+	if value <= 0 {
+		return def
+	}
+	return value
+}
+
+func durationOrDefault(key string, unit, def time.Duration) time.Duration {
+	value := time.Duration(viper.GetInt(key)) * unit
+	if value <= 0 {
+		return def
+	}
+	return value
+}
+
+// Start разбирает очередь каждые interval, пока не отменен ctx. После отмены ctx Start дожидается
+// записи результата уже отправленного уведомления и возвращает в очередь остальные закрепленные
+func (d Dispatcher) Start(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// Пачки забираются, пока в очереди остаются уведомления, срок отправки которых наступил
+			for {
+				dispatched, err := d.Dispatch(ctx)
+				if err != nil {
+					d.logger.ErrorLogger.Error().Msg(fmt.Sprintf("Ошибка при разборе очереди уведомлений: %v", err))
+					break
+				}
+				if dispatched < d.batchSize || ctx.Err() != nil {
+					break
+				}
+			}
+		}
+	}
+}
+
+// Dispatch отправляет одну пачку уведомлений и возвращает их количество. Ошибка отправки отдельного
+// уведомления не прерывает разбор пачки, а записывается в уведомление. Если ctx отменен во время разбора,
+// неотправленные уведомления пачки сразу возвращаются в очередь, не дожидаясь окончания закрепления
+func (d Dispatcher) Dispatch(ctx context.Context) (int, error) {
+	claimCtx, claimCansel := context.WithTimeout(ctx, d.dbResponseTime)
+	defer claimCansel()
+
+	notifications, err := d.notificationRepo.ClaimDue(claimCtx, d.batchSize, sendLease)
+	if err != nil {
+		return 0, err
+	}
+
+	// Результат отправки записывается и после отмены ctx, иначе отправленное уведомление будет отправлено повторно
+	markParentCtx := context.WithoutCancel(ctx)
+
+	for i, notification := range notifications {
+		if ctx.Err() != nil {
+			d.release(markParentCtx, notifications[i:])
+			return i, nil
+		}
+
+		deliveries, sendErr := d.send(ctx, notification)
+
+		// Доставки и итог попытки записываются вместе, чтобы журнал доставок не расходился со статусом уведомления
+		markCtx, markCansel := context.WithTimeout(markParentCtx, d.dbResponseTime)
+		err = d.transactor.WithTx(markCtx, func(ctx context.Context) error {
+			if err := d.notificationRepo.AddDeliveries(ctx, deliveries); err != nil {
+				return err
+			}
+			if sendErr == nil {
+				return d.notificationRepo.MarkSent(ctx, notification)
+			}
+			return d.fail(ctx, notification, sendErr)
+		})
+		markCansel()
+
+		if err != nil {
+			d.logger.ErrorLogger.Error().Msg(fmt.Sprintf("Не удалось сохранить результат отправки уведомления %d: %v", notification.ID, err))
+		}
+	}
+
+	return len(notifications), nil
+}
+
+// release возвращает закрепленные, но не отправленные уведомления в очередь
+func (d Dispatcher) release(ctx context.Context, notifications []models.Notification) {
+	ids := make([]int, 0, len(notifications))
+	for _, notification := range notifications {
+		ids = append(ids, notification.ID)
+	}
+
+	ctx, cansel := context.WithTimeout(ctx, d.dbResponseTime)
+	defer cansel()
+
+	if err := d.notificationRepo.Release(ctx, ids); err != nil {
+		d.logger.ErrorLogger.Error().Msg(fmt.Sprintf("Не удалось вернуть в очередь уведомления %v: %v", ids, err))
+	}
+}
+
+// send составляет сообщение по данным штрафа и отправляет его по каналам политики
+func (d Dispatcher) send(ctx context.Context, notification models.Notification) ([]models.NotificationDelivery, error) {
+	fineCtx, fineCansel := context.WithTimeout(ctx, d.dbResponseTime)
+	defer fineCansel()
+
+	fineData, err := d.caseRepo.GetFineData(fineCtx, notification.CaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	message, err := notifier.Compose(notification.Kind, fineData)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries, err := d.policy.Deliver(ctx, message)
+	for i := range deliveries {
+		deliveries[i].NotificationID = notification.ID
+	}
+
+	return deliveries, err
+}
+
+// fail назначает следующую попытку, а после последней переводит уведомление в неотправленные.
+// Уведомление без подходящих контактов или неизвестного вида сразу переводится в неотправленные,
+// так как повторная попытка его не отправит
+func (d Dispatcher) fail(ctx context.Context, notification models.Notification, sendErr error) error {
+	dead := notification.Attempts >= d.maxAttempts ||
+		errors.Is(sendErr, notifier.ErrNoContacts) || errors.Is(sendErr, customErrors.UnknownNotificationKindErr)
+	nextAttemptAt := time.Now().Add(Backoff(d.retryDelay, d.maxRetryDelay, notification.Attempts))
+
+	if dead {
+		d.logger.ErrorLogger.Error().Msg(fmt.Sprintf("Уведомление %d не отправлено за %d попыток: %v",
+			notification.ID, notification.Attempts, sendErr))
+	} else {
+		d.logger.InfoLogger.Info().Msg(fmt.Sprintf("Попытка %d отправки уведомления %d не удалась: %v",
+			notification.Attempts, notification.ID, sendErr))
+	}
+
+	return d.notificationRepo.MarkFailed(ctx, notification.ID, sendErr.Error(), nextAttemptAt, dead)
+}
+
+// Backoff возвращает задержку перед следующей попыткой после attempts неудачных:
+// retryDelay, затем вдвое больше после каждой попытки, но не больше maxRetryDelay
+func Backoff(retryDelay, maxRetryDelay time.Duration, attempts int) time.Duration {
+	delay := retryDelay
+	for i := 1; i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryDelay)
+}
@@ -0,0 +1,49 @@
+package tests
+
+import (
+	"errors"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/models"
+	"time"
+)
+
+const (
+	retryDelay    = 30 * time.Second
+	maxRetryDelay = time.Hour
+)
+
+// Задержка перед следующей попыткой после заданного числа неудачных
+var backoffByAttempts = map[int]time.Duration{
+	0:  retryDelay,
+	1:  retryDelay,
+	2:  2 * retryDelay,
+	3:  4 * retryDelay,
+	7:  64 * retryDelay,
+	8:  maxRetryDelay,
+	50: maxRetryDelay,
+}
+
+// Число попыток отправки, после которого уведомление переводится в неотправленные
+const maxAttempts = 3
+
+// Данные штрафа владельца, у которого есть почта и телефон
+var fineData = models.FineData{
+	Violation: models.Violation{Type: "Speeding", Amount: 500},
+	Contacts: map[string]string{
+		models.ContactEmail: "owner@example.com",
+		models.ContactPhone: "79031234567",
+	},
+	Coordinated:    "55.7558, 37.6173",
+	ViolationValue: "90 км/ч",
+	Date:           time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC),
+}
+
+// Уведомление о штрафе, которое отправляется впервые
+var fineNotification = models.Notification{
+	ID:       1,
+	CaseID:   1,
+	Kind:     models.NotificationFine,
+	Status:   models.NotificationPending,
+	Attempts: 1,
+}
+
+var errSend = errors.New("сервер недоступен")
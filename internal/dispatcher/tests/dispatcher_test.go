@@ -0,0 +1,235 @@
+package tests
+
+import (
+	"context"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/dispatcher"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/models"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/repository"
+	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/config"
+	logger "github.com/gl1n0m3c/IT_LAB_INIT/pkg/log"
+	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/notifier"
+	notifierTests "github.com/gl1n0m3c/IT_LAB_INIT/pkg/notifier/tests"
+	"github.com/guregu/null"
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+// failedAttempt - неудачная попытка, которую диспетчер записал в очередь
+type failedAttempt struct {
+	NotificationID int
+	Err            string
+	Dead           bool
+}
+
+// fakeNotifications - очередь уведомлений в памяти. Методы, которые диспетчер не вызывает, не реализованы
+type fakeNotifications struct {
+	repository.Notifications
+	due        []models.Notification
+	deliveries []models.NotificationDelivery
+	sent       []int
+	failed     []failedAttempt
+	released   []int
+}
+
+func (f *fakeNotifications) ClaimDue(_ context.Context, limit int, _ time.Duration) ([]models.Notification, error) {
+	claimed := f.due
+	if len(claimed) > limit {
+		claimed = claimed[:limit]
+	}
+	f.due = f.due[len(claimed):]
+	return claimed, nil
+}
+
+func (f *fakeNotifications) Release(_ context.Context, notificationIDs []int) error {
+	f.released = append(f.released, notificationIDs...)
+	return nil
+}
+
+func (f *fakeNotifications) AddDeliveries(ctx context.Context, deliveries []models.NotificationDelivery) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	f.deliveries = append(f.deliveries, deliveries...)
+	return nil
+}
+
+func (f *fakeNotifications) MarkSent(ctx context.Context, notification models.Notification) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	f.sent = append(f.sent, notification.ID)
+	return nil
+}
+
+func (f *fakeNotifications) MarkFailed(ctx context.Context, notificationID int, sendErr string, _ time.Time, dead bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	f.failed = append(f.failed, failedAttempt{NotificationID: notificationID, Err: sendErr, Dead: dead})
+	return nil
+}
+
+// fakeCases возвращает одни и те же данные штрафа для любого случая
+type fakeCases struct {
+	repository.Cases
+	fineData models.FineData
+}
+
+func (f fakeCases) GetFineData(_ context.Context, _ int) (models.FineData, error) {
+	return f.fineData, nil
+}
+
+// fakeTransactor выполняет fn без транзакции
+type fakeTransactor struct{}
+
+func (fakeTransactor) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// initDispatcher создает диспетчер над очередью notifications с данными штрафа fine и каналами notifiers
+func initDispatcher(t *testing.T, notifications *fakeNotifications, fine models.FineData, notifiers ...notifier.Notifier) dispatcher.Dispatcher {
+	viper.Set(config.NotifyMaxAttempts, maxAttempts)
+	viper.Set(config.DBResponseTime, 1)
+	t.Cleanup(func() {
+		viper.Set(config.NotifyMaxAttempts, 0)
+		viper.Set(config.DBResponseTime, 0)
+	})
+
+	nop := zerolog.Nop()
+
+	return dispatcher.InitDispatcher(notifications, fakeCases{fineData: fine}, fakeTransactor{},
+		notifier.InitPolicy(notifiers...), &logger.Logs{InfoLogger: &nop, ErrorLogger: &nop})
+}
+
+// dispatchOne отправляет одно уведомление диспетчером с данными штрафа fine и возвращает очередь после отправки
+func dispatchOne(t *testing.T, notification models.Notification, fine models.FineData, notifiers ...notifier.Notifier) *fakeNotifications {
+	notifications := &fakeNotifications{due: []models.Notification{notification}}
+
+	dispatched, err := initDispatcher(t, notifications, fine, notifiers...).Dispatch(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 1, dispatched)
+
+	return notifications
+}
+
+func TestBackoff(t *testing.T) {
+	for attempts, expected := range backoffByAttempts {
+		assert.Equal(t, expected, dispatcher.Backoff(retryDelay, maxRetryDelay, attempts), "попыток: %d", attempts)
+	}
+}
+
+func TestDispatchSent(t *testing.T) {
+	notifications := dispatchOne(t, fineNotification, fineData,
+		notifierTests.FakeNotifier{Name: models.ChannelEmail, Key: models.ContactEmail})
+
+	assert.Equal(t, []int{fineNotification.ID}, notifications.sent)
+	assert.Empty(t, notifications.failed)
+	if assert.Len(t, notifications.deliveries, 1) {
+		assert.Equal(t, fineNotification.ID, notifications.deliveries[0].NotificationID)
+		assert.Equal(t, fineData.Contacts[models.ContactEmail], notifications.deliveries[0].Recipient)
+		assert.True(t, notifications.deliveries[0].Delivered)
+	}
+}
+
+func TestDispatchDeadAfterMaxAttempts(t *testing.T) {
+	for attempts := 1; attempts <= maxAttempts+1; attempts++ {
+		notification := fineNotification
+		notification.Attempts = attempts
+
+		notifications := dispatchOne(t, notification, fineData,
+			notifierTests.FakeNotifier{Name: models.ChannelEmail, Key: models.ContactEmail, Err: errSend})
+
+		assert.Empty(t, notifications.sent)
+		if assert.Len(t, notifications.failed, 1, "попыток: %d", attempts) {
+			assert.Equal(t, notification.ID, notifications.failed[0].NotificationID)
+			assert.Contains(t, notifications.failed[0].Err, errSend.Error())
+			assert.Equal(t, attempts >= maxAttempts, notifications.failed[0].Dead, "попыток: %d", attempts)
+		}
+	}
+}
+
+func TestDispatchDeadImmediately(t *testing.T) {
+	withoutContacts := fineData
+	withoutContacts.Contacts = map[string]string{}
+
+	unknownKind := fineNotification
+	unknownKind.Kind = "unknown"
+
+	tests := map[string]struct {
+		notification models.Notification
+		fine         models.FineData
+	}{
+		"no contacts":  {notification: fineNotification, fine: withoutContacts},
+		"unknown kind": {notification: unknownKind, fine: fineData},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			notifications := dispatchOne(t, tt.notification, tt.fine,
+				notifierTests.FakeNotifier{Name: models.ChannelEmail, Key: models.ContactEmail})
+
+			assert.Empty(t, notifications.sent)
+			assert.Empty(t, notifications.deliveries)
+			if assert.Len(t, notifications.failed, 1) {
+				assert.True(t, notifications.failed[0].Dead)
+			}
+		})
+	}
+}
+
+func TestDispatchRecordsFailedDeliveries(t *testing.T) {
+	viper.Set(config.NotifyChannels, models.ChannelEmail+","+models.ChannelSMS)
+	defer viper.Set(config.NotifyChannels, "")
+
+	notifications := dispatchOne(t, fineNotification, fineData,
+		notifierTests.FakeNotifier{Name: models.ChannelEmail, Key: models.ContactEmail, Err: errSend},
+		notifierTests.FakeNotifier{Name: models.ChannelSMS, Key: models.ContactPhone, Err: errSend})
+
+	assert.Empty(t, notifications.sent)
+	if assert.Len(t, notifications.failed, 1) {
+		assert.False(t, notifications.failed[0].Dead)
+	}
+
+	// Записываются доставки по всем каналам, которые пробовал диспетчер, в порядке перебора
+	if assert.Len(t, notifications.deliveries, 2) {
+		for i, channel := range []string{models.ChannelEmail, models.ChannelSMS} {
+			delivery := notifications.deliveries[i]
+			assert.Equal(t, fineNotification.ID, delivery.NotificationID)
+			assert.Equal(t, channel, delivery.Channel)
+			assert.False(t, delivery.Delivered)
+			assert.Equal(t, null.StringFrom(errSend.Error()), delivery.Error)
+		}
+	}
+}
+
+func TestDispatchReleasesOnCancel(t *testing.T) {
+	second := fineNotification
+	second.ID = fineNotification.ID + 1
+	third := fineNotification
+	third.ID = fineNotification.ID + 2
+	notifications := &fakeNotifications{due: []models.Notification{fineNotification, second, third}}
+
+	// Остановка приходит во время отправки первого уведомления
+	ctx, cansel := context.WithCancel(context.Background())
+	defer cansel()
+
+	d := initDispatcher(t, notifications, fineData,
+		notifierTests.FakeNotifier{Name: models.ChannelEmail, Key: models.ContactEmail, OnNotify: cansel})
+
+	dispatched, err := d.Dispatch(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 1, dispatched)
+
+	// Результат начатой отправки записан, остальные уведомления возвращены в очередь
+	assert.Equal(t, []int{fineNotification.ID}, notifications.sent)
+	assert.Len(t, notifications.deliveries, 1)
+	assert.Empty(t, notifications.failed)
+	assert.Equal(t, []int{second.ID, third.ID}, notifications.released)
+}
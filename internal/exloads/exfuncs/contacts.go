@@ -6,6 +6,7 @@ import (
 	"github.com/gl1n0m3c/IT_LAB_INIT/internal/repository"
 	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/config"
 	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/database"
+	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/plates"
 	"github.com/xuri/excelize/v2"
 )
 
@@ -51,7 +52,14 @@ func LoadContacts() error {
 			userContacts[keys[j-1]] = key
 		}
 
-		contact.Transport = row[0]
+		// Номер приводится к тому же виду, что и номера из данных камер
+		transport, err := plates.Normalize(row[0])
+		if err != nil {
+			fmt.Printf("Строка %d пропущена, некорректный номер транспорта: %v\n", i+1, err)
+			continue
+		}
+
+		contact.Transport = transport
 		contact.UserContacts = userContacts
 
 		contacts = append(contacts, contact)
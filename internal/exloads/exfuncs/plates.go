@@ -0,0 +1,58 @@
+package exfuncs
+
+import (
+	"context"
+	"fmt"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/repository"
+	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/config"
+	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/database"
+	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/plates"
+)
+
+// NormalizePlates приводит уже сохраненные номера транспорта к тому же виду, что и новые номера.
+// Каждый номер переписывается отдельной транзакцией, поэтому после ошибки загрузку можно запустить повторно.
+// Номера, которые не проходят проверку, не изменяются и выводятся в отчете
+func NormalizePlates() error {
+	var renamed int
+	var invalid []string
+
+	config.InitConfig()
+
+	db := database.GetDB()
+	contactRepo := repository.InitContactRepo(db)
+
+	ctx := context.Background()
+
+	transports, err := contactRepo.GetTransports(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, transport := range transports {
+		normalized, err := plates.Normalize(transport)
+		if err != nil {
+			invalid = append(invalid, fmt.Sprintf("%q: %v", transport, err))
+			continue
+		}
+
+		if normalized == transport {
+			continue
+		}
+
+		if err = contactRepo.RenameTransport(ctx, transport, normalized); err != nil {
+			return fmt.Errorf("номер %q: %w", transport, err)
+		}
+		renamed++
+	}
+
+	fmt.Printf("Приведено к единому виду %d номеров.\n", renamed)
+
+	if len(invalid) != 0 {
+		fmt.Printf("Не прошли проверку и оставлены без изменений %d номеров:\n", len(invalid))
+		for _, line := range invalid {
+			fmt.Println(line)
+		}
+	}
+
+	return nil
+}
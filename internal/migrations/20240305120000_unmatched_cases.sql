@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS unmatched_cases (
+    id SERIAL PRIMARY KEY,
+    camera_id VARCHAR NOT NULL,
+    transport VARCHAR(20) NOT NULL,
+    violation_id VARCHAR NOT NULL,
+    violation_value VARCHAR NOT NULL,
+    level INTEGER NOT NULL,
+    datetime TIMESTAMP WITH TIME ZONE NOT NULL,
+    photo_url VARCHAR NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT (NOW()) NOT NULL
+);
+
+ALTER TABLE unmatched_cases
+    ADD CONSTRAINT fk_unmatched_camera
+        FOREIGN KEY (camera_id) REFERENCES cameras(id) ON DELETE CASCADE,
+    ADD CONSTRAINT fk_unmatched_violation
+        FOREIGN KEY (violation_id) REFERENCES violations(id) ON DELETE CASCADE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS unmatched_cases;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE consensus_policy_type AS ENUM ('unanimous', 'majority', 'supermajority', 'weighted');
+
+-- consensus_k - количество оценок на уровне, после которого принимается решение, NULL - значение K из конфига
+ALTER TABLE violations
+    ADD COLUMN consensus_policy consensus_policy_type DEFAULT ('unanimous') NOT NULL,
+    ADD COLUMN consensus_k INTEGER CHECK (consensus_k > 0);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE violations
+    DROP COLUMN IF EXISTS consensus_policy,
+    DROP COLUMN IF EXISTS consensus_k;
+DROP TYPE IF EXISTS consensus_policy_type;
+-- +goose StatementEnd
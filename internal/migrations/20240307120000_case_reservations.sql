@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Случай закрепляется за одним специалистом до expires_at, истекшие записи перезаписываются следующим резервированием
+CREATE TABLE IF NOT EXISTS case_reservations (
+    case_id INTEGER PRIMARY KEY,
+    specialist_id INTEGER NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_case_reservations_specialist ON case_reservations (specialist_id);
+
+ALTER TABLE case_reservations
+    ADD CONSTRAINT fk_reservation_case
+        FOREIGN KEY (case_id) REFERENCES cases(id) ON DELETE CASCADE,
+    ADD CONSTRAINT fk_reservation_specialist
+        FOREIGN KEY (specialist_id) REFERENCES specialists(id) ON DELETE CASCADE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS case_reservations;
+-- +goose StatementEnd
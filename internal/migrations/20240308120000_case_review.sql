@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Случай, по которому специалисты максимального уровня не пришли к консенсусу, ожидает решения руководителя
+ALTER TABLE cases
+    ADD COLUMN needs_review BOOLEAN DEFAULT (FALSE) NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_cases_needs_review ON cases (id) WHERE needs_review AND NOT is_solved;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_cases_needs_review;
+
+ALTER TABLE cases
+    DROP COLUMN IF EXISTS needs_review;
+-- +goose StatementEnd
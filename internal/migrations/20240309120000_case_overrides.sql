@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE override_action_type AS ENUM ('overturn', 'reopen');
+
+-- Решения руководителей, пересматривающих закрытые случаи. previous_choice - решение до пересмотра,
+-- choice - новое решение, NULL при возврате случая на оценку
+CREATE TABLE IF NOT EXISTS case_overrides (
+    id SERIAL PRIMARY KEY,
+    case_id INTEGER NOT NULL,
+    manager_id INTEGER,
+    action override_action_type NOT NULL,
+    previous_choice BOOLEAN NOT NULL,
+    choice BOOLEAN,
+    justification VARCHAR NOT NULL CHECK (justification <> ''),
+    datetime TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_case_overrides_case ON case_overrides (case_id);
+
+ALTER TABLE case_overrides
+    ADD CONSTRAINT fk_override_case
+        FOREIGN KEY (case_id) REFERENCES cases(id) ON DELETE CASCADE,
+    ADD CONSTRAINT fk_override_manager
+        FOREIGN KEY (manager_id) REFERENCES managers(id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS case_overrides;
+DROP TYPE IF EXISTS override_action_type;
+-- +goose StatementEnd
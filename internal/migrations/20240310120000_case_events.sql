@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE case_event_type AS ENUM ('created', 'rated', 'escalated', 'review', 'solved', 'notified',
+                                     'overturned', 'reopened', 'deleted');
+
+-- История случая. Внешнего ключа на cases нет, чтобы история сохранялась после удаления случая.
+-- level - текущий уровень случая в момент события
+CREATE TABLE IF NOT EXISTS case_events (
+    id SERIAL PRIMARY KEY,
+    case_id INTEGER NOT NULL,
+    type case_event_type NOT NULL,
+    level INTEGER,
+    specialist_id INTEGER,
+    manager_id INTEGER,
+    choice BOOLEAN,
+    message VARCHAR,
+    datetime TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_case_events_case ON case_events (case_id, id);
+
+ALTER TABLE case_events
+    ADD CONSTRAINT fk_event_specialist
+        FOREIGN KEY (specialist_id) REFERENCES specialists(id) ON DELETE SET NULL,
+    ADD CONSTRAINT fk_event_manager
+        FOREIGN KEY (manager_id) REFERENCES managers(id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS case_events;
+DROP TYPE IF EXISTS case_event_type;
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE skip_reason_type AS ENUM ('bad_photo', 'plate_unreadable', 'wrong_violation');
+
+-- Пропуски случаев специалистами, которые не могут их оценить. Пропуск не является оценкой:
+-- не учитывается в консенсусе и рейтинге, но случай больше не выдается специалисту
+CREATE TABLE IF NOT EXISTS case_skips (
+    id SERIAL PRIMARY KEY,
+    case_id INTEGER NOT NULL,
+    specialist_id INTEGER NOT NULL,
+    reason skip_reason_type NOT NULL,
+    datetime TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+    UNIQUE (case_id, specialist_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_case_skips_specialist ON case_skips (specialist_id);
+
+ALTER TABLE case_skips
+    ADD CONSTRAINT fk_skip_case
+        FOREIGN KEY (case_id) REFERENCES cases(id) ON DELETE CASCADE,
+    ADD CONSTRAINT fk_skip_specialist
+        FOREIGN KEY (specialist_id) REFERENCES specialists(id) ON DELETE CASCADE;
+
+ALTER TYPE case_event_type ADD VALUE IF NOT EXISTS 'skipped' AFTER 'rated';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- Значение skipped остается в case_event_type: Postgres не удаляет значения перечислений
+DELETE FROM case_events WHERE type = 'skipped';
+DROP TABLE IF EXISTS case_skips;
+DROP TYPE IF EXISTS skip_reason_type;
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Справочник причин оценки ведут руководители. Отключенную причину нельзя указать в новой оценке,
+-- но она остается в уже сохраненных
+CREATE TABLE IF NOT EXISTS rating_reasons (
+    code VARCHAR(64) PRIMARY KEY,
+    description VARCHAR NOT NULL,
+    is_active BOOLEAN DEFAULT (TRUE) NOT NULL
+);
+
+ALTER TABLE rated_cases
+    ADD COLUMN reason_code VARCHAR(64),
+    ADD COLUMN comment VARCHAR,
+    ADD CONSTRAINT fk_rated_reason
+        FOREIGN KEY (reason_code) REFERENCES rating_reasons(code);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE rated_cases
+    DROP COLUMN IF EXISTS reason_code,
+    DROP COLUMN IF EXISTS comment;
+DROP TABLE IF EXISTS rating_reasons;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Ответ контрольного случая, у обычных случаев NULL
+ALTER TABLE cases ADD COLUMN control_choice BOOLEAN;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE cases DROP COLUMN IF EXISTS control_choice;
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+-- cancelled - уведомление перестало быть нужным после пересмотра решения по случаю до отправки
+CREATE TYPE notification_status AS ENUM ('pending', 'sent', 'dead', 'cancelled');
+
+-- Исходящие уведомления нарушителям. Запись создается в транзакции решения по случаю,
+-- а отправляет ее фоновый диспетчер с повторными попытками. idempotency_key не дает
+-- поставить одно уведомление по одному решению дважды
+CREATE TABLE IF NOT EXISTS notifications (
+    id SERIAL PRIMARY KEY,
+    case_id INTEGER NOT NULL,
+    kind VARCHAR(32) NOT NULL,
+    idempotency_key VARCHAR NOT NULL UNIQUE,
+    status notification_status DEFAULT ('pending') NOT NULL,
+    attempts INTEGER DEFAULT (0) NOT NULL,
+    next_attempt_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+    last_error VARCHAR,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+    sent_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_due ON notifications (next_attempt_at) WHERE status = 'pending';
+
+ALTER TABLE notifications
+    ADD CONSTRAINT fk_notification_case
+        FOREIGN KEY (case_id) REFERENCES cases(id) ON DELETE CASCADE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS notifications;
+DROP TYPE IF EXISTS notification_status;
+-- +goose StatementEnd
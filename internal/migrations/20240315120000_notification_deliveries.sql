@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Результаты доставки уведомлений по каналам: каждая попытка отправить уведомление
+-- на почту, по SMS или через вебхук записывается отдельной строкой
+CREATE TABLE IF NOT EXISTS notification_deliveries (
+    id SERIAL PRIMARY KEY,
+    notification_id INTEGER NOT NULL,
+    channel VARCHAR(16) NOT NULL,
+    recipient VARCHAR NOT NULL,
+    delivered BOOLEAN NOT NULL,
+    error VARCHAR,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_deliveries_notification ON notification_deliveries (notification_id);
+
+ALTER TABLE notification_deliveries
+    ADD CONSTRAINT fk_delivery_notification
+        FOREIGN KEY (notification_id) REFERENCES notifications(id) ON DELETE CASCADE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS notification_deliveries;
+-- +goose StatementEnd
@@ -10,3 +10,14 @@ type Camera struct {
 	ID int `json:"id" db:"id"`
 	CameraBase
 }
+
+// CameraSkipStats - как часто специалисты пропускают случаи камеры: SkippedCases - случаи, пропущенные хотя бы
+// одним специалистом, Skips - все пропуски, SkipRate - доля пропущенных случаев, Reasons - пропуски по причинам
+type CameraSkipStats struct {
+	CameraID     string         `json:"camera_id" db:"camera_id"`
+	Cases        int            `json:"cases" db:"cases"`
+	SkippedCases int            `json:"skipped_cases" db:"skipped_cases"`
+	Skips        int            `json:"skips" db:"skips"`
+	SkipRate     float64        `json:"skip_rate" db:"skip_rate"`
+	Reasons      map[string]int `json:"reasons" db:"-"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"github.com/guregu/null"
+	"time"
+)
+
+// Типы событий в истории случая
+const (
+	CaseEventCreated    = "created"
+	CaseEventRated      = "rated"
+	CaseEventSkipped    = "skipped"
+	CaseEventEscalated  = "escalated"
+	CaseEventReview     = "review"
+	CaseEventSolved     = "solved"
+	CaseEventNotified   = "notified"
+	CaseEventOverturned = "overturned"
+	CaseEventReopened   = "reopened"
+	CaseEventDeleted    = "deleted"
+)
+
+// Уведомления нарушителю, записываемые в поле message события notified
+const (
+	NotificationFine       = "fine"
+	NotificationFineCancel = "fine_cancel"
+)
+
+// CaseEventCreate - событие случая. SpecialistID или ManagerID задают автора события, Choice - оценку или решение,
+// Message - обоснование руководителя, вид уведомления или причину пропуска
+type CaseEventCreate struct {
+	CaseID       int         `json:"case_id" db:"case_id"`
+	Type         string      `json:"type" db:"type"`
+	SpecialistID null.Int    `json:"specialist_id" db:"specialist_id"`
+	ManagerID    null.Int    `json:"manager_id" db:"manager_id"`
+	Choice       null.Bool   `json:"choice" db:"choice"`
+	Message      null.String `json:"message" db:"message"`
+}
+
+// CaseEvent - запись истории случая, Level - уровень случая в момент события
+type CaseEvent struct {
+	CaseEventCreate
+	ID       int       `json:"id" db:"id"`
+	Level    null.Int  `json:"level" db:"level"`
+	Datetime time.Time `json:"datetime" db:"datetime"`
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	customErrors "github.com/gl1n0m3c/IT_LAB_INIT/pkg/utils/customerr"
 	"github.com/guregu/null"
 	"time"
 )
@@ -21,6 +22,20 @@ type Case struct {
 	ID int `json:"id" db:"id"`
 }
 
+// ControlCaseCreate - контрольный случай с заранее известным решением Choice. Специалистам он выдается
+// как обычный случай, но оценки по нему не участвуют в консенсусе и учитываются только в точности специалиста.
+// После K оценок на своем уровне контрольный случай закрывается без штрафа
+type ControlCaseCreate struct {
+	CameraID       string    `json:"camera_id" validate:"required"`
+	Transport      string    `json:"transport" validate:"required"`
+	ViolationID    string    `json:"violation_id" validate:"required"`
+	ViolationValue string    `json:"violation_value" validate:"required"`
+	Level          int       `json:"level" validate:"required"`
+	Datetime       time.Time `json:"datetime" validate:"required"`
+	PhotoUrl       string    `json:"photo_url" validate:"required"`
+	Choice         bool      `json:"choice"`
+}
+
 type CaseUpdate struct {
 	Case
 }
@@ -30,14 +45,24 @@ type CaseViolations struct {
 	Violation
 }
 
+// CaseReservation - случай, закрепленный за специалистом до ExpiresAt
+type CaseReservation struct {
+	CaseViolations
+	ExpiresAt time.Time `json:"expires_at" db:"expires_at"`
+}
+
 type CaseCursor struct {
 	Cases  []CaseViolations `json:"cases"`
 	Cursor null.Int         `json:"cursor"`
 }
 
+// RatedCreate - оценка случая. ReasonCode - код причины из справочника, Comment - пояснение специалиста,
+// оба поля необязательны
 type RatedCreate struct {
-	CaseID int  `json:"case_id" db:"case_id" validate:"required"`
-	Choice bool `json:"choice" db:"choice"`
+	CaseID     int         `json:"case_id" db:"case_id" validate:"required"`
+	Choice     bool        `json:"choice" db:"choice"`
+	ReasonCode null.String `json:"reason_code" db:"reason_code"`
+	Comment    null.String `json:"comment" db:"comment"`
 }
 
 type RatedUpdate struct {
@@ -55,11 +80,12 @@ type RatedBase struct {
 type Rated struct {
 	RatedBase
 	Violation
-	Level          int    `json:"level"`
-	PhotoUrl       string `json:"photo_url"`
-	CameraID       string `json:"camera_id"`
-	ViolationValue string `json:"violation_value"`
-	ID             int    `json:"id"`
+	Level          int         `json:"level"`
+	PhotoUrl       string      `json:"photo_url"`
+	CameraID       string      `json:"camera_id"`
+	ViolationValue string      `json:"violation_value"`
+	Reason         null.String `json:"reason"`
+	ID             int         `json:"id"`
 }
 
 type RatedCursor struct {
@@ -67,16 +93,159 @@ type RatedCursor struct {
 	Cursor null.Int `json:"cursor"`
 }
 
+// RatedCover - оценка случая для руководителя, Reason - описание причины с кодом ReasonCode
 type RatedCover struct {
-	ID     int       `json:"id"`
-	Status string    `json:"status"`
-	Date   time.Time `json:"date"`
+	ID         int         `json:"id"`
+	Choice     bool        `json:"choice"`
+	Status     string      `json:"status"`
+	Date       time.Time   `json:"date"`
+	ReasonCode null.String `json:"reason_code"`
+	Reason     null.String `json:"reason"`
+	Comment    null.String `json:"comment"`
 	SpecialistCover
 }
 
+// CaseBatchItem - результат создания одного случая из пакета, ID заполняется только при успехе,
+// UnmatchedID - если случай отложен из-за отсутствия контактов владельца транспорта
+type CaseBatchItem struct {
+	Index       int                             `json:"index"`
+	Name        string                          `json:"name"`
+	ID          int                             `json:"id,omitempty"`
+	UnmatchedID int                             `json:"unmatched_id,omitempty"`
+	Error       string                          `json:"error,omitempty"`
+	Missing     []customErrors.MissingReference `json:"missing,omitempty"`
+}
+
+type CaseBatchResult struct {
+	Created   int             `json:"created"`
+	Unmatched int             `json:"unmatched"`
+	Failed    int             `json:"failed"`
+	Items     []CaseBatchItem `json:"items"`
+}
+
 type CaseFul struct {
 	Violation
 	CaseBase
-	IsSolved    bool          `json:"is_solved"`
-	RatedCovers *[]RatedCover `json:"rated_covers"`
+	IsSolved    bool           `json:"is_solved"`
+	Control     null.Bool      `json:"control_choice"`
+	RatedCovers *[]RatedCover  `json:"rated_covers"`
+	Overrides   []CaseOverride `json:"overrides"`
+	Timeline    []CaseEvent    `json:"timeline"`
+}
+
+// CaseCreated - результат создания случая. Если контакты владельца транспорта не найдены,
+// случай откладывается до их появления: Unmatched = true, а ID - id отложенного случая
+type CaseCreated struct {
+	ID        int
+	Unmatched bool
+}
+
+// UnmatchedCase - отложенный случай, для транспорта которого нет контактов владельца
+type UnmatchedCase struct {
+	CaseBase
+	ID        int       `json:"id" db:"id"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+type UnmatchedCaseCursor struct {
+	Cases  []UnmatchedCase `json:"cases"`
+	Cursor null.Int        `json:"cursor"`
+}
+
+// UnmatchedCaseResolve - привязка отложенного случая к контактам. Transport заменяет распознанный номер,
+// если камера ошиблась; при переданных Contacts для номера создается новая запись контактов
+type UnmatchedCaseResolve struct {
+	UnmatchedID int               `json:"unmatched_id" validate:"required"`
+	Transport   string            `json:"transport"`
+	Contacts    map[string]string `json:"contacts"`
+}
+
+// CaseVote - оценка случая и уровень оценившего специалиста
+type CaseVote struct {
+	Choice bool `db:"choice"`
+	Level  int  `db:"level"`
+}
+
+// CaseConsensus - данные случая, по которым политика консенсуса принимает решение.
+// K не задано, если для типа нарушения используется значение из конфига. Control задано только у контрольного случая
+type CaseConsensus struct {
+	Level       int        `db:"current_level"`
+	IsSolved    bool       `db:"is_solved"`
+	NeedsReview bool       `db:"needs_review"`
+	Control     null.Bool  `db:"control_choice"`
+	Policy      string     `db:"consensus_policy"`
+	K           null.Int   `db:"consensus_k"`
+	Votes       []CaseVote `db:"-"`
+}
+
+// RatedResolution - результат сохранения оценки: случай решен, передан на следующий уровень,
+// передан руководителю или ожидает других оценок
+type RatedResolution struct {
+	RatedID     int
+	Solved      bool
+	RightChoice bool
+	Escalated   bool
+	Review      bool
+}
+
+// CaseVoteFul - оценка случая вместе с оценившим специалистом, Level - текущий уровень специалиста
+type CaseVoteFul struct {
+	RatedID      int         `json:"rated_id" db:"rated_id"`
+	SpecialistID int         `json:"specialist_id" db:"specialist_id"`
+	Fullname     null.String `json:"fullname" db:"fullname"`
+	Level        int         `json:"level" db:"level"`
+	Choice       bool        `json:"choice" db:"choice"`
+	ReasonCode   null.String `json:"reason_code" db:"reason_code"`
+	Comment      null.String `json:"comment" db:"comment"`
+	Status       string      `json:"status" db:"status"`
+	Datetime     time.Time   `json:"datetime" db:"datetime"`
+}
+
+// CaseReviewDecision - окончательное решение руководителя по случаю из очереди на рассмотрение
+type CaseReviewDecision struct {
+	CaseID int  `json:"case_id" validate:"required"`
+	Choice bool `json:"choice"`
+}
+
+// Действия руководителя над закрытым случаем: изменение решения на противоположное и возврат на оценку
+const (
+	OverrideOverturn = "overturn"
+	OverrideReopen   = "reopen"
+)
+
+// CaseOverrideCreate - пересмотр руководителем закрытого случая, обоснование обязательно
+type CaseOverrideCreate struct {
+	CaseID        int    `json:"case_id" validate:"required"`
+	Action        string `json:"action" validate:"required,oneof=overturn reopen"`
+	Justification string `json:"justification" validate:"required"`
+}
+
+// CaseOverride - запись о пересмотре случая. Choice - новое решение, не задано при возврате случая на оценку
+type CaseOverride struct {
+	ID             int       `json:"id" db:"id"`
+	CaseID         int       `json:"case_id" db:"case_id"`
+	ManagerID      null.Int  `json:"manager_id" db:"manager_id"`
+	Action         string    `json:"action" db:"action"`
+	PreviousChoice bool      `json:"previous_choice" db:"previous_choice"`
+	Choice         null.Bool `json:"choice" db:"choice"`
+	Justification  string    `json:"justification" db:"justification"`
+	Datetime       time.Time `json:"datetime" db:"datetime"`
+}
+
+// Причины, по которым специалист пропускает случай
+const (
+	SkipBadPhoto        = "bad_photo"
+	SkipPlateUnreadable = "plate_unreadable"
+	SkipWrongViolation  = "wrong_violation"
+)
+
+// CaseSkipCreate - пропуск случая специалистом, который не может его оценить
+type CaseSkipCreate struct {
+	CaseID int    `json:"case_id" validate:"required"`
+	Reason string `json:"reason" validate:"required,oneof=bad_photo plate_unreadable wrong_violation"`
+}
+
+type CaseSkip struct {
+	CaseSkipCreate
+	SpecialistID int
 }
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"github.com/guregu/null"
+	"time"
+)
+
+// Состояния уведомления: ожидает отправки, отправлено, исчерпало попытки, отменено пересмотром решения
+const (
+	NotificationPending   = "pending"
+	NotificationSent      = "sent"
+	NotificationDead      = "dead"
+	NotificationCancelled = "cancelled"
+)
+
+// Notification - уведомление нарушителю из очереди отправки. Kind - вид уведомления (NotificationFine,
+// NotificationFineCancel), Attempts - число попыток отправки, LastError - ошибка последней попытки,
+// Deliveries - результаты доставки по каналам в порядке отправки
+type Notification struct {
+	ID             int                    `json:"id" db:"id"`
+	CaseID         int                    `json:"case_id" db:"case_id"`
+	Kind           string                 `json:"kind" db:"kind"`
+	IdempotencyKey string                 `json:"idempotency_key" db:"idempotency_key"`
+	Status         string                 `json:"status" db:"status"`
+	Attempts       int                    `json:"attempts" db:"attempts"`
+	NextAttemptAt  time.Time              `json:"next_attempt_at" db:"next_attempt_at"`
+	LastError      null.String            `json:"last_error" db:"last_error"`
+	CreatedAt      time.Time              `json:"created_at" db:"created_at"`
+	SentAt         null.Time              `json:"sent_at" db:"sent_at"`
+	Deliveries     []NotificationDelivery `json:"deliveries" db:"-"`
+}
+
+type NotificationCursor struct {
+	Notifications []Notification `json:"notifications"`
+	Cursor        null.Int       `json:"cursor"`
+}
+
+// NotificationRetry - повторная отправка уведомления, исчерпавшего попытки
+type NotificationRetry struct {
+	NotificationID int `json:"notification_id" validate:"required"`
+}
+
+// Ключи контактов владельца транспорта в таблице contacts, ContactLanguage - язык уведомлений владельца
+const (
+	ContactEmail    = "email"
+	ContactPhone    = "Номер телефона"
+	ContactVK       = "id_vk"
+	ContactLanguage = "language"
+)
+
+// Каналы доставки уведомлений
+const (
+	ChannelEmail   = "email"
+	ChannelSMS     = "sms"
+	ChannelWebhook = "webhook"
+)
+
+// NotificationDelivery - результат попытки доставить уведомление по одному каналу. Recipient - контакт,
+// на который отправлялось уведомление, Error - ошибка канала, если уведомление не доставлено
+type NotificationDelivery struct {
+	ID             int         `json:"id" db:"id"`
+	NotificationID int         `json:"notification_id" db:"notification_id"`
+	Channel        string      `json:"channel" db:"channel"`
+	Recipient      string      `json:"recipient" db:"recipient"`
+	Delivered      bool        `json:"delivered" db:"delivered"`
+	Error          null.String `json:"error" db:"error"`
+	CreatedAt      time.Time   `json:"created_at" db:"created_at"`
+}
+
+// NotificationPreview - уведомление по случаю в том виде, в котором его получит нарушитель.
+// Photo - фото нарушения, которое прикладывается к письму
+type NotificationPreview struct {
+	CaseID   int    `json:"case_id"`
+	Kind     string `json:"kind"`
+	Language string `json:"language"`
+	Subject  string `json:"subject"`
+	Text     string `json:"text"`
+	HTML     string `json:"html"`
+	Photo    string `json:"photo"`
+}
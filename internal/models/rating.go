@@ -2,11 +2,16 @@ package models
 
 import "github.com/guregu/null"
 
+// RatingSpecialistCount - оценки специалиста за период. Total, Correct и Unknown считаются по обычным случаям
+// относительно консенсуса, ControlTotal, ControlCorrect и Accuracy - по контрольным случаям с известным ответом
 type RatingSpecialistCount struct {
 	SpecialistCover
-	Total   int `json:"total"`
-	Correct int `json:"correct"`
-	Unknown int `json:"unknown"`
+	Total          int        `json:"total"`
+	Correct        int        `json:"correct"`
+	Unknown        int        `json:"unknown"`
+	ControlTotal   int        `json:"control_total"`
+	ControlCorrect int        `json:"control_correct"`
+	Accuracy       null.Float `json:"accuracy"`
 }
 
 type RatingSpecialistCountCursor struct {
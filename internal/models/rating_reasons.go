@@ -0,0 +1,21 @@
+package models
+
+import "github.com/guregu/null"
+
+type RatingReasonCreate struct {
+	Code        string `json:"code" db:"code" validate:"required,max=64"`
+	Description string `json:"description" db:"description" validate:"required"`
+}
+
+// RatingReason - причина оценки из справочника руководителей, отключенную причину нельзя указать в новой оценке
+type RatingReason struct {
+	RatingReasonCreate
+	IsActive bool `json:"is_active" db:"is_active"`
+}
+
+// RatingReasonUpdate изменяет описание причины или включает и отключает ее, незаданные поля не меняются
+type RatingReasonUpdate struct {
+	Code        string      `json:"code" validate:"required"`
+	Description null.String `json:"description"`
+	IsActive    null.Bool   `json:"is_active"`
+}
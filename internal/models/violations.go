@@ -7,9 +7,11 @@ type Violation struct {
 	Amount int    `json:"amount"`
 }
 
+// FineData - данные для уведомления нарушителя. Contacts - контакты владельца транспорта из таблицы contacts
+// по ключам (ContactEmail, ContactPhone, ContactVK и другие)
 type FineData struct {
 	Violation
-	Mail           string
+	Contacts       map[string]string
 	PhotoUrl       string
 	Coordinated    string
 	ViolationValue string
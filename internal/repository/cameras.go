@@ -86,7 +86,7 @@ func (c cameraRepo) Get(ctx context.Context, cameraID string) (models.Camera, er
 						FROM cameras
 						WHERE id=$1;`
 
-	err := c.db.QueryRowxContext(ctx, cameraGetQuery, cameraID).Scan(&camera.ID, &camera.Type, &camera.Description, &coords)
+	err := executor(ctx, c.db).QueryRowxContext(ctx, cameraGetQuery, cameraID).Scan(&camera.ID, &camera.Type, &camera.Description, &coords)
 	if err != nil {
 		switch {
 		case errors.Is(err, sql.ErrNoRows):
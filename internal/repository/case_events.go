@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/models"
+	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/utils"
+	"github.com/jmoiron/sqlx"
+)
+
+type caseEventRepo struct {
+	db *sqlx.DB
+}
+
+func InitCaseEventRepo(
+	db *sqlx.DB,
+) CaseEvents {
+	return caseEventRepo{
+		db: db,
+	}
+}
+
+// Create записывает событие случая. Вызывается в транзакции изменения случая, чтобы история не расходилась с ним
+func (e caseEventRepo) Create(ctx context.Context, event models.CaseEventCreate) error {
+	eventCreateQuery := `INSERT INTO case_events (case_id, type, level, specialist_id, manager_id, choice, message)
+						 VALUES ($1, $2, (SELECT current_level FROM cases WHERE id = $1), $3, $4, $5, $6);`
+
+	_, err := executor(ctx, e.db).ExecContext(ctx, eventCreateQuery,
+		event.CaseID, event.Type, event.SpecialistID, event.ManagerID, event.Choice, event.Message)
+	if err != nil {
+		return utils.ErrNormalizer(utils.ErrorPair{Message: utils.ExecErr, Err: err})
+	}
+
+	return nil
+}
+
+// GetByCaseID возвращает историю случая в порядке событий
+func (e caseEventRepo) GetByCaseID(ctx context.Context, caseID int) ([]models.CaseEvent, error) {
+	events := []models.CaseEvent{}
+
+	eventsGetQuery := `SELECT id, case_id, type, level, specialist_id, manager_id, choice, message, datetime
+					   FROM case_events
+					   WHERE case_id = $1
+					   ORDER BY id;`
+
+	err := sqlx.SelectContext(ctx, executor(ctx, e.db), &events, eventsGetQuery, caseID)
+	if err != nil {
+		return nil, utils.ErrNormalizer(utils.ErrorPair{Message: utils.QueryRrr, Err: err})
+	}
+
+	return events, nil
+}
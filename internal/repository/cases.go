@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/consensus"
 	"github.com/gl1n0m3c/IT_LAB_INIT/internal/models"
 	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/config"
 	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/utils"
@@ -14,10 +15,13 @@ import (
 	"github.com/jmoiron/sqlx"
 	"github.com/lib/pq"
 	"github.com/spf13/viper"
+	"time"
 )
 
 type caseRepo struct {
 	db              *sqlx.DB
+	events          CaseEvents
+	notifications   Notifications
 	casesPerRequest int
 }
 
@@ -26,94 +30,118 @@ func InitCaseRepo(
 ) Cases {
 	return caseRepo{
 		db:              db,
+		events:          InitCaseEventRepo(db),
+		notifications:   InitNotificationRepo(db),
 		casesPerRequest: viper.GetInt(config.EntitiesPerRequest),
 	}
 }
 
 func (c caseRepo) CreateCase(ctx context.Context, caseData models.CaseBase) (int, error) {
-	var createdCaseID int
+	return c.createCase(ctx, caseData, null.Bool{})
+}
 
-	tx, err := c.db.Beginx()
-	if err != nil {
-		return 0, utils.ErrNormalizer(utils.ErrorPair{Message: utils.TransactionErr, Err: err})
-	}
+// CreateControlCase создает контрольный случай с известным решением choice
+func (c caseRepo) CreateControlCase(ctx context.Context, caseData models.CaseBase, choice bool) (int, error) {
+	return c.createCase(ctx, caseData, null.BoolFrom(choice))
+}
 
-	caseCreateQuery := `INSERT INTO cases (camera_id, transport, violation_id, violation_value, level, current_level, datetime, photo_url)
-						VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+func (c caseRepo) createCase(ctx context.Context, caseData models.CaseBase, control null.Bool) (int, error) {
+	var createdCaseID int
+
+	caseCreateQuery := `INSERT INTO cases (camera_id, transport, violation_id, violation_value, level, current_level, datetime, photo_url, control_choice)
+						VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 						RETURNING id;`
 
-	err = tx.QueryRowxContext(ctx, caseCreateQuery,
-		caseData.CameraID, caseData.Transport, caseData.ViolationID, caseData.ViolationValue,
-		caseData.Level, caseData.Level, caseData.Datetime, caseData.PhotoUrl).Scan(&createdCaseID)
-	if err != nil {
-		if rbErr := tx.Rollback(); rbErr != nil {
-			return 0, utils.ErrNormalizer(
-				utils.ErrorPair{Message: utils.ScanErr, Err: err},
-				utils.ErrorPair{Message: utils.RollbackErr, Err: rbErr},
-			)
+	err := withTx(ctx, c.db, func(ctx context.Context) error {
+		err := executor(ctx, c.db).QueryRowxContext(ctx, caseCreateQuery,
+			caseData.CameraID, caseData.Transport, caseData.ViolationID, caseData.ViolationValue,
+			caseData.Level, caseData.Level, caseData.Datetime, caseData.PhotoUrl, control).Scan(&createdCaseID)
+		if err != nil {
+			if reference, ok := caseMissingReference(err, caseData); ok {
+				return customErrors.MissingReferencesErr{References: []customErrors.MissingReference{reference}}
+			}
+			return utils.ErrNormalizer(utils.ErrorPair{Message: utils.ScanErr, Err: err})
 		}
 
-		return 0, utils.ErrNormalizer(utils.ErrorPair{Message: utils.ScanErr, Err: err})
+		return c.events.Create(ctx, models.CaseEventCreate{CaseID: createdCaseID, Type: models.CaseEventCreated})
+	})
+	if err != nil {
+		return 0, err
 	}
 
-	if err = tx.Commit(); err != nil {
-		return 0, utils.ErrNormalizer(utils.ErrorPair{Message: utils.CommitErr, Err: err})
+	return createdCaseID, nil
+}
+
+// caseMissingReference определяет по нарушенному внешнему ключу, на какую несуществующую сущность ссылается случай
+func caseMissingReference(err error, caseData models.CaseBase) (customErrors.MissingReference, bool) {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) || pqErr.Code != "23503" {
+		return customErrors.MissingReference{}, false
 	}
 
-	return createdCaseID, nil
+	switch pqErr.Constraint {
+	case "fk_camera":
+		return customErrors.MissingReference{Entity: customErrors.EntityCamera, ID: caseData.CameraID}, true
+	case "fk_violation":
+		return customErrors.MissingReference{Entity: customErrors.EntityViolation, ID: caseData.ViolationID}, true
+	case "fk_transport":
+		return customErrors.MissingReference{Entity: customErrors.EntityTransport, ID: caseData.Transport}, true
+	default:
+		return customErrors.MissingReference{}, false
+	}
 }
 
-func (c caseRepo) UpdateCaseLevel(ctx context.Context, caseID, level int) error {
-	tx, err := c.db.Beginx()
+// CreateCases создает случаи в одной транзакции. Ошибка отдельного случая откатывает только его вставку
+// и возвращается в errs под тем же индексом, остальные случаи сохраняются
+func (c caseRepo) CreateCases(ctx context.Context, cases []models.CaseBase) ([]int, []error, error) {
+	createdCaseIDs := make([]int, len(cases))
+	errs := make([]error, len(cases))
+
+	err := withTx(ctx, c.db, func(ctx context.Context) error {
+		tx := executor(ctx, c.db)
+
+		for i, caseData := range cases {
+			if _, err := tx.ExecContext(ctx, `SAVEPOINT case_create;`); err != nil {
+				return utils.ErrNormalizer(utils.ErrorPair{Message: utils.ExecErr, Err: err})
+			}
+
+			createdCaseID, err := c.CreateCase(ctx, caseData)
+			if err != nil {
+				errs[i] = err
+
+				_, err = tx.ExecContext(ctx, `ROLLBACK TO SAVEPOINT case_create;`)
+			} else {
+				createdCaseIDs[i] = createdCaseID
+				_, err = tx.ExecContext(ctx, `RELEASE SAVEPOINT case_create;`)
+			}
+			if err != nil {
+				return utils.ErrNormalizer(utils.ErrorPair{Message: utils.ExecErr, Err: err})
+			}
+		}
+
+		return nil
+	})
 	if err != nil {
-		return utils.ErrNormalizer(utils.ErrorPair{Message: utils.TransactionErr, Err: err})
+		return nil, nil, err
 	}
 
+	return createdCaseIDs, errs, nil
+}
+
+func (c caseRepo) UpdateCaseLevel(ctx context.Context, caseID, level int) error {
 	updateQuery := `UPDATE cases SET current_level = $1 WHERE id=$2;`
 
-	res, err := c.db.ExecContext(ctx, updateQuery, level, caseID)
+	res, err := executor(ctx, c.db).ExecContext(ctx, updateQuery, level, caseID)
 	if err != nil {
-		if rbErr := tx.Rollback(); rbErr != nil {
-			return utils.ErrNormalizer(
-				utils.ErrorPair{Message: utils.ExecErr, Err: err},
-				utils.ErrorPair{Message: utils.RollbackErr, Err: rbErr},
-			)
-		}
 		return utils.ErrNormalizer(utils.ErrorPair{Message: utils.ExecErr, Err: err})
 	}
-	count, err := res.RowsAffected()
-	if err != nil {
-		if rbErr := tx.Rollback(); rbErr != nil {
-			return utils.ErrNormalizer(
-				utils.ErrorPair{Message: utils.RowsErr, Err: err},
-				utils.ErrorPair{Message: utils.RollbackErr, Err: rbErr},
-			)
-		}
-		return utils.ErrNormalizer(utils.ErrorPair{Message: utils.RowsErr, Err: err})
-	}
-	if count != 1 {
-		if rbErr := tx.Rollback(); rbErr != nil {
-			return utils.ErrNormalizer(
-				utils.ErrorPair{Message: utils.RowsErr, Err: fmt.Errorf(utils.CountErr, count)},
-				utils.ErrorPair{Message: utils.RollbackErr, Err: rbErr},
-			)
-		}
-		return utils.ErrNormalizer(utils.ErrorPair{Message: utils.RowsErr, Err: fmt.Errorf(utils.CountErr, count)})
-	}
 
-	if err = tx.Commit(); err != nil {
-		return utils.ErrNormalizer(utils.ErrorPair{Message: utils.CommitErr, Err: err})
-	}
-
-	return nil
+	return checkAffectedOne(res)
 }
 
+// UpdateCaseSetSolved отмечает случай решенным, проставляет статусы его оценкам
+// и обновляет серии правильных ответов оценивших специалистов. Все три изменения выполняются в одной транзакции
 func (c caseRepo) UpdateCaseSetSolved(ctx context.Context, caseID int, rightChoice bool) error {
-	tx, err := c.db.Beginx()
-	if err != nil {
-		return utils.ErrNormalizer(utils.ErrorPair{Message: utils.TransactionErr, Err: err})
-	}
-
 	updateCaseSolvedQuery := `UPDATE cases SET is_solved=true WHERE id=$1;`
 
 	updateRatedSpecialistsQuery := `UPDATE rated_cases
@@ -134,66 +162,37 @@ func (c caseRepo) UpdateCaseSetSolved(ctx context.Context, caseID int, rightChoi
 							   	ELSE s.row
 							   END
         					   FROM rated_cases rc
-        					   WHERE rc.case_id = $1 AND rc.specialist_id = s.id
-        					   RETURNING s.id`
+        					   WHERE rc.case_id = $1 AND rc.specialist_id = s.id`
 
-	res, err := c.db.ExecContext(ctx, updateCaseSolvedQuery, caseID)
-	if err != nil {
-		if rbErr := tx.Rollback(); rbErr != nil {
-			return utils.ErrNormalizer(
-				utils.ErrorPair{Message: utils.ExecErr, Err: err},
-				utils.ErrorPair{Message: utils.RollbackErr, Err: rbErr},
-			)
-		}
-		return utils.ErrNormalizer(utils.ErrorPair{Message: utils.ExecErr, Err: err})
-	}
-	count, err := res.RowsAffected()
-	if err != nil {
-		if rbErr := tx.Rollback(); rbErr != nil {
-			return utils.ErrNormalizer(
-				utils.ErrorPair{Message: utils.RowsErr, Err: err},
-				utils.ErrorPair{Message: utils.RollbackErr, Err: rbErr},
-			)
+	return withTx(ctx, c.db, func(ctx context.Context) error {
+		tx := executor(ctx, c.db)
+
+		res, err := tx.ExecContext(ctx, updateCaseSolvedQuery, caseID)
+		if err != nil {
+			return utils.ErrNormalizer(utils.ErrorPair{Message: utils.ExecErr, Err: err})
 		}
-		return utils.ErrNormalizer(utils.ErrorPair{Message: utils.RowsErr, Err: err})
-	}
-	if count != 1 {
-		if rbErr := tx.Rollback(); rbErr != nil {
-			return utils.ErrNormalizer(
-				utils.ErrorPair{Message: utils.RowsErr, Err: fmt.Errorf(utils.CountErr, count)},
-				utils.ErrorPair{Message: utils.RollbackErr, Err: rbErr},
-			)
+		if err = checkAffectedOne(res); err != nil {
+			return err
 		}
-		return utils.ErrNormalizer(utils.ErrorPair{Message: utils.RowsErr, Err: fmt.Errorf(utils.CountErr, count)})
-	}
 
-	_, err = c.db.ExecContext(ctx, updateRatedSpecialistsQuery, rightChoice, caseID)
-	if err != nil {
-		if rbErr := tx.Rollback(); rbErr != nil {
-			return utils.ErrNormalizer(
-				utils.ErrorPair{Message: utils.ExecErr, Err: err},
-				utils.ErrorPair{Message: utils.RollbackErr, Err: rbErr},
-			)
+		if _, err = tx.ExecContext(ctx, updateRatedSpecialistsQuery, rightChoice, caseID); err != nil {
+			return utils.ErrNormalizer(utils.ErrorPair{Message: utils.ExecErr, Err: err})
 		}
-		return utils.ErrNormalizer(utils.ErrorPair{Message: utils.ExecErr, Err: err})
-	}
 
-	_, err = c.db.ExecContext(ctx, updateSpecialistsQuery, caseID)
-	if err != nil {
-		if rbErr := tx.Rollback(); rbErr != nil {
-			return utils.ErrNormalizer(
-				utils.ErrorPair{Message: utils.ExecErr, Err: err},
-				utils.ErrorPair{Message: utils.RollbackErr, Err: rbErr},
-			)
+		if _, err = tx.ExecContext(ctx, updateSpecialistsQuery, caseID); err != nil {
+			return utils.ErrNormalizer(utils.ErrorPair{Message: utils.ExecErr, Err: err})
 		}
-		return utils.ErrNormalizer(utils.ErrorPair{Message: utils.ExecErr, Err: err})
-	}
 
-	return nil
+		return nil
+	})
 }
 
+// GetFineData возвращает данные для уведомления по случаю с контактами владельца транспорта
 func (c caseRepo) GetFineData(ctx context.Context, caseID int) (models.FineData, error) {
-	var fineData models.FineData
+	var (
+		fineData models.FineData
+		contacts []byte
+	)
 
 	getFineDataQuery := `SELECT cn.contacts, c.photo_url, cm.coordinates, c.violation_value, v.type, v.amount, c.datetime
 						 FROM cases c
@@ -201,54 +200,199 @@ func (c caseRepo) GetFineData(ctx context.Context, caseID int) (models.FineData,
 						 JOIN contacts cn ON c.transport = cn.transport
 						 JOIN cameras cm ON c.camera_id = cm.id
 						 WHERE c.id=$1`
-	err := c.db.QueryRowxContext(ctx, getFineDataQuery, caseID).Scan(&fineData.Mail, &fineData.PhotoUrl, &fineData.Coordinated,
+	err := executor(ctx, c.db).QueryRowxContext(ctx, getFineDataQuery, caseID).Scan(&contacts, &fineData.PhotoUrl, &fineData.Coordinated,
 		&fineData.ViolationValue, &fineData.Violation.Type, &fineData.Violation.Amount, &fineData.Date)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.FineData{}, customErrors.NoRowsCaseErr
+		}
 		return models.FineData{}, utils.ErrNormalizer(utils.ErrorPair{Message: utils.ScanErr, Err: err})
 	}
 
-	var contacts map[string]string
-	if err := json.Unmarshal([]byte(fineData.Mail), &contacts); err != nil {
+	if err := json.Unmarshal(contacts, &fineData.Contacts); err != nil {
 		return models.FineData{}, err // Используйте вашу собственную обработку ошибок
 	}
-	fineData.Mail = contacts["email"]
 
 	return fineData, nil
 }
 
-func (c caseRepo) GetCaseLevelSolvedRatingsTrueByID(ctx context.Context, caseID int) (int, int, int, bool, error) {
-	var level int
-	var isSolved bool
+// GetCaseConsensus возвращает уровень и статус случая, политику консенсуса его типа нарушения
+// и все оценки случая с текущими уровнями оценивших специалистов
+func (c caseRepo) GetCaseConsensus(ctx context.Context, caseID int) (models.CaseConsensus, error) {
+	caseConsensusQuery := `SELECT c.current_level, c.is_solved, c.needs_review, c.control_choice, v.consensus_policy, v.consensus_k
+						   FROM cases c
+						   JOIN violations v ON c.violation_id = v.id
+						   WHERE c.id = $1;`
+
+	return c.selectCaseConsensus(ctx, caseConsensusQuery, caseID)
+}
+
+// CreateRatedConsensus сохраняет оценку и принимает решение по случаю в одной транзакции.
+// Строка случая блокируется до конца транзакции, поэтому параллельные оценки одного случая
+// обрабатываются по очереди и видят оценки друг друга. Случай, который без консенсуса поднялся бы
+// выше maxLevel, передается на рассмотрение руководителю. Контрольный случай в консенсусе не участвует:
+// после K оценок на его уровне он закрывается без решения и штрафа и, как обычный случай, уходит из очереди
+func (c caseRepo) CreateRatedConsensus(ctx context.Context, rated models.RatedBase, specialistLevel, defaultK, maxLevel int) (models.RatedResolution, error) {
+	var resolution models.RatedResolution
+
+	caseConsensusQuery := `SELECT c.current_level, c.is_solved, c.needs_review, c.control_choice, v.consensus_policy, v.consensus_k
+						   FROM cases c
+						   JOIN violations v ON c.violation_id = v.id
+						   WHERE c.id = $1
+						   FOR UPDATE OF c;`
 
-	caseLevelQuery := `SELECT c.current_level, c.is_solved
-                       FROM cases c
-                       WHERE c.id = $1;`
+	caseReviewQuery := `UPDATE cases SET needs_review = true WHERE id = $1;`
 
-	err := c.db.QueryRowxContext(ctx, caseLevelQuery, caseID).Scan(&level, &isSolved)
+	controlCloseQuery := `UPDATE cases SET is_solved = true WHERE id = $1;`
+
+	reservationReleaseQuery := `DELETE FROM case_reservations
+								WHERE case_id = $1 AND (specialist_id = $2 OR $3);`
+
+	var controlClosed bool
+
+	err := withTx(ctx, c.db, func(ctx context.Context) error {
+		caseConsensus, err := c.selectCaseConsensus(ctx, caseConsensusQuery, rated.CaseID)
+		if err != nil {
+			return err
+		}
+
+		policy, err := consensus.ByName(caseConsensus.Policy)
+		if err != nil {
+			return err
+		}
+
+		// Количество оценок на уровне задается типом нарушения, по умолчанию - K из конфига
+		k := defaultK
+		if caseConsensus.K.Valid {
+			k = int(caseConsensus.K.Int64)
+		}
+
+		var numberOfRated int
+		for _, vote := range caseConsensus.Votes {
+			if vote.Level == caseConsensus.Level {
+				numberOfRated++
+			}
+		}
+
+		if caseConsensus.IsSolved || caseConsensus.NeedsReview || numberOfRated >= k {
+			return customErrors.CaseAlreadySolved
+		}
+		if caseConsensus.Level != specialistLevel {
+			return customErrors.UserBadLevel
+		}
+
+		resolution.RatedID, err = c.CreateRated(ctx, rated)
+		if err != nil {
+			return err
+		}
+
+		// Контрольный случай закрывается последней оценкой уровня без решения, события и штрафа
+		if caseConsensus.Control.Valid && k-1 == numberOfRated {
+			res, err := executor(ctx, c.db).ExecContext(ctx, controlCloseQuery, rated.CaseID)
+			if err != nil {
+				return utils.ErrNormalizer(utils.ErrorPair{Message: utils.ExecErr, Err: err})
+			}
+			if err = checkAffectedOne(res); err != nil {
+				return err
+			}
+			controlClosed = true
+		}
+
+		// Проверка на консенсус
+		if !caseConsensus.Control.Valid && k-1 == numberOfRated {
+			votes := append(caseConsensus.Votes, models.CaseVote{Choice: rated.Choice, Level: specialistLevel})
+
+			// Решение, передача случая выше или руководителю записываются в историю от имени последней оценки
+			event := models.CaseEventCreate{CaseID: rated.CaseID, SpecialistID: null.IntFrom(int64(rated.SpecialistID))}
+
+			resolution.RightChoice, resolution.Solved = policy.Decide(votes, caseConsensus.Level)
+			switch {
+			case resolution.Solved:
+				event.Type, event.Choice = models.CaseEventSolved, null.BoolFrom(resolution.RightChoice)
+				err = c.UpdateCaseSetSolved(ctx, rated.CaseID, resolution.RightChoice)
+			case caseConsensus.Level >= maxLevel:
+				resolution.Review = true
+				event.Type = models.CaseEventReview
+				res, execErr := executor(ctx, c.db).ExecContext(ctx, caseReviewQuery, rated.CaseID)
+				if execErr != nil {
+					return utils.ErrNormalizer(utils.ErrorPair{Message: utils.ExecErr, Err: execErr})
+				}
+				err = checkAffectedOne(res)
+			default:
+				resolution.Escalated = true
+				event.Type = models.CaseEventEscalated
+				err = c.UpdateCaseLevel(ctx, rated.CaseID, caseConsensus.Level+1)
+			}
+			if err != nil {
+				return err
+			}
+
+			if err = c.events.Create(ctx, event); err != nil {
+				return err
+			}
+
+			// Штраф назначается, только если специалисты подтвердили нарушение
+			if resolution.Solved && resolution.RightChoice {
+				if err = c.notifications.Enqueue(ctx, rated.CaseID, models.NotificationFine); err != nil {
+					return err
+				}
+			}
+		}
+
+		// Оценка снимает закрепление случая за специалистом, а решение по случаю - все его закрепления
+		_, err = executor(ctx, c.db).ExecContext(ctx, reservationReleaseQuery,
+			rated.CaseID, rated.SpecialistID, resolution.Solved || resolution.Escalated || resolution.Review || controlClosed)
+		if err != nil {
+			return utils.ErrNormalizer(utils.ErrorPair{Message: utils.ExecErr, Err: err})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return models.RatedResolution{}, err
+	}
+
+	return resolution, nil
+}
+
+// selectCaseConsensus выполняет запрос данных случая для политики консенсуса и дополняет их оценками случая
+func (c caseRepo) selectCaseConsensus(ctx context.Context, caseConsensusQuery string, caseID int) (models.CaseConsensus, error) {
+	var caseConsensus models.CaseConsensus
+
+	err := executor(ctx, c.db).QueryRowxContext(ctx, caseConsensusQuery, caseID).StructScan(&caseConsensus)
 	if err != nil {
 		switch {
 		case errors.Is(err, sql.ErrNoRows):
-			return 0, 0, 0, false, customErrors.NoRowsCaseErr
+			return models.CaseConsensus{}, customErrors.NoRowsCaseErr
 		default:
-			return 0, 0, 0, false, utils.ErrNormalizer(utils.ErrorPair{Message: utils.ScanErr, Err: err})
+			return models.CaseConsensus{}, utils.ErrNormalizer(utils.ErrorPair{Message: utils.ScanErr, Err: err})
 		}
 	}
 
-	var num, numTrue int
-	ratingsQuery := `SELECT COUNT(rs.case_id), COALESCE(SUM(CASE WHEN rs.choice = true THEN 1 ELSE 0 END), 0)
-                     FROM rated_cases rs
-                     JOIN specialists s ON rs.specialist_id = s.id
-                     WHERE rs.case_id = $1 AND s.level = $2;`
+	votesQuery := `SELECT rc.choice, s.level
+				   FROM rated_cases rc
+				   JOIN specialists s ON rc.specialist_id = s.id
+				   WHERE rc.case_id = $1
+				   ORDER BY rc.id;`
 
-	err = c.db.QueryRowxContext(ctx, ratingsQuery, caseID, level).Scan(&num, &numTrue)
+	err = sqlx.SelectContext(ctx, executor(ctx, c.db), &caseConsensus.Votes, votesQuery, caseID)
 	if err != nil {
-		switch {
-		case !errors.Is(err, sql.ErrNoRows):
-			return 0, 0, 0, false, utils.ErrNormalizer(utils.ErrorPair{Message: utils.ScanErr, Err: err})
-		}
+		return models.CaseConsensus{}, utils.ErrNormalizer(utils.ErrorPair{Message: utils.QueryRrr, Err: err})
 	}
 
-	return level, num, numTrue, isSolved, nil
+	return caseConsensus, nil
+}
+
+// checkAffectedOne проверяет, что запрос изменил ровно одну запись
+func checkAffectedOne(res sql.Result) error {
+	count, err := res.RowsAffected()
+	if err != nil {
+		return utils.ErrNormalizer(utils.ErrorPair{Message: utils.RowsErr, Err: err})
+	}
+	if count != 1 {
+		return utils.ErrNormalizer(utils.ErrorPair{Message: utils.RowsErr, Err: fmt.Errorf(utils.CountErr, count)})
+	}
+	return nil
 }
 
 func (c caseRepo) GetCasesByLevel(ctx context.Context, specialistID, level, cursor int) (models.CaseCursor, error) {
@@ -261,10 +405,12 @@ func (c caseRepo) GetCasesByLevel(ctx context.Context, specialistID, level, curs
 					  FROM cases c
 					  LEFT JOIN violations v ON c.violation_id = v.id
 					  LEFT JOIN rated_cases rc ON c.id = rc.case_id AND rc.specialist_id = $1
-					  WHERE c.level = $2 AND rc.id IS NULL AND c.id >= $3 AND c.is_solved = false
+					  LEFT JOIN case_skips cs ON c.id = cs.case_id AND cs.specialist_id = $1
+					  WHERE c.current_level = $2 AND rc.id IS NULL AND cs.id IS NULL AND c.id >= $3 AND c.is_solved = false
+					  AND c.needs_review = false
 					  ORDER BY id LIMIT $4;`
 
-	err := c.db.SelectContext(ctx, &cases, casesGetQueue, specialistID, level, cursor, c.casesPerRequest+1)
+	err := sqlx.SelectContext(ctx, executor(ctx, c.db), &cases, casesGetQueue, specialistID, level, cursor, c.casesPerRequest+1)
 	if err != nil {
 		return models.CaseCursor{}, utils.ErrNormalizer(utils.ErrorPair{Message: utils.QueryRrr, Err: err})
 	}
@@ -280,85 +426,427 @@ func (c caseRepo) GetCasesByLevel(ctx context.Context, specialistID, level, curs
 	return casesWithCursor, nil
 }
 
-func (c caseRepo) DeleteCase(ctx context.Context, caseID int) error {
-	tx, err := c.db.Beginx()
+// ReserveNextCase закрепляет за специалистом на ttl следующий открытый случай его уровня, который он еще не оценивал
+// и который не закреплен за другим специалистом. Если у специалиста уже есть действующее закрепление, возвращается
+// закрепленный случай. Случаи, заблокированные параллельными вызовами, пропускаются, поэтому два специалиста
+// не могут получить один и тот же случай
+func (c caseRepo) ReserveNextCase(ctx context.Context, specialistID, level int, ttl time.Duration) (models.CaseReservation, error) {
+	var reservation models.CaseReservation
+
+	reservationGetQuery := `SELECT c.id, c.camera_id, c.transport, c.violation_id, c.violation_value, c.level, c.current_level,
+							c.datetime, c.photo_url, v.type, v.amount, r.expires_at
+							FROM case_reservations r
+							JOIN cases c ON r.case_id = c.id
+							JOIN violations v ON c.violation_id = v.id
+							WHERE r.specialist_id = $1 AND r.expires_at > NOW() AND c.current_level = $2
+							AND c.is_solved = false AND c.needs_review = false;`
+
+	nextCaseQuery := `SELECT c.id
+					  FROM cases c
+					  WHERE c.current_level = $2 AND c.is_solved = false AND c.needs_review = false
+					  AND NOT EXISTS (SELECT 1 FROM rated_cases rc WHERE rc.case_id = c.id AND rc.specialist_id = $1)
+					  AND NOT EXISTS (SELECT 1 FROM case_skips cs WHERE cs.case_id = c.id AND cs.specialist_id = $1)
+					  AND NOT EXISTS (SELECT 1 FROM case_reservations r WHERE r.case_id = c.id AND r.expires_at > NOW())
+					  ORDER BY c.id
+					  LIMIT 1
+					  FOR UPDATE OF c SKIP LOCKED;`
+
+	reservationsDeleteQuery := `DELETE FROM case_reservations WHERE specialist_id = $1;`
+
+	reservationCreateQuery := `INSERT INTO case_reservations (case_id, specialist_id, expires_at)
+							   VALUES ($1, $2, NOW() + make_interval(secs => $3))
+							   ON CONFLICT (case_id) DO UPDATE
+							   SET specialist_id = EXCLUDED.specialist_id, expires_at = EXCLUDED.expires_at;`
+
+	err := withTx(ctx, c.db, func(ctx context.Context) error {
+		tx := executor(ctx, c.db)
+
+		err := tx.QueryRowxContext(ctx, reservationGetQuery, specialistID, level).StructScan(&reservation)
+		switch {
+		case err == nil:
+			return nil
+		case !errors.Is(err, sql.ErrNoRows):
+			return utils.ErrNormalizer(utils.ErrorPair{Message: utils.ScanErr, Err: err})
+		}
+
+		var caseID int
+		err = tx.QueryRowxContext(ctx, nextCaseQuery, specialistID, level).Scan(&caseID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return customErrors.NoCasesToReserve
+			}
+			return utils.ErrNormalizer(utils.ErrorPair{Message: utils.ScanErr, Err: err})
+		}
+
+		// Закрепления, которые специалист не использовал, снимаются
+		if _, err = tx.ExecContext(ctx, reservationsDeleteQuery, specialistID); err != nil {
+			return utils.ErrNormalizer(utils.ErrorPair{Message: utils.ExecErr, Err: err})
+		}
+
+		if _, err = tx.ExecContext(ctx, reservationCreateQuery, caseID, specialistID, ttl.Seconds()); err != nil {
+			return utils.ErrNormalizer(utils.ErrorPair{Message: utils.ExecErr, Err: err})
+		}
+
+		err = tx.QueryRowxContext(ctx, reservationGetQuery, specialistID, level).StructScan(&reservation)
+		if err != nil {
+			return utils.ErrNormalizer(utils.ErrorPair{Message: utils.ScanErr, Err: err})
+		}
+
+		return nil
+	})
 	if err != nil {
-		return utils.ErrNormalizer(utils.ErrorPair{Message: utils.TransactionErr, Err: err})
+		return models.CaseReservation{}, err
 	}
 
-	caseDeleteQuery := `DELETE FROM cases WHERE id=$1;`
+	return reservation, nil
+}
+
+// GetReviewCases возвращает нерешенные случаи, ожидающие решения руководителя
+func (c caseRepo) GetReviewCases(ctx context.Context, cursor int) (models.CaseCursor, error) {
+	var cases []models.CaseViolations
+	var nextCursor null.Int
 
-	res, err := tx.ExecContext(ctx, caseDeleteQuery, caseID)
+	casesGetQuery := `SELECT c.id, c.camera_id, c.transport, c.violation_id, c.violation_value, c.level, c.current_level,
+					  c.datetime, c.photo_url, v.type, v.amount
+					  FROM cases c
+					  JOIN violations v ON c.violation_id = v.id
+					  WHERE c.needs_review = true AND c.is_solved = false AND c.id >= $1
+					  ORDER BY c.id LIMIT $2;`
+
+	err := sqlx.SelectContext(ctx, executor(ctx, c.db), &cases, casesGetQuery, cursor, c.casesPerRequest+1)
 	if err != nil {
-		if rbErr := tx.Rollback(); rbErr != nil {
-			return utils.ErrNormalizer(
-				utils.ErrorPair{Message: utils.ExecErr, Err: err},
-				utils.ErrorPair{Message: utils.RollbackErr, Err: rbErr},
-			)
-		}
-		return utils.ErrNormalizer(utils.ErrorPair{Message: utils.ExecErr, Err: err})
+		return models.CaseCursor{}, utils.ErrNormalizer(utils.ErrorPair{Message: utils.QueryRrr, Err: err})
 	}
 
-	count, err := res.RowsAffected()
+	if len(cases) == c.casesPerRequest+1 {
+		nextCursor = null.IntFrom(int64(cases[len(cases)-1].ID))
+		cases = cases[:len(cases)-1]
+	}
+
+	return models.CaseCursor{Cases: cases, Cursor: nextCursor}, nil
+}
+
+// GetCaseVotes возвращает все оценки случая в порядке их создания
+func (c caseRepo) GetCaseVotes(ctx context.Context, caseID int) ([]models.CaseVoteFul, error) {
+	var exists bool
+	votes := []models.CaseVoteFul{}
+
+	caseExistsQuery := `SELECT EXISTS (SELECT 1 FROM cases WHERE id = $1);`
+
+	votesGetQuery := `SELECT rc.id AS rated_id, rc.specialist_id, s.fullname, s.level, rc.choice, rc.reason_code, rc.comment,
+					  rc.status, rc.datetime
+					  FROM rated_cases rc
+					  JOIN specialists s ON rc.specialist_id = s.id
+					  WHERE rc.case_id = $1
+					  ORDER BY rc.id;`
+
+	err := executor(ctx, c.db).QueryRowxContext(ctx, caseExistsQuery, caseID).Scan(&exists)
 	if err != nil {
-		if rbErr := tx.Rollback(); rbErr != nil {
-			return utils.ErrNormalizer(
-				utils.ErrorPair{Message: utils.RowsErr, Err: err},
-				utils.ErrorPair{Message: utils.RollbackErr, Err: rbErr},
-			)
-		}
-		return utils.ErrNormalizer(utils.ErrorPair{Message: utils.RowsErr, Err: err})
+		return nil, utils.ErrNormalizer(utils.ErrorPair{Message: utils.ScanErr, Err: err})
+	}
+	if !exists {
+		return nil, customErrors.NoRowsCaseErr
 	}
 
-	if count != 1 {
-		if rbErr := tx.Rollback(); rbErr != nil {
-			return utils.ErrNormalizer(
-				utils.ErrorPair{Message: utils.RowsErr, Err: fmt.Errorf(utils.CountErr, count)},
-				utils.ErrorPair{Message: utils.RollbackErr, Err: rbErr},
-			)
+	err = sqlx.SelectContext(ctx, executor(ctx, c.db), &votes, votesGetQuery, caseID)
+	if err != nil {
+		return nil, utils.ErrNormalizer(utils.ErrorPair{Message: utils.QueryRrr, Err: err})
+	}
+
+	return votes, nil
+}
+
+// ResolveReviewCase закрывает случай из очереди руководителя его решением так же, как при консенсусе специалистов
+func (c caseRepo) ResolveReviewCase(ctx context.Context, managerID int, decision models.CaseReviewDecision) error {
+	var isSolved, needsReview bool
+
+	caseGetQuery := `SELECT is_solved, needs_review FROM cases WHERE id = $1 FOR UPDATE;`
+
+	caseReviewedQuery := `UPDATE cases SET needs_review = false WHERE id = $1;`
+
+	return withTx(ctx, c.db, func(ctx context.Context) error {
+		tx := executor(ctx, c.db)
+
+		err := tx.QueryRowxContext(ctx, caseGetQuery, decision.CaseID).Scan(&isSolved, &needsReview)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return customErrors.NoRowsCaseErr
+			}
+			return utils.ErrNormalizer(utils.ErrorPair{Message: utils.ScanErr, Err: err})
 		}
-		return utils.ErrNormalizer(utils.ErrorPair{Message: utils.RowsErr, Err: fmt.Errorf(utils.CountErr, count)})
+		if isSolved || !needsReview {
+			return customErrors.CaseNotInReview
+		}
+
+		if err = c.UpdateCaseSetSolved(ctx, decision.CaseID, decision.Choice); err != nil {
+			return err
+		}
+
+		if _, err = tx.ExecContext(ctx, caseReviewedQuery, decision.CaseID); err != nil {
+			return utils.ErrNormalizer(utils.ErrorPair{Message: utils.ExecErr, Err: err})
+		}
+
+		err = c.events.Create(ctx, models.CaseEventCreate{
+			CaseID:    decision.CaseID,
+			Type:      models.CaseEventSolved,
+			ManagerID: null.IntFrom(int64(managerID)),
+			Choice:    null.BoolFrom(decision.Choice),
+		})
+		if err != nil {
+			return err
+		}
+
+		if decision.Choice {
+			return c.notifications.Enqueue(ctx, decision.CaseID, models.NotificationFine)
+		}
+
+		return nil
+	})
+}
+
+// OverrideCase пересматривает закрытый случай по решению руководителя. При изменении решения оценки специалистов
+// проверяются заново, при возврате на оценку случай передается на следующий уровень, а с максимального уровня -
+// в очередь руководителя. Серии верных оценок специалистов, оценивших случай, пересчитываются по всей их истории.
+// Уведомления о штрафе или его отмене ставятся в очередь в той же транзакции
+func (c caseRepo) OverrideCase(ctx context.Context, managerID int, override models.CaseOverrideCreate, maxLevel int) (models.CaseOverride, error) {
+	var caseOverride models.CaseOverride
+
+	caseGetQuery := `SELECT is_solved FROM cases WHERE id = $1 FOR UPDATE;`
+
+	// Оценка верна, только если совпадает с решением, поэтому решение восстанавливается по любой оценке случая
+	decisionGetQuery := `SELECT choice = (status = 'Correct'::status_type) FROM rated_cases WHERE case_id = $1 LIMIT 1;`
+
+	ratedOverturnQuery := `UPDATE rated_cases
+						   SET status =
+							   CASE
+						   WHEN choice = $1 THEN 'Correct'::status_type
+						   ELSE 'Incorrect'::status_type
+						   END
+						   WHERE case_id = $2;`
+
+	ratedReopenQuery := `UPDATE rated_cases SET status = 'Unknown'::status_type WHERE case_id = $1;`
+
+	caseReopenQuery := `UPDATE cases
+						SET is_solved = false,
+							current_level = CASE WHEN current_level < $2 THEN current_level + 1 ELSE current_level END,
+							needs_review = current_level >= $2
+						WHERE id = $1
+						RETURNING needs_review;`
+
+	overrideCreateQuery := `INSERT INTO case_overrides (case_id, manager_id, action, previous_choice, choice, justification)
+							VALUES ($1, $2, $3, $4, $5, $6)
+							RETURNING id, case_id, manager_id, action, previous_choice, choice, justification, datetime;`
+
+	err := withTx(ctx, c.db, func(ctx context.Context) error {
+		tx := executor(ctx, c.db)
+
+		var isSolved bool
+		err := tx.QueryRowxContext(ctx, caseGetQuery, override.CaseID).Scan(&isSolved)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return customErrors.NoRowsCaseErr
+			}
+			return utils.ErrNormalizer(utils.ErrorPair{Message: utils.ScanErr, Err: err})
+		}
+		if !isSolved {
+			return customErrors.CaseNotSolved
+		}
+
+		var previousChoice bool
+		err = tx.QueryRowxContext(ctx, decisionGetQuery, override.CaseID).Scan(&previousChoice)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return customErrors.CaseNotSolved
+			}
+			return utils.ErrNormalizer(utils.ErrorPair{Message: utils.ScanErr, Err: err})
+		}
+
+		var (
+			choice      null.Bool
+			needsReview bool
+		)
+		switch override.Action {
+		case models.OverrideOverturn:
+			choice = null.BoolFrom(!previousChoice)
+			_, err = tx.ExecContext(ctx, ratedOverturnQuery, choice.Bool, override.CaseID)
+		case models.OverrideReopen:
+			if _, err = tx.ExecContext(ctx, ratedReopenQuery, override.CaseID); err == nil {
+				err = tx.QueryRowxContext(ctx, caseReopenQuery, override.CaseID, maxLevel).Scan(&needsReview)
+			}
+		default:
+			return fmt.Errorf("неизвестное действие над случаем: %s", override.Action)
+		}
+		if err != nil {
+			return utils.ErrNormalizer(utils.ErrorPair{Message: utils.ExecErr, Err: err})
+		}
+
+		if err = c.updateSpecialistsRows(ctx, override.CaseID); err != nil {
+			return err
+		}
+
+		err = tx.QueryRowxContext(ctx, overrideCreateQuery, override.CaseID, managerID, override.Action,
+			previousChoice, choice, override.Justification).StructScan(&caseOverride)
+		if err != nil {
+			return utils.ErrNormalizer(utils.ErrorPair{Message: utils.ScanErr, Err: err})
+		}
+
+		event := models.CaseEventCreate{
+			CaseID:    override.CaseID,
+			Type:      models.CaseEventOverturned,
+			ManagerID: null.IntFrom(int64(managerID)),
+			Choice:    choice,
+			Message:   null.StringFrom(override.Justification),
+		}
+		if override.Action == models.OverrideReopen {
+			event.Type = models.CaseEventReopened
+		}
+		if err = c.events.Create(ctx, event); err != nil {
+			return err
+		}
+
+		// Неотправленные уведомления о прежнем решении отменяются. Нарушителю сообщается об отмене штрафа,
+		// только если штраф до него дошел, и о штрафе, только если о нем еще не сообщалось
+		if err = c.notifications.CancelUnsent(ctx, override.CaseID); err != nil {
+			return err
+		}
+
+		lastSent, err := c.notifications.GetLastSentKind(ctx, override.CaseID)
+		if err != nil {
+			return err
+		}
+
+		fineNotified := lastSent.Valid && lastSent.String == models.NotificationFine
+		fineConfirmed := choice.Valid && choice.Bool
+		switch {
+		case fineNotified && !fineConfirmed:
+			err = c.notifications.Enqueue(ctx, override.CaseID, models.NotificationFineCancel)
+		case !fineNotified && fineConfirmed:
+			err = c.notifications.Enqueue(ctx, override.CaseID, models.NotificationFine)
+		}
+		if err != nil {
+			return err
+		}
+
+		// Возвращенный случай, как и при отсутствии консенсуса, передается выше или руководителю
+		if override.Action == models.OverrideReopen {
+			event = models.CaseEventCreate{CaseID: override.CaseID, Type: models.CaseEventEscalated}
+			if needsReview {
+				event.Type = models.CaseEventReview
+			}
+			return c.events.Create(ctx, event)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return models.CaseOverride{}, err
 	}
 
-	if err = tx.Commit(); err != nil {
-		return utils.ErrNormalizer(utils.ErrorPair{Message: utils.CommitErr, Err: err})
+	return caseOverride, nil
+}
+
+// updateSpecialistsRows заново считает серии верных оценок специалистов, оценивших случай. Серии считаются
+// в порядке оценок, неверная оценка прерывает серию, оценки нерешенных случаев не учитываются
+func (c caseRepo) updateSpecialistsRows(ctx context.Context, caseID int) error {
+	updateRowsQuery := `WITH affected AS (
+							SELECT DISTINCT specialist_id FROM rated_cases WHERE case_id = $1
+						),
+						ordered AS (
+							SELECT rc.specialist_id, rc.status,
+								   COUNT(*) FILTER (WHERE rc.status = 'Incorrect'::status_type)
+									   OVER (PARTITION BY rc.specialist_id ORDER BY rc.datetime, rc.id) AS streak
+							FROM rated_cases rc
+							JOIN affected a ON rc.specialist_id = a.specialist_id
+							WHERE rc.status <> 'Unknown'::status_type
+						),
+						streaks AS (
+							SELECT specialist_id, COUNT(*) FILTER (WHERE status = 'Correct'::status_type) AS length,
+								   streak = MAX(streak) OVER (PARTITION BY specialist_id) AS is_current
+							FROM ordered
+							GROUP BY specialist_id, streak
+						)
+						UPDATE specialists s
+						SET row = COALESCE(st.best_row, 0),
+							current_row = COALESCE(st.last_row, 0)
+						FROM affected a
+						LEFT JOIN (
+							SELECT specialist_id, MAX(length) AS best_row, MAX(length) FILTER (WHERE is_current) AS last_row
+							FROM streaks
+							GROUP BY specialist_id
+						) st ON a.specialist_id = st.specialist_id
+						WHERE s.id = a.specialist_id;`
+
+	_, err := executor(ctx, c.db).ExecContext(ctx, updateRowsQuery, caseID)
+	if err != nil {
+		return utils.ErrNormalizer(utils.ErrorPair{Message: utils.ExecErr, Err: err})
 	}
 
 	return nil
 }
 
+func (c caseRepo) DeleteCase(ctx context.Context, caseID int) error {
+	caseDeleteQuery := `DELETE FROM cases WHERE id=$1;`
+
+	// Событие записывается до удаления, чтобы в нем остался последний уровень случая
+	return withTx(ctx, c.db, func(ctx context.Context) error {
+		err := c.events.Create(ctx, models.CaseEventCreate{CaseID: caseID, Type: models.CaseEventDeleted})
+		if err != nil {
+			return err
+		}
+
+		res, err := executor(ctx, c.db).ExecContext(ctx, caseDeleteQuery, caseID)
+		if err != nil {
+			return utils.ErrNormalizer(utils.ErrorPair{Message: utils.ExecErr, Err: err})
+		}
+
+		return checkAffectedOne(res)
+	})
+}
+
 func (c caseRepo) CreateRated(ctx context.Context, rated models.RatedBase) (int, error) {
 	var createdRatedID int
 
-	tx, err := c.db.Beginx()
-	if err != nil {
-		return 0, utils.ErrNormalizer(utils.ErrorPair{Message: utils.TransactionErr, Err: err})
-	}
+	reasonGetQuery := `SELECT is_active FROM rating_reasons WHERE code = $1;`
 
-	caseCreateQuery := `INSERT INTO rated_cases (specialist_id, case_id, choice, datetime, status)
-						VALUES ($1, $2, $3, $4, $5)
+	caseCreateQuery := `INSERT INTO rated_cases (specialist_id, case_id, choice, datetime, status, reason_code, comment)
+						VALUES ($1, $2, $3, $4, $5, $6, $7)
 						RETURNING id;`
 
-	err = tx.QueryRowxContext(ctx, caseCreateQuery,
-		rated.SpecialistID, rated.CaseID, rated.Choice, rated.Date, rated.Status).Scan(&createdRatedID)
-	if err != nil {
-		if rbErr := tx.Rollback(); rbErr != nil {
-			return 0, utils.ErrNormalizer(
-				utils.ErrorPair{Message: utils.ScanErr, Err: err},
-				utils.ErrorPair{Message: utils.RollbackErr, Err: rbErr},
-			)
+	err := withTx(ctx, c.db, func(ctx context.Context) error {
+		if rated.ReasonCode.Valid {
+			var isActive bool
+			err := executor(ctx, c.db).QueryRowxContext(ctx, reasonGetQuery, rated.ReasonCode).Scan(&isActive)
+			if err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return customErrors.NoRowsRatingReasonErr
+				}
+				return utils.ErrNormalizer(utils.ErrorPair{Message: utils.ScanErr, Err: err})
+			}
+			if !isActive {
+				return customErrors.InactiveRatingReasonErr
+			}
 		}
 
-		var pqErr *pq.Error
-		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
-			return 0, customErrors.UniqueRatedErr
-		}
+		err := executor(ctx, c.db).QueryRowxContext(ctx, caseCreateQuery, rated.SpecialistID, rated.CaseID,
+			rated.Choice, rated.Date, rated.Status, rated.ReasonCode, rated.Comment).Scan(&createdRatedID)
+		if err != nil {
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+				return customErrors.UniqueRatedErr
+			}
 
-		return 0, utils.ErrNormalizer(utils.ErrorPair{Message: utils.ScanErr, Err: err})
-	}
+			return utils.ErrNormalizer(utils.ErrorPair{Message: utils.ScanErr, Err: err})
+		}
 
-	if err = tx.Commit(); err != nil {
-		return 0, utils.ErrNormalizer(utils.ErrorPair{Message: utils.CommitErr, Err: err})
+		return c.events.Create(ctx, models.CaseEventCreate{
+			CaseID:       rated.CaseID,
+			Type:         models.CaseEventRated,
+			SpecialistID: null.IntFrom(int64(rated.SpecialistID)),
+			Choice:       null.BoolFrom(rated.Choice),
+		})
+	})
+	if err != nil {
+		return 0, err
 	}
 
 	return createdRatedID, nil
@@ -369,7 +857,7 @@ func (c caseRepo) GetNumberRatedByCaseID(ctx context.Context, caseID int) (int,
 
 	getNumberQuery := `SELECT COUNT(*) FROM rated_cases WHERE case_id=$1;`
 
-	err := c.db.QueryRowxContext(ctx, getNumberQuery, caseID).Scan(&number)
+	err := executor(ctx, c.db).QueryRowxContext(ctx, getNumberQuery, caseID).Scan(&number)
 	if err != nil {
 		return 0, utils.ErrNormalizer(utils.ErrorPair{Message: utils.ScanErr, Err: err})
 	}
@@ -377,21 +865,150 @@ func (c caseRepo) GetNumberRatedByCaseID(ctx context.Context, caseID int) (int,
 	return number, nil
 }
 
+// SkipCase сохраняет пропуск случая специалистом. Пропустить можно только случай своего уровня,
+// который еще не решен и не оценен специалистом. Пропуск снимает закрепление случая за специалистом
+func (c caseRepo) SkipCase(ctx context.Context, skip models.CaseSkip, specialistLevel int) (int, error) {
+	var createdSkipID int
+
+	caseGetQuery := `SELECT current_level, is_solved, needs_review FROM cases WHERE id = $1 FOR SHARE;`
+
+	ratedExistsQuery := `SELECT EXISTS (SELECT 1 FROM rated_cases WHERE case_id = $1 AND specialist_id = $2);`
+
+	skipCreateQuery := `INSERT INTO case_skips (case_id, specialist_id, reason)
+						VALUES ($1, $2, $3)
+						RETURNING id;`
+
+	reservationReleaseQuery := `DELETE FROM case_reservations WHERE case_id = $1 AND specialist_id = $2;`
+
+	err := withTx(ctx, c.db, func(ctx context.Context) error {
+		tx := executor(ctx, c.db)
+
+		var (
+			level                 int
+			isSolved, needsReview bool
+			rated                 bool
+		)
+		err := tx.QueryRowxContext(ctx, caseGetQuery, skip.CaseID).Scan(&level, &isSolved, &needsReview)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return customErrors.NoRowsCaseErr
+			}
+			return utils.ErrNormalizer(utils.ErrorPair{Message: utils.ScanErr, Err: err})
+		}
+		if isSolved || needsReview {
+			return customErrors.CaseAlreadySolved
+		}
+		if level != specialistLevel {
+			return customErrors.UserBadLevel
+		}
+
+		if err = tx.QueryRowxContext(ctx, ratedExistsQuery, skip.CaseID, skip.SpecialistID).Scan(&rated); err != nil {
+			return utils.ErrNormalizer(utils.ErrorPair{Message: utils.ScanErr, Err: err})
+		}
+		if rated {
+			return customErrors.UniqueRatedErr
+		}
+
+		err = tx.QueryRowxContext(ctx, skipCreateQuery, skip.CaseID, skip.SpecialistID, skip.Reason).Scan(&createdSkipID)
+		if err != nil {
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+				return customErrors.UniqueSkipErr
+			}
+			return utils.ErrNormalizer(utils.ErrorPair{Message: utils.ScanErr, Err: err})
+		}
+
+		if _, err = tx.ExecContext(ctx, reservationReleaseQuery, skip.CaseID, skip.SpecialistID); err != nil {
+			return utils.ErrNormalizer(utils.ErrorPair{Message: utils.ExecErr, Err: err})
+		}
+
+		return c.events.Create(ctx, models.CaseEventCreate{
+			CaseID:       skip.CaseID,
+			Type:         models.CaseEventSkipped,
+			SpecialistID: null.IntFrom(int64(skip.SpecialistID)),
+			Message:      null.StringFrom(skip.Reason),
+		})
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return createdSkipID, nil
+}
+
+// GetCameraSkipStats возвращает статистику пропусков по камерам, начиная с камер с наибольшей долей пропущенных случаев.
+// Пропуски по причинам считаются группировкой по reason, поэтому новые причины попадают в статистику без изменения запроса
+func (c caseRepo) GetCameraSkipStats(ctx context.Context) ([]models.CameraSkipStats, error) {
+	stats := []models.CameraSkipStats{}
+	var reasons []struct {
+		CameraID string `db:"camera_id"`
+		Reason   string `db:"reason"`
+		Skips    int    `db:"skips"`
+	}
+
+	statsGetQuery := `SELECT c.camera_id,
+							 COUNT(DISTINCT c.id) AS cases,
+							 COUNT(DISTINCT cs.case_id) AS skipped_cases,
+							 COUNT(cs.id) AS skips,
+							 COUNT(DISTINCT cs.case_id)::float8 / COUNT(DISTINCT c.id) AS skip_rate
+					  FROM cases c
+					  LEFT JOIN case_skips cs ON c.id = cs.case_id
+					  GROUP BY c.camera_id
+					  ORDER BY skip_rate DESC, skips DESC, c.camera_id;`
+
+	reasonsGetQuery := `SELECT c.camera_id, cs.reason, COUNT(*) AS skips
+						FROM case_skips cs
+						JOIN cases c ON c.id = cs.case_id
+						GROUP BY c.camera_id, cs.reason;`
+
+	err := withTx(ctx, c.db, func(ctx context.Context) error {
+		tx := executor(ctx, c.db)
+
+		if err := sqlx.SelectContext(ctx, tx, &stats, statsGetQuery); err != nil {
+			return utils.ErrNormalizer(utils.ErrorPair{Message: utils.QueryRrr, Err: err})
+		}
+
+		if err := sqlx.SelectContext(ctx, tx, &reasons, reasonsGetQuery); err != nil {
+			return utils.ErrNormalizer(utils.ErrorPair{Message: utils.QueryRrr, Err: err})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	byCamera := make(map[string]map[string]int, len(stats))
+	for i := range stats {
+		stats[i].Reasons = map[string]int{}
+		byCamera[stats[i].CameraID] = stats[i].Reasons
+	}
+	for _, reason := range reasons {
+		if cameraReasons, ok := byCamera[reason.CameraID]; ok {
+			cameraReasons[reason.Reason] = reason.Skips
+		}
+	}
+
+	return stats, nil
+}
+
 func (c caseRepo) GetRatedSolved(ctx context.Context, cursor int) (models.RatedCursor, error) {
 	var rated []models.Rated
 	var nextCursor null.Int
 	var casesWithCursor models.RatedCursor
 
 	casesGetQueue := `SELECT DISTINCT ON (rc.case_id) rc.id, rc.specialist_id, rc.case_id, rc.choice, rc.datetime, rc.status,
+					  rc.reason_code, rr.description, rc.comment,
 					  c.camera_id, c.violation_value, v.type, v.amount, c.level, c.photo_url
 					  FROM rated_cases rc
 					  LEFT JOIN cases c ON rc.case_id = c.id
 					  LEFT JOIN violations v ON c.violation_id = v.id
+					  LEFT JOIN rating_reasons rr ON rc.reason_code = rr.code
 					  WHERE status != 'Unknown' AND rc.id >= $1 AND c.is_solved = true
 					  ORDER BY case_id, id
 					  LIMIT $2;`
 
-	rows, err := c.db.QueryxContext(ctx, casesGetQueue, cursor, c.casesPerRequest+1)
+	rows, err := executor(ctx, c.db).QueryxContext(ctx, casesGetQueue, cursor, c.casesPerRequest+1)
 	if err != nil {
 		return models.RatedCursor{}, utils.ErrNormalizer(utils.ErrorPair{Message: utils.ScanErr, Err: err})
 	}
@@ -400,7 +1017,8 @@ func (c caseRepo) GetRatedSolved(ctx context.Context, cursor int) (models.RatedC
 	for rows.Next() {
 		var rt models.Rated
 
-		err := rows.Scan(&rt.ID, &rt.SpecialistID, &rt.CaseID, &rt.Choice, &rt.Date, &rt.Status, &rt.CameraID,
+		err := rows.Scan(&rt.ID, &rt.SpecialistID, &rt.CaseID, &rt.Choice, &rt.Date, &rt.Status,
+			&rt.ReasonCode, &rt.Reason, &rt.Comment, &rt.CameraID,
 			&rt.ViolationValue, &rt.Violation.Type, &rt.Violation.Amount, &rt.Level, &rt.PhotoUrl)
 		if err != nil {
 			return models.RatedCursor{}, utils.ErrNormalizer(utils.ErrorPair{Message: utils.ScanErr, Err: err})
@@ -424,16 +1042,18 @@ func (c caseRepo) GetFulCaseByID(ctx context.Context, caseID int) (models.CaseFu
 	var caseFul models.CaseFul
 	var ratedNum int
 
-	caseGetQuery := `SELECT c.camera_id, c.transport, v.id, v.type, v.amount, c.violation_value, c.level, c.current_level, c.datetime, c.photo_url, c.is_solved, COUNT(*)
+	caseGetQuery := `SELECT c.camera_id, c.transport, v.id, v.type, v.amount, c.violation_value, c.level, c.current_level, c.datetime, c.photo_url, c.is_solved,
+					 c.control_choice, COUNT(rc.id)
 					 FROM cases c
 					 LEFT JOIN violations v ON c.violation_id = v.id
 					 LEFT JOIN rated_cases rc ON c.id = rc.case_id
 					 WHERE c.id = $1
-					 GROUP BY c.camera_id, c.transport, v.id, v.type, v.amount, c.violation_value, c.level, c.current_level, c.datetime, c.photo_url, c.is_solved`
+					 GROUP BY c.camera_id, c.transport, v.id, v.type, v.amount, c.violation_value, c.level, c.current_level, c.datetime, c.photo_url, c.is_solved,
+					 c.control_choice`
 
-	err := c.db.QueryRowxContext(ctx, caseGetQuery, caseID).Scan(&caseFul.CameraID, &caseFul.Transport, &caseFul.ViolationID, &caseFul.Violation.Type,
+	err := executor(ctx, c.db).QueryRowxContext(ctx, caseGetQuery, caseID).Scan(&caseFul.CameraID, &caseFul.Transport, &caseFul.ViolationID, &caseFul.Violation.Type,
 		&caseFul.Violation.Amount, &caseFul.ViolationValue,
-		&caseFul.Level, &caseFul.CurrentLevel, &caseFul.Datetime, &caseFul.PhotoUrl, &caseFul.IsSolved, &ratedNum)
+		&caseFul.Level, &caseFul.CurrentLevel, &caseFul.Datetime, &caseFul.PhotoUrl, &caseFul.IsSolved, &caseFul.Control, &ratedNum)
 	if err != nil {
 		switch {
 		case errors.Is(err, sql.ErrNoRows):
@@ -443,13 +1063,19 @@ func (c caseRepo) GetFulCaseByID(ctx context.Context, caseID int) (models.CaseFu
 		}
 	}
 
-	if ratedNum > 1 {
-		ratedGetQuery := `SELECT rc.id, rc.status, rc.datetime, s.id, s.fullname, s.level, s.photo_url
+	if ratedNum > 0 {
+		ratedGetQuery := `SELECT rc.id, rc.choice, rc.status, rc.datetime, rc.reason_code, rr.description, rc.comment,
+						  s.id, s.fullname, s.level, s.photo_url
 					  	  FROM rated_cases rc
 					  	  LEFT JOIN specialists s ON rc.specialist_id = s.id
-					  	  WHERE rc.case_id = $1`
+					  	  LEFT JOIN rating_reasons rr ON rc.reason_code = rr.code
+					  	  WHERE rc.case_id = $1
+					  	  ORDER BY rc.id`
 
-		rows, err := c.db.QueryxContext(ctx, ratedGetQuery, caseID)
+		rows, err := executor(ctx, c.db).QueryxContext(ctx, ratedGetQuery, caseID)
+		if err != nil {
+			return models.CaseFul{}, utils.ErrNormalizer(utils.ErrorPair{Message: utils.QueryRrr, Err: err})
+		}
 		defer rows.Close()
 
 		var ratedCovers []models.RatedCover
@@ -457,8 +1083,8 @@ func (c caseRepo) GetFulCaseByID(ctx context.Context, caseID int) (models.CaseFu
 		for rows.Next() {
 			var ratedCover models.RatedCover
 
-			err := rows.Scan(&ratedCover.ID, &ratedCover.Status, &ratedCover.Date,
-				&ratedCover.SpecialistCover.ID, &ratedCover.SpecialistCover.Fullname, &ratedCover.SpecialistCover.Level, &ratedCover.SpecialistCover.PhotoUrl)
+			err := rows.Scan(&ratedCover.ID, &ratedCover.Choice, &ratedCover.Status, &ratedCover.Date,
+				&ratedCover.ReasonCode, &ratedCover.Reason, &ratedCover.Comment, &ratedCover.SpecialistCover.ID, &ratedCover.SpecialistCover.Fullname, &ratedCover.SpecialistCover.Level, &ratedCover.SpecialistCover.PhotoUrl)
 			if err != nil {
 				return models.CaseFul{}, utils.ErrNormalizer(utils.ErrorPair{Message: utils.ScanErr, Err: err})
 			}
@@ -476,6 +1102,22 @@ func (c caseRepo) GetFulCaseByID(ctx context.Context, caseID int) (models.CaseFu
 		caseFul.RatedCovers = nil
 	}
 
+	overridesGetQuery := `SELECT id, case_id, manager_id, action, previous_choice, choice, justification, datetime
+						  FROM case_overrides
+						  WHERE case_id = $1
+						  ORDER BY id;`
+
+	caseFul.Overrides = []models.CaseOverride{}
+	err = sqlx.SelectContext(ctx, executor(ctx, c.db), &caseFul.Overrides, overridesGetQuery, caseID)
+	if err != nil {
+		return models.CaseFul{}, utils.ErrNormalizer(utils.ErrorPair{Message: utils.QueryRrr, Err: err})
+	}
+
+	caseFul.Timeline, err = c.events.GetByCaseID(ctx, caseID)
+	if err != nil {
+		return models.CaseFul{}, err
+	}
+
 	return caseFul, nil
 
 }
@@ -1,11 +1,14 @@
 package repository
 
 import (
+	"context"
+	"database/sql"
 	"encoding/json"
-	"fmt"
+	"errors"
 	"github.com/gl1n0m3c/IT_LAB_INIT/internal/models"
+	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/utils"
+	customErrors "github.com/gl1n0m3c/IT_LAB_INIT/pkg/utils/customerr"
 	"github.com/jmoiron/sqlx"
-	"github.com/lib/pq"
 )
 
 type contactRepo struct {
@@ -16,66 +19,125 @@ func InitContactRepo(db *sqlx.DB) Contacts {
 	return contactRepo{db: db}
 }
 
+// Create добавляет контакты одной транзакцией и возвращает количество добавленных.
+// Контакты номеров, которые уже есть в базе, пропускаются
 func (c contactRepo) Create(contacts []models.Contact) (int, error) {
 	var accepted int
 
-	tx, err := c.db.Beginx()
-	if err != nil {
-		return 0, err
-	}
-	defer tx.Rollback()
-
 	contactsQueue := `INSERT INTO contacts (transport, contacts)
-					  VALUES ($1, $2)`
+					  VALUES ($1, $2)
+					  ON CONFLICT (transport) DO NOTHING;`
 
-	for i, contact := range contacts {
-		savepoint := fmt.Sprintf("sp_%d", i)
-		if _, err := tx.Exec("SAVEPOINT " + savepoint); err != nil {
-			return 0, err
-		}
+	ctx := context.Background()
 
-		userContactsJSON, err := json.Marshal(contact.UserContacts)
-		if err != nil {
-			return 0, err
-		}
+	err := withTx(ctx, c.db, func(ctx context.Context) error {
+		tx := executor(ctx, c.db)
 
-		res, err := tx.Exec(contactsQueue, contact.Transport, userContactsJSON)
-		if err != nil {
-			if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
-				_, rbErr := tx.Exec("ROLLBACK TO SAVEPOINT " + savepoint)
-				if rbErr != nil {
-					return 0, rbErr
-				}
-				continue
+		for _, contact := range contacts {
+			userContactsJSON, err := json.Marshal(contact.UserContacts)
+			if err != nil {
+				return err
 			}
 
-			if rbErr := tx.Rollback(); rbErr != nil {
-				return 0, fmt.Errorf("error: %v", err.Error()+rbErr.Error())
+			res, err := tx.ExecContext(ctx, contactsQueue, contact.Transport, userContactsJSON)
+			if err != nil {
+				return utils.ErrNormalizer(utils.ErrorPair{Message: utils.ExecErr, Err: err})
 			}
-			return 0, err
-		}
 
-		count, err := res.RowsAffected()
-		if err != nil {
-			if rbErr := tx.Rollback(); rbErr != nil {
-				return 0, fmt.Errorf("error: %v", err.Error()+rbErr.Error())
+			count, err := res.RowsAffected()
+			if err != nil {
+				return utils.ErrNormalizer(utils.ErrorPair{Message: utils.RowsErr, Err: err})
 			}
-			return 0, err
+
+			accepted += int(count)
 		}
 
-		if count == 0 {
-			if rbErr := tx.Rollback(); rbErr != nil {
-				return 0, fmt.Errorf("error: %v", err.Error()+rbErr.Error())
-			}
-			return 0, fmt.Errorf("no rows were inserted")
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return accepted, nil
+}
+
+func (c contactRepo) Get(ctx context.Context, transport string) (models.Contact, error) {
+	var contact models.Contact
+	var userContactsJSON []byte
+
+	contactGetQuery := `SELECT transport, contacts
+						FROM contacts
+						WHERE transport=$1;`
+
+	err := executor(ctx, c.db).QueryRowxContext(ctx, contactGetQuery, transport).Scan(&contact.Transport, &userContactsJSON)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return models.Contact{}, customErrors.NoRowsContactErr
+		default:
+			return models.Contact{}, utils.ErrNormalizer(utils.ErrorPair{Message: utils.ScanErr, Err: err})
 		}
+	}
 
-		accepted++
+	if err = json.Unmarshal(userContactsJSON, &contact.UserContacts); err != nil {
+		return models.Contact{}, err
 	}
 
-	if err = tx.Commit(); err != nil {
-		return 0, err
+	return contact, nil
+}
+
+// GetTransports возвращает все номера транспорта, которые хранятся в контактах, случаях и неразобранных случаях
+func (c contactRepo) GetTransports(ctx context.Context) ([]string, error) {
+	var transports []string
+
+	transportsGetQuery := `SELECT transport FROM contacts
+						   UNION
+						   SELECT transport FROM cases
+						   UNION
+						   SELECT transport FROM unmatched_cases
+						   ORDER BY transport;`
+
+	err := sqlx.SelectContext(ctx, executor(ctx, c.db), &transports, transportsGetQuery)
+	if err != nil {
+		return nil, utils.ErrNormalizer(utils.ErrorPair{Message: utils.QueryRrr, Err: err})
 	}
 
-	return accepted, nil
+	return transports, nil
+}
+
+// RenameTransport заменяет номер транспорта from на to в контактах, случаях и неразобранных случаях одной транзакцией.
+// Если контакты для to уже есть, контакты from дописываются к ним без перезаписи существующих ключей
+func (c contactRepo) RenameTransport(ctx context.Context, from, to string) error {
+	// Сначала создается запись с новым номером, чтобы на нее можно было перевесить случаи
+	contactCopyQuery := `INSERT INTO contacts (transport, contacts)
+						 SELECT $2, contacts FROM contacts WHERE transport = $1
+						 ON CONFLICT (transport) DO UPDATE SET contacts = EXCLUDED.contacts || contacts.contacts;`
+
+	casesUpdateQuery := `UPDATE cases SET transport = $2 WHERE transport = $1;`
+
+	unmatchedUpdateQuery := `UPDATE unmatched_cases SET transport = $2 WHERE transport = $1;`
+
+	contactDeleteQuery := `DELETE FROM contacts WHERE transport = $1;`
+
+	return withTx(ctx, c.db, func(ctx context.Context) error {
+		tx := executor(ctx, c.db)
+
+		queries := []struct {
+			query string
+			args  []any
+		}{
+			{query: contactCopyQuery, args: []any{from, to}},
+			{query: casesUpdateQuery, args: []any{from, to}},
+			{query: unmatchedUpdateQuery, args: []any{from, to}},
+			{query: contactDeleteQuery, args: []any{from}},
+		}
+
+		for _, q := range queries {
+			if _, err := tx.ExecContext(ctx, q.query, q.args...); err != nil {
+				return utils.ErrNormalizer(utils.ErrorPair{Message: utils.ExecErr, Err: err})
+			}
+		}
+
+		return nil
+	})
 }
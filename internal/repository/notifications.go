@@ -0,0 +1,287 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/models"
+	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/config"
+	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/utils"
+	customErrors "github.com/gl1n0m3c/IT_LAB_INIT/pkg/utils/customerr"
+	"github.com/guregu/null"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/spf13/viper"
+	"time"
+)
+
+type notificationRepo struct {
+	db                      *sqlx.DB
+	events                  CaseEvents
+	notificationsPerRequest int
+}
+
+func InitNotificationRepo(
+	db *sqlx.DB,
+) Notifications {
+	return notificationRepo{
+		db:                      db,
+		events:                  InitCaseEventRepo(db),
+		notificationsPerRequest: viper.GetInt(config.EntitiesPerRequest),
+	}
+}
+
+// Enqueue ставит уведомление kind по случаю в очередь отправки. Вызывается в транзакции решения по случаю,
+// поэтому уведомление появляется тогда и только тогда, когда решение зафиксировано.
+// Ключ идемпотентности включает число пересмотров случая: по одному решению уведомление ставится один раз,
+// а после пересмотра - заново
+func (n notificationRepo) Enqueue(ctx context.Context, caseID int, kind string) error {
+	enqueueQuery := `INSERT INTO notifications (case_id, kind, idempotency_key)
+					 VALUES ($1, $2, CONCAT_WS(':', $2::text, $1::int, (SELECT COUNT(*) FROM case_overrides WHERE case_id = $1)))
+					 ON CONFLICT (idempotency_key) DO NOTHING;`
+
+	_, err := executor(ctx, n.db).ExecContext(ctx, enqueueQuery, caseID, kind)
+	if err != nil {
+		return utils.ErrNormalizer(utils.ErrorPair{Message: utils.ExecErr, Err: err})
+	}
+
+	return nil
+}
+
+// CancelUnsent отменяет уведомления случая, которые еще не отправлены, в том числе исчерпавшие попытки.
+// Вызывается в транзакции пересмотра решения: уведомления о прежнем решении отправлять уже не нужно
+func (n notificationRepo) CancelUnsent(ctx context.Context, caseID int) error {
+	cancelQuery := `UPDATE notifications
+					SET status = 'cancelled'
+					WHERE case_id = $1 AND status IN ('pending', 'dead');`
+
+	_, err := executor(ctx, n.db).ExecContext(ctx, cancelQuery, caseID)
+	if err != nil {
+		return utils.ErrNormalizer(utils.ErrorPair{Message: utils.ExecErr, Err: err})
+	}
+
+	return nil
+}
+
+// GetLastSentKind возвращает вид последнего отправленного по случаю уведомления,
+// то есть решение, о котором нарушитель знает. Если уведомлений не отправлялось, значение пустое
+func (n notificationRepo) GetLastSentKind(ctx context.Context, caseID int) (null.String, error) {
+	var kind null.String
+
+	lastSentQuery := `SELECT kind FROM notifications
+					  WHERE case_id = $1 AND status = 'sent'
+					  ORDER BY sent_at DESC, id DESC
+					  LIMIT 1;`
+
+	err := executor(ctx, n.db).QueryRowxContext(ctx, lastSentQuery, caseID).Scan(&kind)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return null.String{}, utils.ErrNormalizer(utils.ErrorPair{Message: utils.ScanErr, Err: err})
+	}
+
+	return kind, nil
+}
+
+// ClaimDue выбирает до limit уведомлений, срок отправки которых наступил, и засчитывает им попытку.
+// Следующая попытка откладывается на lease, чтобы другой диспетчер не отправил уведомление,
+// пока текущий его отправляет. Уведомления, заблокированные параллельными вызовами, пропускаются
+func (n notificationRepo) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]models.Notification, error) {
+	notifications := []models.Notification{}
+
+	claimQuery := `UPDATE notifications
+				   SET attempts = attempts + 1, next_attempt_at = NOW() + make_interval(secs => $2)
+				   WHERE id IN (
+					   SELECT id FROM notifications
+					   WHERE status = 'pending' AND next_attempt_at <= NOW()
+					   ORDER BY next_attempt_at, id
+					   LIMIT $1
+					   FOR UPDATE SKIP LOCKED
+				   )
+				   RETURNING id, case_id, kind, idempotency_key, status, attempts, next_attempt_at, last_error, created_at, sent_at;`
+
+	err := sqlx.SelectContext(ctx, executor(ctx, n.db), &notifications, claimQuery, limit, lease.Seconds())
+	if err != nil {
+		return nil, utils.ErrNormalizer(utils.ErrorPair{Message: utils.QueryRrr, Err: err})
+	}
+
+	return notifications, nil
+}
+
+// MarkSent отмечает уведомление отправленным и записывает отправку в историю случая
+func (n notificationRepo) MarkSent(ctx context.Context, notification models.Notification) error {
+	markSentQuery := `UPDATE notifications
+					  SET status = 'sent', sent_at = NOW(), last_error = NULL
+					  WHERE id = $1 AND status = 'pending';`
+
+	return withTx(ctx, n.db, func(ctx context.Context) error {
+		res, err := executor(ctx, n.db).ExecContext(ctx, markSentQuery, notification.ID)
+		if err != nil {
+			return utils.ErrNormalizer(utils.ErrorPair{Message: utils.ExecErr, Err: err})
+		}
+		if err = checkAffectedOne(res); err != nil {
+			return err
+		}
+
+		return n.events.Create(ctx, models.CaseEventCreate{
+			CaseID:  notification.CaseID,
+			Type:    models.CaseEventNotified,
+			Message: null.StringFrom(notification.Kind),
+		})
+	})
+}
+
+// Release возвращает в очередь уведомления, закрепленные ClaimDue, но не отправленные: они становятся доступны
+// для отправки сразу, а попытка не засчитывается
+func (n notificationRepo) Release(ctx context.Context, notificationIDs []int) error {
+	releaseQuery := `UPDATE notifications
+					 SET attempts = attempts - 1, next_attempt_at = NOW()
+					 WHERE id = ANY($1) AND status = 'pending';`
+
+	_, err := executor(ctx, n.db).ExecContext(ctx, releaseQuery, pq.Array(notificationIDs))
+	if err != nil {
+		return utils.ErrNormalizer(utils.ErrorPair{Message: utils.ExecErr, Err: err})
+	}
+
+	return nil
+}
+
+// MarkFailed сохраняет ошибку попытки и назначает следующую на nextAttemptAt, при dead уведомление
+// больше не отправляется
+func (n notificationRepo) MarkFailed(ctx context.Context, notificationID int, sendErr string, nextAttemptAt time.Time, dead bool) error {
+	markFailedQuery := `UPDATE notifications
+						SET last_error = $2, next_attempt_at = $3,
+							status = CASE WHEN $4 THEN 'dead'::notification_status ELSE status END
+						WHERE id = $1 AND status = 'pending';`
+
+	res, err := executor(ctx, n.db).ExecContext(ctx, markFailedQuery, notificationID, sendErr, nextAttemptAt, dead)
+	if err != nil {
+		return utils.ErrNormalizer(utils.ErrorPair{Message: utils.ExecErr, Err: err})
+	}
+
+	return checkAffectedOne(res)
+}
+
+// AddDeliveries записывает результаты доставки уведомления по каналам
+func (n notificationRepo) AddDeliveries(ctx context.Context, deliveries []models.NotificationDelivery) error {
+	deliveryCreateQuery := `INSERT INTO notification_deliveries (notification_id, channel, recipient, delivered, error)
+							VALUES (:notification_id, :channel, :recipient, :delivered, :error);`
+
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	_, err := sqlx.NamedExecContext(ctx, executor(ctx, n.db), deliveryCreateQuery, deliveries)
+	if err != nil {
+		return utils.ErrNormalizer(utils.ErrorPair{Message: utils.ExecErr, Err: err})
+	}
+
+	return nil
+}
+
+// attachDeliveries дополняет уведомления результатами доставки по каналам
+func (n notificationRepo) attachDeliveries(ctx context.Context, notifications []models.Notification) error {
+	var deliveries []models.NotificationDelivery
+
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(notifications))
+	for i, notification := range notifications {
+		ids[i] = int64(notification.ID)
+	}
+
+	deliveriesGetQuery := `SELECT id, notification_id, channel, recipient, delivered, error, created_at
+						   FROM notification_deliveries
+						   WHERE notification_id = ANY($1)
+						   ORDER BY id;`
+
+	err := sqlx.SelectContext(ctx, executor(ctx, n.db), &deliveries, deliveriesGetQuery, pq.Array(ids))
+	if err != nil {
+		return utils.ErrNormalizer(utils.ErrorPair{Message: utils.QueryRrr, Err: err})
+	}
+
+	byNotification := make(map[int][]models.NotificationDelivery, len(notifications))
+	for _, delivery := range deliveries {
+		byNotification[delivery.NotificationID] = append(byNotification[delivery.NotificationID], delivery)
+	}
+	for i := range notifications {
+		notifications[i].Deliveries = byNotification[notifications[i].ID]
+		if notifications[i].Deliveries == nil {
+			notifications[i].Deliveries = []models.NotificationDelivery{}
+		}
+	}
+
+	return nil
+}
+
+// GetDead возвращает уведомления, которые не удалось отправить за все попытки
+func (n notificationRepo) GetDead(ctx context.Context, cursor int) (models.NotificationCursor, error) {
+	var (
+		notifications []models.Notification
+		nextCursor    null.Int
+	)
+
+	deadGetQuery := `SELECT id, case_id, kind, idempotency_key, status, attempts, next_attempt_at, last_error, created_at, sent_at
+					 FROM notifications
+					 WHERE status = 'dead' AND id >= $1
+					 ORDER BY id LIMIT $2;`
+
+	err := sqlx.SelectContext(ctx, executor(ctx, n.db), &notifications, deadGetQuery, cursor, n.notificationsPerRequest+1)
+	if err != nil {
+		return models.NotificationCursor{}, utils.ErrNormalizer(utils.ErrorPair{Message: utils.QueryRrr, Err: err})
+	}
+
+	if len(notifications) == n.notificationsPerRequest+1 {
+		nextCursor = null.IntFrom(int64(notifications[len(notifications)-1].ID))
+		notifications = notifications[:len(notifications)-1]
+	}
+
+	if err = n.attachDeliveries(ctx, notifications); err != nil {
+		return models.NotificationCursor{}, err
+	}
+
+	return models.NotificationCursor{Notifications: notifications, Cursor: nextCursor}, nil
+}
+
+// Retry возвращает неотправленное уведомление в очередь с новым счетчиком попыток
+func (n notificationRepo) Retry(ctx context.Context, notificationID int) error {
+	retryQuery := `UPDATE notifications
+				   SET status = 'pending', attempts = 0, next_attempt_at = NOW()
+				   WHERE id = $1 AND status = 'dead';`
+
+	res, err := executor(ctx, n.db).ExecContext(ctx, retryQuery, notificationID)
+	if err != nil {
+		return utils.ErrNormalizer(utils.ErrorPair{Message: utils.ExecErr, Err: err})
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return utils.ErrNormalizer(utils.ErrorPair{Message: utils.RowsErr, Err: err})
+	}
+	if count == 0 {
+		return customErrors.NoRowsNotificationErr
+	}
+
+	return nil
+}
+
+// GetByCaseID возвращает уведомления случая с результатами доставки в порядке создания
+func (n notificationRepo) GetByCaseID(ctx context.Context, caseID int) ([]models.Notification, error) {
+	notifications := []models.Notification{}
+
+	notificationsGetQuery := `SELECT id, case_id, kind, idempotency_key, status, attempts, next_attempt_at, last_error, created_at, sent_at
+							  FROM notifications
+							  WHERE case_id = $1
+							  ORDER BY id;`
+
+	err := sqlx.SelectContext(ctx, executor(ctx, n.db), &notifications, notificationsGetQuery, caseID)
+	if err != nil {
+		return nil, utils.ErrNormalizer(utils.ErrorPair{Message: utils.QueryRrr, Err: err})
+	}
+
+	if err = n.attachDeliveries(ctx, notifications); err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
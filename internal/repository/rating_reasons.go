@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/models"
+	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/utils"
+	customErrors "github.com/gl1n0m3c/IT_LAB_INIT/pkg/utils/customerr"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type ratingReasonRepo struct {
+	db *sqlx.DB
+}
+
+func InitRatingReasonRepo(
+	db *sqlx.DB,
+) RatingReasons {
+	return ratingReasonRepo{
+		db: db,
+	}
+}
+
+func (r ratingReasonRepo) Create(ctx context.Context, reason models.RatingReasonCreate) error {
+	reasonCreateQuery := `INSERT INTO rating_reasons (code, description)
+						  VALUES ($1, $2);`
+
+	_, err := executor(ctx, r.db).ExecContext(ctx, reasonCreateQuery, reason.Code, reason.Description)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return customErrors.UniqueRatingReasonErr
+		}
+		return utils.ErrNormalizer(utils.ErrorPair{Message: utils.ExecErr, Err: err})
+	}
+
+	return nil
+}
+
+// GetAll возвращает справочник причин оценки, при onlyActive - только причины, доступные для новых оценок
+func (r ratingReasonRepo) GetAll(ctx context.Context, onlyActive bool) ([]models.RatingReason, error) {
+	reasons := []models.RatingReason{}
+
+	reasonsGetQuery := `SELECT code, description, is_active
+						FROM rating_reasons
+						WHERE is_active OR NOT $1
+						ORDER BY code;`
+
+	err := sqlx.SelectContext(ctx, executor(ctx, r.db), &reasons, reasonsGetQuery, onlyActive)
+	if err != nil {
+		return nil, utils.ErrNormalizer(utils.ErrorPair{Message: utils.QueryRrr, Err: err})
+	}
+
+	return reasons, nil
+}
+
+func (r ratingReasonRepo) Update(ctx context.Context, reason models.RatingReasonUpdate) error {
+	reasonUpdateQuery := `UPDATE rating_reasons
+						  SET description = COALESCE($2, description), is_active = COALESCE($3, is_active)
+						  WHERE code = $1;`
+
+	res, err := executor(ctx, r.db).ExecContext(ctx, reasonUpdateQuery, reason.Code, reason.Description, reason.IsActive)
+	if err != nil {
+		return utils.ErrNormalizer(utils.ErrorPair{Message: utils.ExecErr, Err: err})
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return utils.ErrNormalizer(utils.ErrorPair{Message: utils.RowsErr, Err: err})
+	}
+	if count == 0 {
+		return customErrors.NoRowsRatingReasonErr
+	}
+
+	return nil
+}
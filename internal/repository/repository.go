@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"github.com/gl1n0m3c/IT_LAB_INIT/internal/models"
+	"github.com/guregu/null"
 	"time"
 )
 
@@ -31,24 +32,73 @@ type Cameras interface {
 
 type Cases interface {
 	CreateCase(ctx context.Context, caseData models.CaseBase) (int, error)
+	CreateCases(ctx context.Context, cases []models.CaseBase) ([]int, []error, error)
+	CreateControlCase(ctx context.Context, caseData models.CaseBase, choice bool) (int, error)
 	UpdateCaseLevel(ctx context.Context, caseID, level int) error
 	UpdateCaseSetSolved(ctx context.Context, caseID int, rightChoice bool) error
 	GetFineData(ctx context.Context, caseID int) (models.FineData, error)
-	GetCaseLevelSolvedRatingsTrueByID(ctx context.Context, caseID int) (int, int, int, bool, error)
+	GetCaseConsensus(ctx context.Context, caseID int) (models.CaseConsensus, error)
 	GetCasesByLevel(ctx context.Context, specialistID, level, cursor int) (models.CaseCursor, error)
+	ReserveNextCase(ctx context.Context, specialistID, level int, ttl time.Duration) (models.CaseReservation, error)
+	GetReviewCases(ctx context.Context, cursor int) (models.CaseCursor, error)
+	GetCaseVotes(ctx context.Context, caseID int) ([]models.CaseVoteFul, error)
+	ResolveReviewCase(ctx context.Context, managerID int, decision models.CaseReviewDecision) error
+	OverrideCase(ctx context.Context, managerID int, override models.CaseOverrideCreate, maxLevel int) (models.CaseOverride, error)
 	DeleteCase(ctx context.Context, caseID int) error
 
 	CreateRated(ctx context.Context, rated models.RatedBase) (int, error)
+	CreateRatedConsensus(ctx context.Context, rated models.RatedBase, specialistLevel, defaultK, maxLevel int) (models.RatedResolution, error)
+	SkipCase(ctx context.Context, skip models.CaseSkip, specialistLevel int) (int, error)
+	GetCameraSkipStats(ctx context.Context) ([]models.CameraSkipStats, error)
 	GetRatedSolved(ctx context.Context, cursor int) (models.RatedCursor, error)
 	GetNumberRatedByCaseID(ctx context.Context, caseID int) (int, error)
 
 	GetFulCaseByID(ctx context.Context, caseID int) (models.CaseFul, error)
 }
 
+// Notifications - очередь уведомлений нарушителям, которую разбирает фоновый диспетчер
+type Notifications interface {
+	Enqueue(ctx context.Context, caseID int, kind string) error
+	CancelUnsent(ctx context.Context, caseID int) error
+	GetLastSentKind(ctx context.Context, caseID int) (null.String, error)
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]models.Notification, error)
+	Release(ctx context.Context, notificationIDs []int) error
+	MarkSent(ctx context.Context, notification models.Notification) error
+	MarkFailed(ctx context.Context, notificationID int, sendErr string, nextAttemptAt time.Time, dead bool) error
+	AddDeliveries(ctx context.Context, deliveries []models.NotificationDelivery) error
+	GetDead(ctx context.Context, cursor int) (models.NotificationCursor, error)
+	Retry(ctx context.Context, notificationID int) error
+	GetByCaseID(ctx context.Context, caseID int) ([]models.Notification, error)
+}
+
+// RatingReasons - справочник причин оценки, который ведут руководители
+type RatingReasons interface {
+	Create(ctx context.Context, reason models.RatingReasonCreate) error
+	GetAll(ctx context.Context, onlyActive bool) ([]models.RatingReason, error)
+	Update(ctx context.Context, reason models.RatingReasonUpdate) error
+}
+
+// CaseEvents хранит историю случаев: создание, оценки, передачу на уровни, решения и уведомления
+type CaseEvents interface {
+	Create(ctx context.Context, event models.CaseEventCreate) error
+	GetByCaseID(ctx context.Context, caseID int) ([]models.CaseEvent, error)
+}
+
+// UnmatchedCases хранит случаи, для транспорта которых нет контактов владельца, до их привязки менеджером
+type UnmatchedCases interface {
+	Create(ctx context.Context, caseData models.CaseBase) (int, error)
+	GetAll(ctx context.Context, cursor int) (models.UnmatchedCaseCursor, error)
+	Promote(ctx context.Context, resolve models.UnmatchedCaseResolve) (int, error)
+}
+
 type Violations interface {
 	Create(violations []models.Violation) (int, error)
+	Get(ctx context.Context, violationID string) (models.Violation, error)
 }
 
 type Contacts interface {
 	Create(contacts []models.Contact) (int, error)
+	Get(ctx context.Context, transport string) (models.Contact, error)
+	GetTransports(ctx context.Context) ([]string, error)
+	RenameTransport(ctx context.Context, from, to string) error
 }
@@ -113,12 +113,17 @@ func (s specialistsRepo) GetSpecialistRating(ctx context.Context, timeStart, tim
 		nextCursor        null.Int
 	)
 
+	// Оценки контрольных случаев не сравниваются с консенсусом и считаются отдельно, по известному ответу
 	getRatingQuery := `SELECT s.id, s.fullname, s.level, s.photo_url, row,
-					          COUNT(rc.id) AS total_cases,
-					          COUNT(CASE WHEN rc.status = 'Correct' THEN 1 END) AS correct_cases,
-					          COUNT(CASE WHEN rc.status = 'Unknown' THEN 1 END) AS unknown_cases
+					          COUNT(rc.id) FILTER (WHERE c.control_choice IS NULL) AS total_cases,
+					          COUNT(CASE WHEN rc.status = 'Correct' AND c.control_choice IS NULL THEN 1 END) AS correct_cases,
+					          COUNT(CASE WHEN rc.status = 'Unknown' AND c.control_choice IS NULL THEN 1 END) AS unknown_cases,
+					          COUNT(c.control_choice) AS control_cases,
+					          COUNT(CASE WHEN rc.choice = c.control_choice THEN 1 END) AS control_correct_cases,
+					          COUNT(CASE WHEN rc.choice = c.control_choice THEN 1 END) * 1.0 / NULLIF(COUNT(c.control_choice), 0) AS accuracy
 					   FROM specialists s
-					   LEFT JOIN rated_cases rc ON s.id = rc.specialist_id AND rc.datetime BETWEEN $1 AND $2
+					   LEFT JOIN (rated_cases rc JOIN cases c ON rc.case_id = c.id)
+					   	   ON s.id = rc.specialist_id AND rc.datetime BETWEEN $1 AND $2
 					   WHERE s.id >= $3
 					   GROUP BY s.id, s.login, s.fullname, s.level, s.photo_url, s.is_verified
 					   LIMIT $4;`
@@ -132,7 +137,8 @@ func (s specialistsRepo) GetSpecialistRating(ctx context.Context, timeStart, tim
 		var ratingSpecialist models.RatingSpecialistCount
 
 		err := rows.Scan(&ratingSpecialist.ID, &ratingSpecialist.Fullname, &ratingSpecialist.Level, &ratingSpecialist.PhotoUrl, &ratingSpecialist.Row,
-			&ratingSpecialist.Total, &ratingSpecialist.Correct, &ratingSpecialist.Unknown)
+			&ratingSpecialist.Total, &ratingSpecialist.Correct, &ratingSpecialist.Unknown,
+			&ratingSpecialist.ControlTotal, &ratingSpecialist.ControlCorrect, &ratingSpecialist.Accuracy)
 		if err != nil {
 			return models.RatingSpecialistCountCursor{}, utils.ErrNormalizer(utils.ErrorPair{Message: utils.QueryRrr, Err: err})
 		}
@@ -158,6 +164,7 @@ func (s specialistsRepo) GetFulRating(ctx context.Context) ([]models.RatingSpeci
 					       COUNT(CASE WHEN rc.status = 'Correct' THEN 1 END) * 1.0 / NULLIF(COUNT(rc.id), 0) AS rating
 					   FROM specialists s
 					   LEFT JOIN rated_cases rc ON s.id = rc.specialist_id
+					   AND NOT EXISTS (SELECT 1 FROM cases c WHERE c.id = rc.case_id AND c.control_choice IS NOT NULL)
 					   GROUP BY s.id, s.level, s.fullname
 					   ORDER BY rating DESC, COUNT(CASE WHEN rc.status = 'Correct' THEN 1 END);`
 
@@ -191,6 +198,7 @@ func (s specialistsRepo) GetOnlyRating(timeStart, timeEnd time.Time) ([]models.R
 	getRatingQuery := `SELECT s.id, s.level, COUNT(CASE WHEN rc.status = 'Correct' THEN 1 END) * 1.0 / COUNT(rc.id) AS rating
 					   FROM specialists s
 					   LEFT JOIN rated_cases rc ON s.id = rc.specialist_id AND rc.datetime BETWEEN $1 AND $2
+					   AND NOT EXISTS (SELECT 1 FROM cases c WHERE c.id = rc.case_id AND c.control_choice IS NOT NULL)
 					   GROUP BY s.id, s.level
 					   HAVING COUNT(rc.id) >= $3
 					   ORDER BY COUNT(CASE WHEN rc.status = 'Correct' THEN 1 END) * 1.0 / COUNT(rc.id) DESC, COUNT(CASE WHEN rc.status = 'Correct' THEN 1 END);`
@@ -220,65 +228,37 @@ func (s specialistsRepo) GetOnlyRating(timeStart, timeEnd time.Time) ([]models.R
 }
 
 func (s specialistsRepo) UpdateSpecialistsIncDecLevel(incrementIDs, decrementIDs []int) error {
-	tx, err := s.db.Beginx()
-	if err != nil {
-		return utils.ErrNormalizer(utils.ErrorPair{Message: utils.TransactionErr, Err: err})
-	}
-
 	query1 := `UPDATE specialists SET level = level + 1 WHERE id = ANY($1)`
 	query2 := `UPDATE specialists SET level = level - 1 WHERE id = ANY($1)`
 
-	res, err := s.db.Exec(query1, pq.Array(incrementIDs))
-	if err != nil {
-		return utils.ErrNormalizer(utils.ErrorPair{Message: utils.ExecErr, Err: err})
-	}
-	count, err := res.RowsAffected()
-	if err != nil {
-		if rbErr := tx.Rollback(); rbErr != nil {
-			return utils.ErrNormalizer(
-				utils.ErrorPair{Message: utils.RowsErr, Err: err},
-				utils.ErrorPair{Message: utils.RollbackErr, Err: rbErr},
-			)
-		}
-		return utils.ErrNormalizer(utils.ErrorPair{Message: utils.RowsErr, Err: err})
-	}
-
-	if count != int64(len(incrementIDs)) {
-		if rbErr := tx.Rollback(); rbErr != nil {
-			return utils.ErrNormalizer(
-				utils.ErrorPair{Message: utils.RowsErr, Err: fmt.Errorf(utils.CountErr, count)},
-				utils.ErrorPair{Message: utils.RollbackErr, Err: rbErr},
-			)
-		}
-		return utils.ErrNormalizer(utils.ErrorPair{Message: utils.RowsErr, Err: fmt.Errorf(utils.CountErr, count)})
-	}
-
-	res, err = s.db.Exec(query2, pq.Array(decrementIDs))
-	if err != nil {
-		return utils.ErrNormalizer(utils.ErrorPair{Message: utils.ExecErr, Err: err})
-	}
-	count, err = res.RowsAffected()
-	if err != nil {
-		if rbErr := tx.Rollback(); rbErr != nil {
-			return utils.ErrNormalizer(
-				utils.ErrorPair{Message: utils.RowsErr, Err: err},
-				utils.ErrorPair{Message: utils.RollbackErr, Err: rbErr},
-			)
+	updates := []struct {
+		query string
+		ids   []int
+	}{
+		{query: query1, ids: incrementIDs},
+		{query: query2, ids: decrementIDs},
+	}
+
+	return withTx(context.Background(), s.db, func(ctx context.Context) error {
+		tx := executor(ctx, s.db)
+
+		for _, update := range updates {
+			res, err := tx.ExecContext(ctx, update.query, pq.Array(update.ids))
+			if err != nil {
+				return utils.ErrNormalizer(utils.ErrorPair{Message: utils.ExecErr, Err: err})
+			}
+			count, err := res.RowsAffected()
+			if err != nil {
+				return utils.ErrNormalizer(utils.ErrorPair{Message: utils.RowsErr, Err: err})
+			}
+
+			if count != int64(len(update.ids)) {
+				return utils.ErrNormalizer(utils.ErrorPair{Message: utils.RowsErr, Err: fmt.Errorf(utils.CountErr, count)})
+			}
 		}
-		return utils.ErrNormalizer(utils.ErrorPair{Message: utils.RowsErr, Err: err})
-	}
-
-	if count != int64(len(decrementIDs)) {
-		if rbErr := tx.Rollback(); rbErr != nil {
-			return utils.ErrNormalizer(
-				utils.ErrorPair{Message: utils.RowsErr, Err: fmt.Errorf(utils.CountErr, count)},
-				utils.ErrorPair{Message: utils.RollbackErr, Err: rbErr},
-			)
-		}
-		return utils.ErrNormalizer(utils.ErrorPair{Message: utils.RowsErr, Err: fmt.Errorf(utils.CountErr, count)})
-	}
 
-	return nil
+		return nil
+	})
 }
 
 func (s specialistsRepo) UpdateMain(ctx context.Context, specialistUpdate models.Specialist, newPasswordFlag bool) error {
@@ -3,15 +3,12 @@ package cases
 import (
 	"context"
 	"database/sql"
-	"fmt"
 	"github.com/gl1n0m3c/IT_LAB_INIT/internal/models"
 	"github.com/gl1n0m3c/IT_LAB_INIT/internal/repository"
 	"github.com/gl1n0m3c/IT_LAB_INIT/internal/repository/tests"
-	"github.com/go-testfixtures/testfixtures/v3"
+	customErrors "github.com/gl1n0m3c/IT_LAB_INIT/pkg/utils/customerr"
 	"github.com/guregu/null"
 	"github.com/jmoiron/sqlx"
-	_ "github.com/lib/pq"
-	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"log"
 	"os"
@@ -22,51 +19,14 @@ import (
 var db *sqlx.DB
 
 func TestMain(m *testing.M) {
-	var err error
-
-	tests.InitTestConfig()
-
-	connectionString := fmt.Sprintf(
-		"user=%s password=%s host=%s port=%d dbname=%s sslmode=disable",
-		viper.GetString(tests.TestDBUser),
-		viper.GetString(tests.TestDBPassword),
-		viper.GetString(tests.TestDBHost),
-		viper.GetInt(tests.TestDBPort),
-		viper.GetString(tests.TestDBName),
-	)
-
-	db, err = sqlx.Connect("postgres", connectionString)
-	if err != nil {
-		log.Fatalf("Could not connect to the tests database: %v", err)
-	}
-
-	fixtures, err := testfixtures.New(
-		testfixtures.Database(db.DB),
-		testfixtures.Dialect("postgres"),
-		testfixtures.Paths(
-			"../../../fixtures/cameras.yml",
-			"../../../fixtures/specialists.yml",
-			"../../../fixtures/violations.yml",
-			"../../../fixtures/contacts.yml",
-		),
-	)
-	if err != nil {
-		log.Fatalf("Error creating fixtures: %v", err)
-	}
-
-	if err := fixtures.Load(); err != nil {
-		log.Fatalf("Error loading fixtures: %v", err)
-	}
+	db = tests.ConnectTestDB()
+	tests.LoadCaseFixtures(db)
 
 	code := m.Run()
 
-	db.Exec("DELETE FROM cameras")
-	db.Exec("DELETE FROM specialists")
-	db.Exec("DELETE FROM violations")
-	db.Exec("DELETE FROM contacts")
+	tests.DeleteCaseFixtures(db)
 
-	err = db.Close()
-	if err != nil {
+	if err := db.Close(); err != nil {
 		log.Fatalf("Could not close the tests database: %v", err)
 	}
 
@@ -127,48 +87,289 @@ func TestCreateGetDeleteCasesRated(t *testing.T) {
 		createdRatedIDs = append(createdRatedIDs, id)
 	}
 
-	// Get rated
-	for i, id := range createdRatedIDs {
-		if i == 3 {
-			break
-		}
+	// Пока случаи не решены, их оценки не попадают в решенные
+	solved, err := caseRepo.GetRatedSolved(ctx, createdRatedIDs[0])
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+	assert.Empty(t, solved.Rated)
 
-		caseCursor, err := caseRepo.GetRatedSolved(ctx, id-1)
+	// Решение случая проставляет статусы его оценкам
+	for i, id := range createdIDs {
+		err := caseRepo.UpdateCaseSetSolved(ctx, id, testCasesRated[i].Choice)
 		if err != nil {
 			t.Errorf(err.Error())
 		}
+	}
 
-		assert.Equal(t, null.Int{NullInt64: sql.NullInt64{Int64: int64(id), Valid: true}}, caseCursor.Cursor)
+	// Get rated
+	for _, id := range createdRatedIDs {
+		solved, err := caseRepo.GetRatedSolved(ctx, id)
+		if err != nil {
+			t.Errorf(err.Error())
+		}
 
+		if assert.NotEmpty(t, solved.Rated) {
+			assert.Equal(t, id, solved.Rated[0].ID)
+			assert.Equal(t, "Correct", solved.Rated[0].Status)
+		}
 	}
 
-	// UpdateMain rated status
-	for _, id := range createdRatedIDs {
-		err := caseRepo.UpdateRatedStatus(ctx, models.RatedUpdate{
-			CaseID: id,
-			Status: "Correct",
-		})
+	// Delete
+	for _, id := range createdIDs {
+		err := caseRepo.DeleteCase(ctx, id)
 		if err != nil {
 			t.Errorf(err.Error())
 		}
 	}
 
-	// Get rated
-	for _, id := range createdRatedIDs {
-		caseCursor, err := caseRepo.GetRatedSolved(ctx, id-1)
+}
+
+func TestCreateCasesBatch(t *testing.T) {
+	caseRepo := repository.InitCaseRepo(db)
+
+	ctx, cansel := context.WithTimeout(context.Background(), time.Second*2)
+	defer cansel()
+
+	// Случай с несуществующей камерой не должен помешать сохранению остальных
+	badCase := testCases[1]
+	badCase.CameraID = "00000000-0000-0000-0000-000000000000"
+	batch := []models.CaseBase{testCases[0], badCase, testCases[2]}
+
+	createdIDs, errs, err := caseRepo.CreateCases(ctx, batch)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Len(t, createdIDs, len(batch))
+	assert.NoError(t, errs[0])
+	assert.Error(t, errs[1])
+	assert.NoError(t, errs[2])
+	assert.Equal(t, 0, createdIDs[1])
+	assert.Less(t, createdIDs[0], createdIDs[2])
+
+	for _, i := range []int{0, 2} {
+		err := caseRepo.DeleteCase(ctx, createdIDs[i])
 		if err != nil {
 			t.Errorf(err.Error())
 		}
+	}
+}
+
+func TestGetCaseReferences(t *testing.T) {
+	violationRepo := repository.InitViolationRepo(db)
+	contactRepo := repository.InitContactRepo(db)
+
+	ctx := context.Background()
+
+	_, err := violationRepo.Get(ctx, testCases[0].ViolationID)
+	assert.NoError(t, err)
 
-		assert.Equal(t, null.Int{NullInt64: sql.NullInt64{Int64: int64(id), Valid: true}}, caseCursor.Cursor)
+	_, err = violationRepo.Get(ctx, "unknown")
+	assert.ErrorIs(t, err, customErrors.NoRowsViolationErr)
+
+	contact, err := contactRepo.Get(ctx, testCases[0].Transport)
+	if err != nil {
+		t.Fatal(err)
 	}
+	assert.Equal(t, testCases[0].Transport, contact.Transport)
+	assert.NotEmpty(t, contact.UserContacts)
 
-	// Delete
-	for _, id := range createdIDs {
-		err := caseRepo.DeleteCase(ctx, id)
+	_, err = contactRepo.Get(ctx, "X000XX00")
+	assert.ErrorIs(t, err, customErrors.NoRowsContactErr)
+}
+
+func TestCreatePromoteUnmatchedCase(t *testing.T) {
+	unmatchedRepo := repository.InitUnmatchedCaseRepo(db)
+	caseRepo := repository.InitCaseRepo(db)
+
+	ctx, cansel := context.WithTimeout(context.Background(), time.Second*2)
+	defer cansel()
+
+	caseData := testCases[0]
+	caseData.Transport = unmatchedTransport
+
+	unmatchedID, err := unmatchedRepo.Create(ctx, caseData)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unmatched, err := unmatchedRepo.GetAll(ctx, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NotEmpty(t, unmatched.Cases)
+	assert.Equal(t, unmatchedID, unmatched.Cases[0].ID)
+	assert.Equal(t, unmatchedTransport, unmatched.Cases[0].Transport)
+
+	// Без контактов для номера случай не может быть перенесен
+	_, err = unmatchedRepo.Promote(ctx, models.UnmatchedCaseResolve{UnmatchedID: unmatchedID})
+	assert.ErrorIs(t, err, customErrors.NoRowsContactErr)
+
+	caseID, err := unmatchedRepo.Promote(ctx, models.UnmatchedCaseResolve{
+		UnmatchedID: unmatchedID,
+		Contacts:    unmatchedContacts,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	caseFul, err := caseRepo.GetFulCaseByID(ctx, caseID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, unmatchedTransport, caseFul.Transport)
+	assert.Equal(t, caseData.Level, caseFul.CurrentLevel)
+
+	_, err = unmatchedRepo.Promote(ctx, models.UnmatchedCaseResolve{UnmatchedID: unmatchedID})
+	assert.ErrorIs(t, err, customErrors.NoRowsUnmatchedCaseErr)
+
+	err = caseRepo.DeleteCase(ctx, caseID)
+	if err != nil {
+		t.Errorf(err.Error())
+	}
+	db.Exec("DELETE FROM contacts WHERE transport = $1", unmatchedTransport)
+}
+
+func TestWithTx(t *testing.T) {
+	transactor := repository.InitTransactor(db)
+	caseRepo := repository.InitCaseRepo(db)
+	ctx := context.Background()
+
+	// createAndEscalate создает случай и сразу передает его на второй уровень
+	createAndEscalate := func(ctx context.Context, caseID *int) error {
+		id, err := caseRepo.CreateCase(ctx, testCases[0])
+		if err != nil {
+			return err
+		}
+		*caseID = id
+		return caseRepo.UpdateCaseLevel(ctx, id, 2)
+	}
+
+	t.Run("commit", func(t *testing.T) {
+		var caseID int
+		err := transactor.WithTx(ctx, func(ctx context.Context) error {
+			return createAndEscalate(ctx, &caseID)
+		})
 		if err != nil {
+			t.Fatal(err)
+		}
+
+		caseConsensus, err := caseRepo.GetCaseConsensus(ctx, caseID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, 2, caseConsensus.Level)
+
+		if err := caseRepo.DeleteCase(ctx, caseID); err != nil {
 			t.Errorf(err.Error())
 		}
+	})
+
+	t.Run("rollback on error", func(t *testing.T) {
+		var caseID int
+		err := transactor.WithTx(ctx, func(ctx context.Context) error {
+			if err := createAndEscalate(ctx, &caseID); err != nil {
+				return err
+			}
+			return errInjected
+		})
+		assert.ErrorIs(t, err, errInjected)
+
+		_, err = caseRepo.GetCaseConsensus(ctx, caseID)
+		assert.ErrorIs(t, err, customErrors.NoRowsCaseErr)
+	})
+
+	t.Run("rollback on panic", func(t *testing.T) {
+		var caseID int
+		assert.Panics(t, func() {
+			_ = transactor.WithTx(ctx, func(ctx context.Context) error {
+				if err := createAndEscalate(ctx, &caseID); err != nil {
+					return err
+				}
+				panic(errInjected)
+			})
+		})
+
+		_, err := caseRepo.GetCaseConsensus(ctx, caseID)
+		assert.ErrorIs(t, err, customErrors.NoRowsCaseErr)
+	})
+
+	t.Run("nested", func(t *testing.T) {
+		// Вложенный вызов выполняется во внешней транзакции и откатывается вместе с ней
+		var caseID int
+		err := transactor.WithTx(ctx, func(ctx context.Context) error {
+			err := transactor.WithTx(ctx, func(ctx context.Context) error {
+				return createAndEscalate(ctx, &caseID)
+			})
+			if err != nil {
+				return err
+			}
+			return errInjected
+		})
+		assert.ErrorIs(t, err, errInjected)
+
+		_, err = caseRepo.GetCaseConsensus(ctx, caseID)
+		assert.ErrorIs(t, err, customErrors.NoRowsCaseErr)
+	})
+}
+
+func TestUpdateCaseSetSolvedRollback(t *testing.T) {
+	caseRepo := repository.InitCaseRepo(db)
+	ctx := context.Background()
+
+	caseID, err := caseRepo.CreateCase(ctx, testCases[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer caseRepo.DeleteCase(ctx, caseID)
+
+	rated := testCasesRated[3]
+	rated.CaseID = caseID
+	if _, err := caseRepo.CreateRated(ctx, rated); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := db.Exec(failSpecialistsUpdateUp); err != nil {
+		t.Fatal(err)
+	}
+
+	// Случай и статусы оценок обновляются раньше специалистов, но после ошибки не должны сохраниться
+	err = caseRepo.UpdateCaseSetSolved(ctx, caseID, rated.Choice)
+	assert.Error(t, err)
+
+	if _, err := db.Exec(failSpecialistsUpdateDown); err != nil {
+		t.Fatal(err)
 	}
 
+	caseFul, err := caseRepo.GetFulCaseByID(ctx, caseID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.False(t, caseFul.IsSolved)
+
+	var status string
+	err = db.Get(&status, `SELECT status FROM rated_cases WHERE case_id = $1`, caseID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "Unknown", status)
+
+	// Без ошибки все три изменения сохраняются
+	err = caseRepo.UpdateCaseSetSolved(ctx, caseID, rated.Choice)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	caseFul, err = caseRepo.GetFulCaseByID(ctx, caseID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, caseFul.IsSolved)
+
+	err = db.Get(&status, `SELECT status FROM rated_cases WHERE case_id = $1`, caseID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "Correct", status)
 }
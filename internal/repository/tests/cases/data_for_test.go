@@ -1,6 +1,7 @@
 package cases
 
 import (
+	"errors"
 	"github.com/gl1n0m3c/IT_LAB_INIT/internal/models"
 	"time"
 )
@@ -100,3 +101,23 @@ var testCasesRated = []models.RatedBase{
 		Status:       "Unknown",
 	},
 }
+
+// Номер, для которого в фикстурах нет контактов
+const unmatchedTransport = "X001XX77"
+
+var unmatchedContacts = map[string]string{"email": "owner@example.com"}
+
+var errInjected = errors.New("injected failure")
+
+// Триггер, из-за которого падает обновление специалистов - последний шаг UpdateCaseSetSolved
+const (
+	failSpecialistsUpdateUp = `CREATE OR REPLACE FUNCTION fail_specialists_update() RETURNS trigger AS $$
+							   BEGIN
+								   RAISE EXCEPTION 'injected failure';
+							   END;
+							   $$ LANGUAGE plpgsql;
+							   CREATE TRIGGER fail_specialists_update BEFORE UPDATE ON specialists
+							   FOR EACH ROW EXECUTE FUNCTION fail_specialists_update();`
+	failSpecialistsUpdateDown = `DROP TRIGGER IF EXISTS fail_specialists_update ON specialists;
+								 DROP FUNCTION IF EXISTS fail_specialists_update();`
+)
@@ -0,0 +1,225 @@
+package consensus
+
+import (
+	"context"
+	"errors"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/models"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/repository"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/repository/tests"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/services"
+	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/config"
+	logger "github.com/gl1n0m3c/IT_LAB_INIT/pkg/log"
+	customErrors "github.com/gl1n0m3c/IT_LAB_INIT/pkg/utils/customerr"
+	"github.com/guregu/null"
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"log"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+var db *sqlx.DB
+
+func TestMain(m *testing.M) {
+	db = tests.ConnectTestDB()
+	tests.LoadCaseFixtures(db)
+
+	code := m.Run()
+
+	tests.DeleteCaseFixtures(db)
+
+	if err := db.Close(); err != nil {
+		log.Fatalf("Could not close the tests database: %v", err)
+	}
+
+	os.Exit(code)
+}
+
+func TestGetCaseConsensus(t *testing.T) {
+	caseRepo := repository.InitCaseRepo(db)
+
+	ctx, cansel := context.WithTimeout(context.Background(), time.Second*2)
+	defer cansel()
+
+	// testCases[1] - парковка, для которой в фикстурах задано решение большинством из трех оценок
+	caseID, err := caseRepo.CreateCase(ctx, testCases[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, rated := range testCasesRated {
+		rated.CaseID = caseID
+		if _, err := caseRepo.CreateRated(ctx, rated); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	caseConsensus, err := caseRepo.GetCaseConsensus(ctx, caseID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, testCases[1].Level, caseConsensus.Level)
+	assert.False(t, caseConsensus.IsSolved)
+	assert.Equal(t, "majority", caseConsensus.Policy)
+	assert.Equal(t, null.IntFrom(3), caseConsensus.K)
+	assert.Equal(t, []models.CaseVote{{Choice: true, Level: 1}, {Choice: false, Level: 2}}, caseConsensus.Votes)
+
+	// Для остальных типов нарушений действует единогласие и K из конфига
+	otherID, err := caseRepo.CreateCase(ctx, testCases[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	caseConsensus, err = caseRepo.GetCaseConsensus(ctx, otherID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "unanimous", caseConsensus.Policy)
+	assert.False(t, caseConsensus.K.Valid)
+	assert.Empty(t, caseConsensus.Votes)
+
+	_, err = caseRepo.GetCaseConsensus(ctx, 0)
+	assert.ErrorIs(t, err, customErrors.NoRowsCaseErr)
+
+	for _, id := range []int{caseID, otherID} {
+		if err := caseRepo.DeleteCase(ctx, id); err != nil {
+			t.Errorf(err.Error())
+		}
+	}
+}
+
+func TestCreateRatedConcurrent(t *testing.T) {
+	viper.Set(config.K, concurrentK)
+	viper.Set(config.DBResponseTime, 5)
+
+	caseRepo := repository.InitCaseRepo(db)
+	ctx := context.Background()
+
+	specialistIDs := tests.CreateLevelSpecialists(t, db, "concurrent", concurrentSpecialists, 1)
+
+	nop := zerolog.Nop()
+	logs := &logger.Logs{InfoLogger: &nop, ErrorLogger: &nop}
+
+	// rate параллельно отправляет оценки choices разных специалистов по новому случаю
+	// и возвращает случай, число успешных оценок, число отказов и число уведомлений в очереди
+	rate := func(t *testing.T, choices []bool) (models.CaseConsensus, int, int, int) {
+		caseID, err := caseRepo.CreateCase(ctx, testCases[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { caseRepo.DeleteCase(ctx, caseID) })
+
+		specialistService := services.InitSpecialistService(repository.InitSpecialistsRepo(db), caseRepo,
+			repository.InitRatingReasonRepo(db), logs)
+
+		errs := make([]error, len(choices))
+		var wg sync.WaitGroup
+		for i, choice := range choices {
+			wg.Add(1)
+			go func(i int, choice bool) {
+				defer wg.Done()
+				_, errs[i] = specialistService.CreateRated(ctx, models.RatedBase{
+					RatedCreate:  models.RatedCreate{CaseID: caseID, Choice: choice},
+					SpecialistID: specialistIDs[i],
+					Date:         time.Now().UTC(),
+					Status:       "Unknown",
+				})
+			}(i, choice)
+		}
+		wg.Wait()
+
+		var created, rejected int
+		for _, err := range errs {
+			switch {
+			case err == nil:
+				created++
+			case errors.Is(err, customErrors.CaseAlreadySolved):
+				rejected++
+			default:
+				t.Errorf("неожиданная ошибка: %v", err)
+			}
+		}
+
+		caseConsensus, err := caseRepo.GetCaseConsensus(ctx, caseID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		notifications, err := repository.InitNotificationRepo(db).GetByCaseID(ctx, caseID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return caseConsensus, created, rejected, len(notifications)
+	}
+
+	t.Run("solved once", func(t *testing.T) {
+		caseConsensus, created, rejected, queued := rate(t, []bool{true, true, true, true, true})
+
+		assert.Equal(t, concurrentK, created)
+		assert.Equal(t, concurrentSpecialists-concurrentK, rejected)
+		assert.Equal(t, 1, queued)
+		assert.True(t, caseConsensus.IsSolved)
+		assert.Equal(t, 1, caseConsensus.Level)
+		assert.Len(t, caseConsensus.Votes, concurrentK)
+	})
+
+	t.Run("escalated once", func(t *testing.T) {
+		caseConsensus, created, rejected, queued := rate(t, []bool{true, false, true})
+
+		assert.Equal(t, concurrentK, created)
+		assert.Zero(t, rejected)
+		assert.Zero(t, queued)
+		assert.False(t, caseConsensus.IsSolved)
+		assert.Equal(t, 2, caseConsensus.Level)
+	})
+}
+
+func TestGetCasesByLevelEscalated(t *testing.T) {
+	caseRepo := repository.InitCaseRepo(db)
+	ctx := context.Background()
+
+	specialistIDs := tests.CreateLevelSpecialists(t, db, "escalated", concurrentK, 1)
+
+	// Разные оценки единогласного случая поднимают его на второй уровень
+	caseID, err := caseRepo.CreateCase(ctx, testCases[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer caseRepo.DeleteCase(ctx, caseID)
+
+	var resolution models.RatedResolution
+	for i, choice := range []bool{true, false, true} {
+		resolution, err = caseRepo.CreateRatedConsensus(ctx, models.RatedBase{
+			RatedCreate:  models.RatedCreate{CaseID: caseID, Choice: choice},
+			SpecialistID: specialistIDs[i],
+			Date:         time.Now().UTC(),
+			Status:       "Unknown",
+		}, 1, concurrentK, maxCaseLevel)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	assert.True(t, resolution.Escalated)
+
+	// Случай выдается специалистам второго уровня и больше не выдается специалистам первого
+	secondLevel, err := caseRepo.GetCasesByLevel(ctx, 0, 2, caseID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if assert.NotEmpty(t, secondLevel.Cases) {
+		assert.Equal(t, caseID, secondLevel.Cases[0].ID)
+	}
+
+	firstLevel, err := caseRepo.GetCasesByLevel(ctx, 0, 1, caseID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, caseData := range firstLevel.Cases {
+		assert.NotEqual(t, caseID, caseData.ID)
+	}
+}
@@ -0,0 +1,59 @@
+package consensus
+
+import (
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/models"
+	"time"
+)
+
+var testCases = []models.CaseBase{
+	{
+		CameraID:       "c70a6f9c-cb9b-4cd9-9425-77e8c8a5b072",
+		Transport:      "A123BC97",
+		ViolationID:    "e2f7e5b2-ea16-4c48-8a8a-5e2d1a2b8410",
+		ViolationValue: "Speeding",
+		Level:          1,
+		Datetime:       time.Now().UTC(),
+		PhotoUrl:       "http://example.com/photo1.jpg",
+	},
+	{
+		CameraID:       "d95a3f0c-cb9b-4cd9-9425-77e8c8a5b072",
+		Transport:      "B234CD98",
+		ViolationID:    "f3a4b5c6-d7e8-9fa0-b1c2-d3e4f5a6b7c8",
+		ViolationValue: "Parking",
+		Level:          1,
+		Datetime:       time.Now().UTC(),
+		PhotoUrl:       "http://example.com/photo2.jpg",
+	},
+}
+
+// Оценки специалистов первого и второго уровня из фикстур
+var testCasesRated = []models.RatedBase{
+	{
+		RatedCreate: models.RatedCreate{
+			CaseID: 0,
+			Choice: true,
+		},
+		SpecialistID: 1,
+		Date:         time.Now().UTC(),
+		Status:       "Unknown",
+	},
+	{
+		RatedCreate: models.RatedCreate{
+			CaseID: 0,
+			Choice: false,
+		},
+		SpecialistID: 2,
+		Date:         time.Now().UTC(),
+		Status:       "Unknown",
+	},
+}
+
+// Максимальный уровень случая, который в тестах не достигается
+const maxCaseLevel = 5
+
+// Параметры проверки параллельных оценок: K оценок на уровне и число специалистов первого уровня,
+// одновременно оценивающих один случай
+const (
+	concurrentK           = 3
+	concurrentSpecialists = 5
+)
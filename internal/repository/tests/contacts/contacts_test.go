@@ -0,0 +1,143 @@
+package contacts
+
+import (
+	"context"
+	"fmt"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/models"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/repository"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/repository/tests"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"log"
+	"os"
+	"testing"
+	"time"
+)
+
+var db *sqlx.DB
+
+func TestMain(m *testing.M) {
+	var err error
+
+	tests.InitTestConfig()
+
+	connectionString := fmt.Sprintf(
+		"user=%s password=%s host=%s port=%d dbname=%s sslmode=disable",
+		viper.GetString(tests.TestDBUser),
+		viper.GetString(tests.TestDBPassword),
+		viper.GetString(tests.TestDBHost),
+		viper.GetInt(tests.TestDBPort),
+		viper.GetString(tests.TestDBName),
+	)
+
+	db, err = sqlx.Connect("postgres", connectionString)
+	if err != nil {
+		log.Fatalf("Could not connect to the tests database: %v", err)
+	}
+
+	code := m.Run()
+
+	err = db.Close()
+	if err != nil {
+		log.Fatalf("Could not close the tests database: %v", err)
+	}
+
+	os.Exit(code)
+}
+
+func TestRenameTransport(t *testing.T) {
+	contactRepo := repository.InitContactRepo(db)
+	cameraRepo := repository.InitCameraRepo(db)
+	caseRepo := repository.InitCaseRepo(db)
+	unmatchedRepo := repository.InitUnmatchedCaseRepo(db)
+
+	ctx, cansel := context.WithTimeout(context.Background(), time.Second*2)
+	defer cansel()
+
+	_, err := contactRepo.Create([]models.Contact{
+		{Transport: legacyTransport, UserContacts: legacyContacts},
+		{Transport: normalizedTransport, UserContacts: normalizedContacts},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		db.Exec("DELETE FROM contacts WHERE transport = ANY($1)", pq.Array([]string{legacyTransport, normalizedTransport}))
+	})
+
+	cameraID, err := cameraRepo.Create(ctx, testCamera)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		db.Exec("DELETE FROM cameras WHERE id = $1", cameraID)
+	})
+
+	_, err = db.ExecContext(ctx, "INSERT INTO violations (id, type, amount) VALUES ($1, $2, 100)", testViolationID, testViolationType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		db.Exec("DELETE FROM violations WHERE id = $1", testViolationID)
+	})
+
+	caseData := testCase
+	caseData.CameraID = cameraID
+
+	caseID, err := caseRepo.CreateCase(ctx, caseData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		db.Exec("DELETE FROM cases WHERE id = $1", caseID)
+	})
+
+	unmatchedID, err := unmatchedRepo.Create(ctx, caseData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		db.Exec("DELETE FROM unmatched_cases WHERE id = $1", unmatchedID)
+	})
+
+	transports, err := contactRepo.GetTransports(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Contains(t, transports, legacyTransport)
+
+	err = contactRepo.RenameTransport(ctx, legacyTransport, normalizedTransport)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	transports, err = contactRepo.GetTransports(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NotContains(t, transports, legacyTransport)
+	assert.Contains(t, transports, normalizedTransport)
+
+	// Существующие контакты нормализованного номера не перезаписываются, недостающие дописываются
+	var contactsJSON []byte
+	err = db.GetContext(ctx, &contactsJSON, "SELECT contacts FROM contacts WHERE transport = $1", normalizedTransport)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.JSONEq(t, `{"email": "new@example.com", "Номер телефона": "79990000000"}`, string(contactsJSON))
+
+	caseFul, err := caseRepo.GetFulCaseByID(ctx, caseID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, normalizedTransport, caseFul.Transport)
+
+	var unmatchedTransport string
+	err = db.GetContext(ctx, &unmatchedTransport, "SELECT transport FROM unmatched_cases WHERE id = $1", unmatchedID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, normalizedTransport, unmatchedTransport)
+}
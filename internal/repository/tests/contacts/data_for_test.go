@@ -0,0 +1,39 @@
+package contacts
+
+import (
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/models"
+	"time"
+)
+
+// Номер, записанный кириллицей до приведения номеров к единому виду, и его нормализованный вид
+const (
+	legacyTransport     = "А777АА77"
+	normalizedTransport = "A777AA77"
+)
+
+// Контакты, сохраненные для номера в старом виде, и контакты, уже заведенные для нормализованного номера
+var (
+	legacyContacts     = map[string]string{"email": "old@example.com", "Номер телефона": "79990000000"}
+	normalizedContacts = map[string]string{"email": "new@example.com"}
+)
+
+var testCamera = models.CameraBase{
+	Type:        "camerus1",
+	Coordinates: [2]float64{55.7558, 37.6173},
+	Description: "plates test camera",
+}
+
+// Нарушение, на которое ссылается случай с номером в старом виде
+const (
+	testViolationID   = "a7c1d2e3-plates-test-violation"
+	testViolationType = "Plates Test"
+)
+
+var testCase = models.CaseBase{
+	Transport:      legacyTransport,
+	ViolationID:    testViolationID,
+	ViolationValue: "90 км/ч",
+	Level:          1,
+	Datetime:       time.Now().UTC(),
+	PhotoUrl:       "http://example.com/photo.jpg",
+}
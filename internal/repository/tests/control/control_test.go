@@ -0,0 +1,146 @@
+package control
+
+import (
+	"context"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/models"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/repository"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/repository/tests"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/services"
+	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/config"
+	logger "github.com/gl1n0m3c/IT_LAB_INIT/pkg/log"
+	customErrors "github.com/gl1n0m3c/IT_LAB_INIT/pkg/utils/customerr"
+	"github.com/guregu/null"
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"log"
+	"os"
+	"testing"
+	"time"
+)
+
+var db *sqlx.DB
+
+func TestMain(m *testing.M) {
+	db = tests.ConnectTestDB()
+	tests.LoadCaseFixtures(db)
+
+	code := m.Run()
+
+	tests.DeleteCaseFixtures(db)
+
+	if err := db.Close(); err != nil {
+		log.Fatalf("Could not close the tests database: %v", err)
+	}
+
+	os.Exit(code)
+}
+
+func TestControlCase(t *testing.T) {
+	viper.Set(config.K, levelK)
+	viper.Set(config.DBResponseTime, 5)
+
+	caseRepo := repository.InitCaseRepo(db)
+	specialistsRepo := repository.InitSpecialistsRepo(db)
+	ctx := context.Background()
+
+	// Последний специалист оценивает случай после того, как его оценили K специалистов
+	specialistIDs := tests.CreateLevelSpecialists(t, db, "control", len(controlVotes)+1, 1)
+	late := specialistIDs[len(controlVotes)]
+
+	timeStart := time.Now().UTC().Add(-time.Minute)
+
+	caseID, err := caseRepo.CreateControlCase(ctx, testCase, controlChoice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer caseRepo.DeleteCase(ctx, caseID)
+
+	// Контрольный случай выдается специалистам как обычный
+	cases, err := caseRepo.GetCasesByLevel(ctx, specialistIDs[0], testCase.Level, caseID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if assert.NotEmpty(t, cases.Cases) {
+		assert.Equal(t, caseID, cases.Cases[0].ID)
+	}
+
+	nop := zerolog.Nop()
+	specialistService := services.InitSpecialistService(specialistsRepo, caseRepo, repository.InitRatingReasonRepo(db),
+		&logger.Logs{InfoLogger: &nop, ErrorLogger: &nop})
+
+	for i, choice := range controlVotes {
+		_, err = specialistService.CreateRated(ctx, models.RatedBase{
+			RatedCreate:  models.RatedCreate{CaseID: caseID, Choice: choice},
+			SpecialistID: specialistIDs[i],
+			Date:         time.Now().UTC(),
+			Status:       "Unknown",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// K оценок закрывают случай без штрафа, больше он не выдается
+	notifications, err := repository.InitNotificationRepo(db).GetByCaseID(ctx, caseID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, notifications)
+
+	cases, err = caseRepo.GetCasesByLevel(ctx, late, testCase.Level, caseID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, caseData := range cases.Cases {
+		assert.NotEqual(t, caseID, caseData.ID)
+	}
+
+	_, err = specialistService.CreateRated(ctx, models.RatedBase{
+		RatedCreate:  models.RatedCreate{CaseID: caseID, Choice: controlChoice},
+		SpecialistID: late,
+		Date:         time.Now().UTC(),
+		Status:       "Unknown",
+	})
+	assert.ErrorIs(t, err, customErrors.CaseAlreadySolved)
+
+	caseFul, err := caseRepo.GetFulCaseByID(ctx, caseID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, caseFul.IsSolved)
+	assert.Equal(t, testCase.Level, caseFul.CurrentLevel)
+	assert.Equal(t, null.BoolFrom(controlChoice), caseFul.Control)
+	if assert.NotNil(t, caseFul.RatedCovers) && assert.Len(t, *caseFul.RatedCovers, len(controlVotes)) {
+		for _, cover := range *caseFul.RatedCovers {
+			assert.Equal(t, "Unknown", cover.Status)
+		}
+	}
+
+	rating, err := specialistsRepo.GetSpecialistRating(ctx, timeStart, time.Now().UTC().Add(time.Minute), specialistIDs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checked := 0
+	for _, specialist := range rating.Specialists {
+		for i, id := range specialistIDs[:len(controlVotes)] {
+			if specialist.ID != id {
+				continue
+			}
+			checked++
+
+			accuracy := 0.0
+			if controlVotes[i] == controlChoice {
+				accuracy = 1
+			}
+			assert.Zero(t, specialist.Total)
+			assert.Zero(t, specialist.Unknown)
+			assert.Equal(t, 1, specialist.ControlTotal)
+			assert.Equal(t, int(accuracy), specialist.ControlCorrect)
+			assert.Equal(t, null.FloatFrom(accuracy), specialist.Accuracy)
+		}
+	}
+	assert.NotZero(t, checked)
+}
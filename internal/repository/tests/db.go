@@ -0,0 +1,90 @@
+package tests
+
+import (
+	"fmt"
+	"github.com/go-testfixtures/testfixtures/v3"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/spf13/viper"
+	"log"
+	"testing"
+)
+
+// Фикстуры, которые нужны тестам случаев, пути указаны относительно каталога пакета с тестами.
+// Все пакеты тестов репозиториев работают с одной базой, поэтому их запускают последовательно:
+// go test -p 1 ./internal/repository/tests/...
+var caseFixtures = []string{
+	"../../../fixtures/cameras.yml",
+	"../../../fixtures/specialists.yml",
+	"../../../fixtures/violations.yml",
+	"../../../fixtures/contacts.yml",
+}
+
+// ConnectTestDB подключается к тестовой базе из tests.env
+func ConnectTestDB() *sqlx.DB {
+	InitTestConfig()
+
+	connectionString := fmt.Sprintf(
+		"user=%s password=%s host=%s port=%d dbname=%s sslmode=disable",
+		viper.GetString(TestDBUser),
+		viper.GetString(TestDBPassword),
+		viper.GetString(TestDBHost),
+		viper.GetInt(TestDBPort),
+		viper.GetString(TestDBName),
+	)
+
+	db, err := sqlx.Connect("postgres", connectionString)
+	if err != nil {
+		log.Fatalf("Could not connect to the tests database: %v", err)
+	}
+
+	return db
+}
+
+// LoadCaseFixtures загружает камеры, специалистов, нарушения и контакты для тестов случаев
+func LoadCaseFixtures(db *sqlx.DB) {
+	fixtures, err := testfixtures.New(
+		testfixtures.Database(db.DB),
+		testfixtures.Dialect("postgres"),
+		testfixtures.Paths(caseFixtures...),
+	)
+	if err != nil {
+		log.Fatalf("Error creating fixtures: %v", err)
+	}
+
+	if err := fixtures.Load(); err != nil {
+		log.Fatalf("Error loading fixtures: %v", err)
+	}
+}
+
+// DeleteCaseFixtures удаляет данные, загруженные LoadCaseFixtures
+func DeleteCaseFixtures(db *sqlx.DB) {
+	db.Exec("DELETE FROM cameras")
+	db.Exec("DELETE FROM specialists")
+	db.Exec("DELETE FROM violations")
+	db.Exec("DELETE FROM contacts")
+}
+
+// CreateLevelSpecialists регистрирует n подтвержденных специалистов уровня level с логинами prefix0, prefix1, ...
+// и удаляет их по завершении теста
+func CreateLevelSpecialists(t *testing.T, db *sqlx.DB, prefix string, n, level int) []int {
+	t.Helper()
+
+	specialistIDs := make([]int, 0, n)
+	t.Cleanup(func() {
+		db.Exec("DELETE FROM specialists WHERE id = ANY($1)", pq.Array(specialistIDs))
+	})
+
+	for i := 0; i < n; i++ {
+		var id int
+		err := db.QueryRowx(`INSERT INTO specialists (login, hashed_password, level, is_verified)
+							 VALUES ($1, 'hash', $2, true) RETURNING id;`,
+			fmt.Sprintf("%s%d", prefix, i), level).Scan(&id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		specialistIDs = append(specialistIDs, id)
+	}
+
+	return specialistIDs
+}
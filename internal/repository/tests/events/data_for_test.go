@@ -0,0 +1,32 @@
+package events
+
+import (
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/models"
+	"time"
+)
+
+var testCase = models.CaseBase{
+	CameraID:       "c70a6f9c-cb9b-4cd9-9425-77e8c8a5b072",
+	Transport:      "A123BC97",
+	ViolationID:    "e2f7e5b2-ea16-4c48-8a8a-5e2d1a2b8410",
+	ViolationValue: "Speeding",
+	Level:          1,
+	Datetime:       time.Now().UTC(),
+	PhotoUrl:       "http://example.com/photo1.jpg",
+}
+
+// K оценок на уровне и максимальный уровень случая, который в тесте не достигается
+const (
+	levelK       = 3
+	maxCaseLevel = 5
+)
+
+// Ожидаемая история случая, который передается на второй уровень, решается там и удаляется
+var caseEventsTimeline = []string{
+	models.CaseEventCreated,
+	models.CaseEventRated, models.CaseEventRated, models.CaseEventRated,
+	models.CaseEventEscalated,
+	models.CaseEventRated, models.CaseEventRated, models.CaseEventRated,
+	models.CaseEventSolved,
+	models.CaseEventDeleted,
+}
@@ -0,0 +1,82 @@
+package events
+
+import (
+	"context"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/models"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/repository"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/repository/tests"
+	"github.com/guregu/null"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"log"
+	"os"
+	"testing"
+	"time"
+)
+
+var db *sqlx.DB
+
+func TestMain(m *testing.M) {
+	db = tests.ConnectTestDB()
+	tests.LoadCaseFixtures(db)
+
+	code := m.Run()
+
+	tests.DeleteCaseFixtures(db)
+
+	if err := db.Close(); err != nil {
+		log.Fatalf("Could not close the tests database: %v", err)
+	}
+
+	os.Exit(code)
+}
+
+func TestCaseEvents(t *testing.T) {
+	caseRepo := repository.InitCaseRepo(db)
+	eventRepo := repository.InitCaseEventRepo(db)
+	ctx := context.Background()
+
+	// По три специалиста первого и второго уровня
+	specialistIDs := append(tests.CreateLevelSpecialists(t, db, "events_first", levelK, 1),
+		tests.CreateLevelSpecialists(t, db, "events_second", levelK, 2)...)
+
+	caseID, err := caseRepo.CreateCase(ctx, testCase)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, choice := range []bool{true, false, true, true, true, true} {
+		_, err = caseRepo.CreateRatedConsensus(ctx, models.RatedBase{
+			RatedCreate:  models.RatedCreate{CaseID: caseID, Choice: choice},
+			SpecialistID: specialistIDs[i],
+			Date:         time.Now().UTC(),
+			Status:       "Unknown",
+		}, i/levelK+1, levelK, maxCaseLevel)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err = caseRepo.DeleteCase(ctx, caseID); err != nil {
+		t.Fatal(err)
+	}
+
+	// История сохраняется после удаления случая
+	events, err := eventRepo.GetByCaseID(ctx, caseID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var types []string
+	for _, event := range events {
+		types = append(types, event.Type)
+	}
+	assert.Equal(t, caseEventsTimeline, types)
+
+	escalated, solved := events[4], events[8]
+	assert.Equal(t, null.IntFrom(2), escalated.Level)
+	assert.Equal(t, null.IntFrom(int64(specialistIDs[2])), escalated.SpecialistID)
+	assert.Equal(t, null.BoolFrom(true), solved.Choice)
+	assert.Equal(t, null.IntFrom(int64(specialistIDs[5])), solved.SpecialistID)
+	assert.Equal(t, null.IntFrom(2), events[len(events)-1].Level)
+}
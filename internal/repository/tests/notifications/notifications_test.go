@@ -0,0 +1,234 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/dispatcher"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/models"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/repository"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/repository/tests"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/services"
+	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/config"
+	logger "github.com/gl1n0m3c/IT_LAB_INIT/pkg/log"
+	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/notifier"
+	notifierTests "github.com/gl1n0m3c/IT_LAB_INIT/pkg/notifier/tests"
+	customErrors "github.com/gl1n0m3c/IT_LAB_INIT/pkg/utils/customerr"
+	"github.com/guregu/null"
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"log"
+	"os"
+	"testing"
+	"time"
+)
+
+var db *sqlx.DB
+
+func TestMain(m *testing.M) {
+	db = tests.ConnectTestDB()
+	tests.LoadCaseFixtures(db)
+
+	code := m.Run()
+
+	tests.DeleteCaseFixtures(db)
+
+	if err := db.Close(); err != nil {
+		log.Fatalf("Could not close the tests database: %v", err)
+	}
+
+	os.Exit(code)
+}
+
+func TestNotificationOutbox(t *testing.T) {
+	caseRepo := repository.InitCaseRepo(db)
+	notificationRepo := repository.InitNotificationRepo(db)
+	ctx := context.Background()
+
+	specialistIDs := tests.CreateLevelSpecialists(t, db, "outbox", levelK, 1)
+
+	caseID, err := caseRepo.CreateCase(ctx, testCase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer caseRepo.DeleteCase(ctx, caseID)
+
+	for _, specialistID := range specialistIDs {
+		_, err = caseRepo.CreateRatedConsensus(ctx, models.RatedBase{
+			RatedCreate:  models.RatedCreate{CaseID: caseID, Choice: true},
+			SpecialistID: specialistID,
+			Date:         time.Now().UTC(),
+			Status:       "Unknown",
+		}, 1, levelK, maxCaseLevel)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Повторная постановка того же решения в очередь не создает второго уведомления
+	err = notificationRepo.Enqueue(ctx, caseID, models.NotificationFine)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// notification возвращает единственное уведомление случая
+	notification := func() models.Notification {
+		notifications, err := notificationRepo.GetByCaseID(ctx, caseID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !assert.Len(t, notifications, 1) {
+			t.FailNow()
+		}
+		return notifications[0]
+	}
+	// makeDue переносит следующую попытку на текущий момент, не дожидаясь задержки
+	makeDue := func() {
+		_, err := db.ExecContext(ctx, "UPDATE notifications SET next_attempt_at = NOW() WHERE case_id = $1", caseID)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	queued := notification()
+	assert.Equal(t, models.NotificationFine, queued.Kind)
+	assert.Equal(t, models.NotificationPending, queued.Status)
+	assert.Zero(t, queued.Attempts)
+
+	viper.Set(config.NotifyMaxAttempts, notifyMaxAttempts)
+	defer viper.Set(config.NotifyMaxAttempts, 0)
+
+	nop := zerolog.Nop()
+	logs := &logger.Logs{InfoLogger: &nop, ErrorLogger: &nop}
+	sendErr := errors.New("почтовый сервер недоступен")
+	var sent []notifier.Message
+
+	// Почтовый канал сначала не отвечает, а после повтора доставляет уведомление
+	failingEmail := notifierTests.FakeNotifier{Name: models.ChannelEmail, Key: models.ContactEmail, Err: sendErr}
+	email := notifierTests.FakeNotifier{Name: models.ChannelEmail, Key: models.ContactEmail, Messages: &sent}
+
+	failing := dispatcher.InitDispatcher(notificationRepo, caseRepo, repository.InitTransactor(db),
+		notifier.InitPolicy(failingEmail), logs)
+
+	for attempt := 1; attempt <= notifyMaxAttempts; attempt++ {
+		makeDue()
+		_, err = failing.Dispatch(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		failed := notification()
+		assert.Equal(t, attempt, failed.Attempts)
+		assert.Contains(t, failed.LastError.String, sendErr.Error())
+		if assert.Len(t, failed.Deliveries, attempt) {
+			assert.Equal(t, models.ChannelEmail, failed.Deliveries[attempt-1].Channel)
+			assert.False(t, failed.Deliveries[attempt-1].Delivered)
+			assert.Equal(t, null.StringFrom(sendErr.Error()), failed.Deliveries[attempt-1].Error)
+		}
+		if attempt < notifyMaxAttempts {
+			assert.Equal(t, models.NotificationPending, failed.Status)
+			assert.True(t, failed.NextAttemptAt.After(time.Now()))
+		} else {
+			assert.Equal(t, models.NotificationDead, failed.Status)
+		}
+	}
+
+	dead, err := notificationRepo.GetDead(ctx, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var deadIDs []int
+	for _, n := range dead.Notifications {
+		deadIDs = append(deadIDs, n.ID)
+	}
+	assert.Contains(t, deadIDs, queued.ID)
+
+	err = notificationRepo.Retry(ctx, queued.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = notificationRepo.Retry(ctx, queued.ID)
+	assert.ErrorIs(t, err, customErrors.NoRowsNotificationErr)
+
+	succeeding := dispatcher.InitDispatcher(notificationRepo, caseRepo, repository.InitTransactor(db),
+		notifier.InitPolicy(email), logs)
+
+	_, err = succeeding.Dispatch(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	delivered := notification()
+	assert.Equal(t, models.NotificationSent, delivered.Status)
+	assert.Equal(t, 1, delivered.Attempts)
+	assert.True(t, delivered.SentAt.Valid)
+	if assert.Len(t, sent, 1) {
+		assert.Equal(t, models.NotificationFine, sent[0].Kind)
+	}
+	if assert.Len(t, delivered.Deliveries, notifyMaxAttempts+1) {
+		last := delivered.Deliveries[notifyMaxAttempts]
+		assert.True(t, last.Delivered)
+		assert.False(t, last.Error.Valid)
+		assert.Equal(t, sent[0].Fine.Contacts[models.ContactEmail], last.Recipient)
+	}
+
+	// Отправленное уведомление не отправляется повторно
+	_, err = succeeding.Dispatch(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, sent, 1)
+
+	events, err := repository.InitCaseEventRepo(db).GetByCaseID(ctx, caseID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if assert.NotEmpty(t, events) {
+		last := events[len(events)-1]
+		assert.Equal(t, models.CaseEventNotified, last.Type)
+		assert.Equal(t, null.StringFrom(models.NotificationFine), last.Message)
+	}
+}
+
+func TestPreviewNotification(t *testing.T) {
+	caseRepo := repository.InitCaseRepo(db)
+	ctx := context.Background()
+
+	nop := zerolog.Nop()
+	managerService := services.InitManagerService(caseRepo, repository.InitSpecialistsRepo(db),
+		repository.InitUnmatchedCaseRepo(db), repository.InitRatingReasonRepo(db), repository.InitNotificationRepo(db),
+		&logger.Logs{InfoLogger: &nop, ErrorLogger: &nop})
+
+	caseID, err := caseRepo.CreateCase(ctx, testCase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer caseRepo.DeleteCase(ctx, caseID)
+
+	preview, err := managerService.PreviewNotification(ctx, caseID, models.NotificationFine, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, caseID, preview.CaseID)
+	assert.Equal(t, "ru", preview.Language)
+	assert.Equal(t, testCase.PhotoUrl, preview.Photo)
+	assert.NotEmpty(t, preview.Subject)
+	assert.Contains(t, preview.HTML, "<html")
+
+	preview, err = managerService.PreviewNotification(ctx, caseID, models.NotificationFineCancel, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "en", preview.Language)
+	assert.Empty(t, preview.Photo)
+
+	_, err = managerService.PreviewNotification(ctx, caseID, models.NotificationFine, "de")
+	assert.ErrorIs(t, err, customErrors.UnknownLanguageErr)
+
+	_, err = managerService.PreviewNotification(ctx, caseID, "reminder", "")
+	assert.ErrorIs(t, err, customErrors.UnknownNotificationKindErr)
+
+	_, err = managerService.PreviewNotification(ctx, 0, models.NotificationFine, "")
+	assert.ErrorIs(t, err, customErrors.NoRowsCaseErr)
+}
@@ -0,0 +1,122 @@
+package reasons
+
+import (
+	"context"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/models"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/repository"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/repository/tests"
+	customErrors "github.com/gl1n0m3c/IT_LAB_INIT/pkg/utils/customerr"
+	"github.com/guregu/null"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"log"
+	"os"
+	"testing"
+)
+
+var db *sqlx.DB
+
+func TestMain(m *testing.M) {
+	db = tests.ConnectTestDB()
+	tests.LoadCaseFixtures(db)
+
+	code := m.Run()
+
+	tests.DeleteCaseFixtures(db)
+
+	if err := db.Close(); err != nil {
+		log.Fatalf("Could not close the tests database: %v", err)
+	}
+
+	os.Exit(code)
+}
+
+func TestRatingReasons(t *testing.T) {
+	caseRepo := repository.InitCaseRepo(db)
+	reasonRepo := repository.InitRatingReasonRepo(db)
+	ctx := context.Background()
+
+	var specialistID int
+	err := db.QueryRowxContext(ctx, `INSERT INTO specialists (login, hashed_password, level, is_verified)
+									 VALUES ('reason', 'hash', 1, true) RETURNING id;`).Scan(&specialistID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Exec("DELETE FROM specialists WHERE id = $1", specialistID)
+
+	if err = reasonRepo.Create(ctx, testRatingReason); err != nil {
+		t.Fatal(err)
+	}
+	defer db.Exec("DELETE FROM rating_reasons WHERE code = $1", testRatingReason.Code)
+
+	err = reasonRepo.Create(ctx, testRatingReason)
+	assert.ErrorIs(t, err, customErrors.UniqueRatingReasonErr)
+
+	caseID, err := caseRepo.CreateCase(ctx, testCase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer caseRepo.DeleteCase(ctx, caseID)
+
+	rated := testRated
+	rated.CaseID, rated.SpecialistID = caseID, specialistID
+
+	rated.ReasonCode = null.StringFrom("test_unknown_reason")
+	_, err = caseRepo.CreateRated(ctx, rated)
+	assert.ErrorIs(t, err, customErrors.NoRowsRatingReasonErr)
+
+	rated.ReasonCode = null.StringFrom(testRatingReason.Code)
+	rated.Comment = null.StringFrom(ratingComment)
+	ratedID, err := caseRepo.CreateRated(ctx, rated)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	votes, err := caseRepo.GetCaseVotes(ctx, caseID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if assert.Len(t, votes, 1) {
+		assert.Equal(t, ratedID, votes[0].RatedID)
+		assert.Equal(t, testRatingReason.Code, votes[0].ReasonCode.String)
+		assert.Equal(t, ratingComment, votes[0].Comment.String)
+	}
+
+	caseFul, err := caseRepo.GetFulCaseByID(ctx, caseID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if assert.NotNil(t, caseFul.RatedCovers) && assert.Len(t, *caseFul.RatedCovers, 1) {
+		cover := (*caseFul.RatedCovers)[0]
+		assert.Equal(t, rated.Choice, cover.Choice)
+		assert.Equal(t, testRatingReason.Code, cover.ReasonCode.String)
+		assert.Equal(t, testRatingReason.Description, cover.Reason.String)
+		assert.Equal(t, ratingComment, cover.Comment.String)
+	}
+
+	// Отключенную причину нельзя указать в новой оценке, но она остается в справочнике
+	err = reasonRepo.Update(ctx, models.RatingReasonUpdate{Code: testRatingReason.Code, IsActive: null.BoolFrom(false)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	active, err := reasonRepo.GetAll(ctx, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, reason := range active {
+		assert.NotEqual(t, testRatingReason.Code, reason.Code)
+	}
+
+	all, err := reasonRepo.GetAll(ctx, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Contains(t, all, models.RatingReason{RatingReasonCreate: testRatingReason, IsActive: false})
+
+	_, err = caseRepo.CreateRated(ctx, rated)
+	assert.ErrorIs(t, err, customErrors.InactiveRatingReasonErr)
+
+	err = reasonRepo.Update(ctx, models.RatingReasonUpdate{Code: "test_unknown_reason", IsActive: null.BoolFrom(true)})
+	assert.ErrorIs(t, err, customErrors.NoRowsRatingReasonErr)
+}
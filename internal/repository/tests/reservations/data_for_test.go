@@ -0,0 +1,54 @@
+package reservations
+
+import (
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/models"
+	"time"
+)
+
+var testCases = []models.CaseBase{
+	{
+		CameraID:       "c70a6f9c-cb9b-4cd9-9425-77e8c8a5b072",
+		Transport:      "A123BC97",
+		ViolationID:    "e2f7e5b2-ea16-4c48-8a8a-5e2d1a2b8410",
+		ViolationValue: "Speeding",
+		Level:          1,
+		Datetime:       time.Now().UTC(),
+		PhotoUrl:       "http://example.com/photo1.jpg",
+	},
+	{
+		CameraID:       "d95a3f0c-cb9b-4cd9-9425-77e8c8a5b072",
+		Transport:      "B234CD98",
+		ViolationID:    "f3a4b5c6-d7e8-9fa0-b1c2-d3e4f5a6b7c8",
+		ViolationValue: "Parking",
+		Level:          1,
+		Datetime:       time.Now().UTC(),
+		PhotoUrl:       "http://example.com/photo2.jpg",
+	},
+	{
+		CameraID:       "a85a4f9c-cb9b-4cd9-9425-77e8c8a5b072",
+		Transport:      "C345DE99",
+		ViolationID:    "a1b2c3d4-e5f6-a7b8-c9d0-e1f2a3b4c5d6",
+		ViolationValue: "Red Light",
+		Level:          1,
+		Datetime:       time.Now().UTC(),
+		PhotoUrl:       "http://example.com/photo3.jpg",
+	},
+}
+
+var testRated = models.RatedBase{
+	RatedCreate: models.RatedCreate{
+		CaseID: 0,
+		Choice: true,
+	},
+	SpecialistID: 3,
+	Date:         time.Now().UTC(),
+	Status:       "Correct",
+}
+
+const reservationTTL = time.Minute
+
+// K оценок на уровне и максимальный уровень случая, который в тесте не достигается
+const (
+	levelK       = 3
+	maxCaseLevel = 5
+)
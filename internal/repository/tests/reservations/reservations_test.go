@@ -0,0 +1,98 @@
+package reservations
+
+import (
+	"context"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/repository"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/repository/tests"
+	customErrors "github.com/gl1n0m3c/IT_LAB_INIT/pkg/utils/customerr"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"log"
+	"os"
+	"testing"
+	"time"
+)
+
+var db *sqlx.DB
+
+func TestMain(m *testing.M) {
+	db = tests.ConnectTestDB()
+	tests.LoadCaseFixtures(db)
+
+	code := m.Run()
+
+	tests.DeleteCaseFixtures(db)
+
+	if err := db.Close(); err != nil {
+		log.Fatalf("Could not close the tests database: %v", err)
+	}
+
+	os.Exit(code)
+}
+
+func TestReserveNextCase(t *testing.T) {
+	caseRepo := repository.InitCaseRepo(db)
+	ctx := context.Background()
+
+	specialistIDs := tests.CreateLevelSpecialists(t, db, "reservation", 2, 1)
+	first, second := specialistIDs[0], specialistIDs[1]
+
+	var caseIDs []int
+	for _, caseData := range testCases {
+		id, err := caseRepo.CreateCase(ctx, caseData)
+		if err != nil {
+			t.Fatal(err)
+		}
+		caseIDs = append(caseIDs, id)
+	}
+	defer func() {
+		for _, id := range caseIDs {
+			caseRepo.DeleteCase(ctx, id)
+		}
+	}()
+
+	reserve := func(specialistID int) int {
+		reservation, err := caseRepo.ReserveNextCase(ctx, specialistID, 1, reservationTTL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.True(t, reservation.ExpiresAt.After(time.Now()))
+		return reservation.ID
+	}
+
+	rate := func(specialistID, caseID int) {
+		rated := testRated
+		rated.CaseID = caseID
+		rated.SpecialistID = specialistID
+		_, err := caseRepo.CreateRatedConsensus(ctx, rated, 1, levelK, maxCaseLevel)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Повторный запрос возвращает тот же случай, другой специалист получает следующий
+	assert.Equal(t, caseIDs[0], reserve(first))
+	assert.Equal(t, caseIDs[0], reserve(first))
+	assert.Equal(t, caseIDs[1], reserve(second))
+
+	// Оценка снимает закрепление, оцененный и чужой случаи пропускаются
+	rate(first, caseIDs[0])
+	var reserved int
+	if err := db.Get(&reserved, `SELECT COUNT(*) FROM case_reservations WHERE specialist_id = $1`, first); err != nil {
+		t.Fatal(err)
+	}
+	assert.Zero(t, reserved)
+	assert.Equal(t, caseIDs[2], reserve(first))
+
+	// Истекшее закрепление не мешает другому специалисту взять случай
+	_, err := db.Exec(`UPDATE case_reservations SET expires_at = NOW() - INTERVAL '1 second' WHERE specialist_id = $1`, second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rate(first, caseIDs[2])
+	assert.Equal(t, caseIDs[1], reserve(first))
+
+	rate(first, caseIDs[1])
+	_, err = caseRepo.ReserveNextCase(ctx, first, 1, reservationTTL)
+	assert.ErrorIs(t, err, customErrors.NoCasesToReserve)
+}
@@ -0,0 +1,36 @@
+package review
+
+import (
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/models"
+	"time"
+)
+
+var testCase = models.CaseBase{
+	CameraID:       "c70a6f9c-cb9b-4cd9-9425-77e8c8a5b072",
+	Transport:      "A123BC97",
+	ViolationID:    "e2f7e5b2-ea16-4c48-8a8a-5e2d1a2b8410",
+	ViolationValue: "Speeding",
+	Level:          1,
+	Datetime:       time.Now().UTC(),
+	PhotoUrl:       "http://example.com/photo1.jpg",
+}
+
+var testRated = models.RatedBase{
+	RatedCreate: models.RatedCreate{
+		CaseID: 0,
+		Choice: true,
+	},
+	SpecialistID: 3,
+	Date:         time.Now().UTC(),
+	Status:       "Correct",
+}
+
+// K оценок на уровне и максимальный уровень случая: в TestOverrideCase не достигается,
+// в TestCaseReview случай первого уровня без консенсуса сразу уходит руководителю
+const (
+	levelK          = 3
+	maxCaseLevel    = 5
+	reviewCaseLevel = 1
+)
+
+const overrideJustification = "На фото видно, что нарушения нет"
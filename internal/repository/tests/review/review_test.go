@@ -0,0 +1,264 @@
+package review
+
+import (
+	"context"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/models"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/repository"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/repository/tests"
+	customErrors "github.com/gl1n0m3c/IT_LAB_INIT/pkg/utils/customerr"
+	"github.com/guregu/null"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"log"
+	"os"
+	"testing"
+	"time"
+)
+
+var db *sqlx.DB
+
+func TestMain(m *testing.M) {
+	db = tests.ConnectTestDB()
+	tests.LoadCaseFixtures(db)
+
+	code := m.Run()
+
+	tests.DeleteCaseFixtures(db)
+
+	if err := db.Close(); err != nil {
+		log.Fatalf("Could not close the tests database: %v", err)
+	}
+
+	os.Exit(code)
+}
+
+func TestCaseReview(t *testing.T) {
+	caseRepo := repository.InitCaseRepo(db)
+	ctx := context.Background()
+
+	var managerID int
+	err := db.QueryRowxContext(ctx, `INSERT INTO managers (login, hashed_password)
+									 VALUES ('review', 'hash') RETURNING id;`).Scan(&managerID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Exec("DELETE FROM managers WHERE login = 'review'")
+
+	specialistIDs := tests.CreateLevelSpecialists(t, db, "review", levelK, 1)
+
+	// Для случая "Speeding" консенсус - единогласие, поэтому разные оценки не закрывают его
+	caseID, err := caseRepo.CreateCase(ctx, testCase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer caseRepo.DeleteCase(ctx, caseID)
+
+	var resolution models.RatedResolution
+	for i, choice := range []bool{true, false, true} {
+		resolution, err = caseRepo.CreateRatedConsensus(ctx, models.RatedBase{
+			RatedCreate:  models.RatedCreate{CaseID: caseID, Choice: choice},
+			SpecialistID: specialistIDs[i],
+			Date:         time.Now().UTC(),
+			Status:       "Unknown",
+		}, 1, levelK, reviewCaseLevel)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	assert.True(t, resolution.Review)
+	assert.False(t, resolution.Solved)
+	assert.False(t, resolution.Escalated)
+
+	caseConsensus, err := caseRepo.GetCaseConsensus(ctx, caseID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, caseConsensus.NeedsReview)
+	assert.Equal(t, reviewCaseLevel, caseConsensus.Level)
+
+	// Случай в очереди руководителя не выдается специалистам
+	_, err = caseRepo.CreateRatedConsensus(ctx, testRated, 1, levelK, reviewCaseLevel)
+	assert.ErrorIs(t, err, customErrors.CaseAlreadySolved)
+
+	reviewCases, err := caseRepo.GetReviewCases(ctx, caseID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if assert.NotEmpty(t, reviewCases.Cases) {
+		assert.Equal(t, caseID, reviewCases.Cases[0].ID)
+	}
+
+	votes, err := caseRepo.GetCaseVotes(ctx, caseID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if assert.Len(t, votes, levelK) {
+		assert.Equal(t, specialistIDs[1], votes[1].SpecialistID)
+		assert.False(t, votes[1].Choice)
+	}
+
+	err = caseRepo.ResolveReviewCase(ctx, managerID, models.CaseReviewDecision{CaseID: caseID, Choice: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	caseConsensus, err = caseRepo.GetCaseConsensus(ctx, caseID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, caseConsensus.IsSolved)
+	assert.False(t, caseConsensus.NeedsReview)
+
+	votes, err = caseRepo.GetCaseVotes(ctx, caseID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "Correct", votes[0].Status)
+	assert.Equal(t, "Incorrect", votes[1].Status)
+
+	err = caseRepo.ResolveReviewCase(ctx, managerID, models.CaseReviewDecision{CaseID: caseID, Choice: true})
+	assert.ErrorIs(t, err, customErrors.CaseNotInReview)
+
+	_, err = caseRepo.GetCaseVotes(ctx, 0)
+	assert.ErrorIs(t, err, customErrors.NoRowsCaseErr)
+}
+
+func TestOverrideCase(t *testing.T) {
+	caseRepo := repository.InitCaseRepo(db)
+	ctx := context.Background()
+
+	var managerID int
+	err := db.QueryRowxContext(ctx, `INSERT INTO managers (login, hashed_password)
+									 VALUES ('override', 'hash') RETURNING id;`).Scan(&managerID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Exec("DELETE FROM managers WHERE login = 'override'")
+
+	specialistIDs := tests.CreateLevelSpecialists(t, db, "override", levelK, 1)
+
+	caseID, err := caseRepo.CreateCase(ctx, testCase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer caseRepo.DeleteCase(ctx, caseID)
+
+	for _, specialistID := range specialistIDs {
+		_, err = caseRepo.CreateRatedConsensus(ctx, models.RatedBase{
+			RatedCreate:  models.RatedCreate{CaseID: caseID, Choice: true},
+			SpecialistID: specialistID,
+			Date:         time.Now().UTC(),
+			Status:       "Unknown",
+		}, 1, levelK, maxCaseLevel)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// checkRows проверяет серии верных оценок и статусы оценок всех специалистов случая
+	checkRows := func(row, currentRow int, status string) {
+		var rows []struct {
+			Row        int    `db:"row"`
+			CurrentRow int    `db:"current_row"`
+			Status     string `db:"status"`
+		}
+		err := db.Select(&rows, `SELECT s.row, s.current_row, rc.status
+								 FROM specialists s
+								 JOIN rated_cases rc ON rc.specialist_id = s.id
+								 WHERE rc.case_id = $1`, caseID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Len(t, rows, levelK)
+		for _, r := range rows {
+			assert.Equal(t, row, r.Row)
+			assert.Equal(t, currentRow, r.CurrentRow)
+			assert.Equal(t, status, r.Status)
+		}
+	}
+	checkRows(1, 1, "Correct")
+
+	override := func(action string) (models.CaseOverride, error) {
+		return caseRepo.OverrideCase(ctx, managerID, models.CaseOverrideCreate{
+			CaseID:        caseID,
+			Action:        action,
+			Justification: overrideJustification,
+		}, maxCaseLevel)
+	}
+
+	// checkNotifications проверяет виды и состояния уведомлений случая в порядке создания
+	checkNotifications := func(expected ...string) {
+		notifications, err := repository.InitNotificationRepo(db).GetByCaseID(ctx, caseID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var actual []string
+		for _, notification := range notifications {
+			actual = append(actual, notification.Kind+":"+notification.Status)
+		}
+		assert.Equal(t, expected, actual)
+	}
+	checkNotifications("fine:pending")
+
+	// Штраф еще не отправлен, поэтому он отменяется, а об отмене нарушителю не сообщается
+	caseOverride, err := override(models.OverrideOverturn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, caseOverride.PreviousChoice)
+	assert.Equal(t, null.BoolFrom(false), caseOverride.Choice)
+	assert.Equal(t, null.IntFrom(int64(managerID)), caseOverride.ManagerID)
+	checkRows(0, 0, "Incorrect")
+	checkNotifications("fine:cancelled")
+
+	caseOverride, err = override(models.OverrideOverturn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.False(t, caseOverride.PreviousChoice)
+	assert.Equal(t, null.BoolFrom(true), caseOverride.Choice)
+	checkRows(1, 1, "Correct")
+	checkNotifications("fine:cancelled", "fine:pending")
+
+	_, err = db.ExecContext(ctx, `UPDATE notifications SET status = 'sent', sent_at = NOW()
+								  WHERE case_id = $1 AND status = 'pending'`, caseID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Возврат на оценку передает случай на следующий уровень, оценки больше не учитываются в сериях
+	caseOverride, err = override(models.OverrideReopen)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, caseOverride.PreviousChoice)
+	assert.False(t, caseOverride.Choice.Valid)
+	checkRows(0, 0, "Unknown")
+	// Отправленный штраф отменяется отдельным уведомлением
+	checkNotifications("fine:cancelled", "fine:sent", "fine_cancel:pending")
+
+	caseConsensus, err := caseRepo.GetCaseConsensus(ctx, caseID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.False(t, caseConsensus.IsSolved)
+	assert.False(t, caseConsensus.NeedsReview)
+	assert.Equal(t, 2, caseConsensus.Level)
+
+	_, err = override(models.OverrideOverturn)
+	assert.ErrorIs(t, err, customErrors.CaseNotSolved)
+
+	caseFul, err := caseRepo.GetFulCaseByID(ctx, caseID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if assert.Len(t, caseFul.Overrides, 3) {
+		assert.Equal(t, models.OverrideReopen, caseFul.Overrides[2].Action)
+		assert.Equal(t, overrideJustification, caseFul.Overrides[2].Justification)
+	}
+
+	_, err = caseRepo.OverrideCase(ctx, managerID, models.CaseOverrideCreate{
+		CaseID: 0, Action: models.OverrideOverturn, Justification: overrideJustification,
+	}, maxCaseLevel)
+	assert.ErrorIs(t, err, customErrors.NoRowsCaseErr)
+}
@@ -0,0 +1,115 @@
+package skips
+
+import (
+	"context"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/models"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/repository"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/repository/tests"
+	customErrors "github.com/gl1n0m3c/IT_LAB_INIT/pkg/utils/customerr"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"log"
+	"os"
+	"testing"
+)
+
+var db *sqlx.DB
+
+func TestMain(m *testing.M) {
+	db = tests.ConnectTestDB()
+	tests.LoadCaseFixtures(db)
+
+	code := m.Run()
+
+	tests.DeleteCaseFixtures(db)
+
+	if err := db.Close(); err != nil {
+		log.Fatalf("Could not close the tests database: %v", err)
+	}
+
+	os.Exit(code)
+}
+
+func TestSkipCase(t *testing.T) {
+	caseRepo := repository.InitCaseRepo(db)
+	ctx := context.Background()
+
+	specialistIDs := tests.CreateLevelSpecialists(t, db, "skip", 2, 1)
+	skipping, rating := specialistIDs[0], specialistIDs[1]
+
+	caseID, err := caseRepo.CreateCase(ctx, testCase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer caseRepo.DeleteCase(ctx, caseID)
+
+	cameraStats := func() models.CameraSkipStats {
+		stats, err := caseRepo.GetCameraSkipStats(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, cameraStat := range stats {
+			if cameraStat.CameraID == testCase.CameraID {
+				return cameraStat
+			}
+		}
+		t.Fatalf("нет статистики камеры %s", testCase.CameraID)
+		return models.CameraSkipStats{}
+	}
+	before := cameraStats()
+
+	skip := models.CaseSkip{
+		CaseSkipCreate: models.CaseSkipCreate{CaseID: caseID, Reason: models.SkipBadPhoto},
+		SpecialistID:   skipping,
+	}
+	skipID, err := caseRepo.SkipCase(ctx, skip, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NotZero(t, skipID)
+
+	_, err = caseRepo.SkipCase(ctx, skip, 1)
+	assert.ErrorIs(t, err, customErrors.UniqueSkipErr)
+
+	// Пропуск не считается оценкой
+	caseConsensus, err := caseRepo.GetCaseConsensus(ctx, caseID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, caseConsensus.Votes)
+
+	// Пропущенный случай больше не выдается специалисту
+	cases, err := caseRepo.GetCasesByLevel(ctx, skipping, 1, caseID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, caseData := range cases.Cases {
+		assert.NotEqual(t, caseID, caseData.ID)
+	}
+
+	reservation, err := caseRepo.ReserveNextCase(ctx, skipping, 1, reservationTTL)
+	if err == nil {
+		assert.NotEqual(t, caseID, reservation.ID)
+		db.Exec("DELETE FROM case_reservations WHERE specialist_id = $1", skipping)
+	} else {
+		assert.ErrorIs(t, err, customErrors.NoCasesToReserve)
+	}
+
+	// Оцененный случай пропустить нельзя
+	rated := testRated
+	rated.CaseID, rated.SpecialistID = caseID, rating
+	if _, err = caseRepo.CreateRatedConsensus(ctx, rated, 1, levelK, maxCaseLevel); err != nil {
+		t.Fatal(err)
+	}
+	_, err = caseRepo.SkipCase(ctx, models.CaseSkip{
+		CaseSkipCreate: models.CaseSkipCreate{CaseID: caseID, Reason: models.SkipWrongViolation},
+		SpecialistID:   rating,
+	}, 1)
+	assert.ErrorIs(t, err, customErrors.UniqueRatedErr)
+
+	after := cameraStats()
+	assert.Equal(t, before.Skips+1, after.Skips)
+	assert.Equal(t, before.SkippedCases+1, after.SkippedCases)
+	assert.Equal(t, before.Reasons[models.SkipBadPhoto]+1, after.Reasons[models.SkipBadPhoto])
+	assert.Equal(t, before.Reasons[models.SkipWrongViolation], after.Reasons[models.SkipWrongViolation])
+}
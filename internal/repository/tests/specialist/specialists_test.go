@@ -135,3 +135,46 @@ func TestCreateGetUpdateDeleteSpecialist(t *testing.T) {
 		assert.NotNil(t, err, "Expected an error when trying to GetByID a deleted specialist with ID %d, but got nil", id)
 	}
 }
+
+func TestUpdateSpecialistsIncDecLevelRollback(t *testing.T) {
+	var createdIDs []int
+
+	specRepo := repository.InitSpecialistsRepo(db)
+	ctx := context.Background()
+
+	for _, specialistCase := range testcaseSpecialistCreate[:2] {
+		id, err := specRepo.Create(ctx, specialistCase)
+		if err != nil {
+			t.Fatalf("Create error: %v", err)
+		}
+		createdIDs = append(createdIDs, id)
+	}
+	defer func() {
+		for _, id := range createdIDs {
+			specRepo.Delete(ctx, id)
+		}
+	}()
+
+	// Понижение несуществующего специалиста должно откатить уже выполненное повышение
+	err := specRepo.UpdateSpecialistsIncDecLevel([]int{createdIDs[0]}, []int{createdIDs[1], 0})
+	assert.Error(t, err)
+
+	for _, id := range createdIDs {
+		specialist, err := specRepo.GetByID(ctx, id)
+		if err != nil {
+			t.Fatalf("GetByID error: %v", err)
+		}
+		assert.Equal(t, 1, specialist.Level, "Level should not change after rollback")
+	}
+
+	err = specRepo.UpdateSpecialistsIncDecLevel(createdIDs, nil)
+	assert.NoError(t, err)
+
+	for _, id := range createdIDs {
+		specialist, err := specRepo.GetByID(ctx, id)
+		if err != nil {
+			t.Fatalf("GetByID error: %v", err)
+		}
+		assert.Equal(t, 2, specialist.Level, "Level should be incremented")
+	}
+}
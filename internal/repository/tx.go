@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/utils"
+	"github.com/jmoiron/sqlx"
+)
+
+type txKey struct{}
+
+// Transactor объединяет вызовы нескольких репозиториев в одну транзакцию.
+// Репозитории, вызванные с контекстом, который получила fn, выполняют запросы в этой транзакции
+type Transactor interface {
+	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type transactor struct {
+	db *sqlx.DB
+}
+
+func InitTransactor(db *sqlx.DB) Transactor {
+	return transactor{db: db}
+}
+
+func (t transactor) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return withTx(ctx, t.db, fn)
+}
+
+// withTx выполняет fn в транзакции, которая передается через контекст. Если в ctx уже есть транзакция,
+// fn выполняется в ней, а фиксирует или откатывает ее тот, кто ее начал.
+// Транзакция откатывается, если fn вернула ошибку или запаниковала
+func withTx(ctx context.Context, db *sqlx.DB, fn func(ctx context.Context) error) (err error) {
+	if _, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return utils.ErrNormalizer(utils.ErrorPair{Message: utils.TransactionErr, Err: err})
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err = fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			// Исходная ошибка оборачивается, чтобы ее можно было проверить через errors.Is
+			return fmt.Errorf("%w, "+utils.RollbackErr, err, rbErr)
+		}
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return utils.ErrNormalizer(utils.ErrorPair{Message: utils.CommitErr, Err: err})
+	}
+
+	return nil
+}
+
+// executor возвращает транзакцию из контекста, а если ее нет - подключение к базе
+func executor(ctx context.Context, db *sqlx.DB) sqlx.ExtContext {
+	if tx, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		return tx
+	}
+	return db
+}
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/models"
+	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/config"
+	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/utils"
+	customErrors "github.com/gl1n0m3c/IT_LAB_INIT/pkg/utils/customerr"
+	"github.com/guregu/null"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/spf13/viper"
+)
+
+type unmatchedCaseRepo struct {
+	db              *sqlx.DB
+	events          CaseEvents
+	casesPerRequest int
+}
+
+func InitUnmatchedCaseRepo(
+	db *sqlx.DB,
+) UnmatchedCases {
+	return unmatchedCaseRepo{
+		db:              db,
+		events:          InitCaseEventRepo(db),
+		casesPerRequest: viper.GetInt(config.EntitiesPerRequest),
+	}
+}
+
+func (u unmatchedCaseRepo) Create(ctx context.Context, caseData models.CaseBase) (int, error) {
+	var createdCaseID int
+
+	caseCreateQuery := `INSERT INTO unmatched_cases (camera_id, transport, violation_id, violation_value, level, datetime, photo_url)
+						VALUES ($1, $2, $3, $4, $5, $6, $7)
+						RETURNING id;`
+
+	err := executor(ctx, u.db).QueryRowxContext(ctx, caseCreateQuery,
+		caseData.CameraID, caseData.Transport, caseData.ViolationID, caseData.ViolationValue,
+		caseData.Level, caseData.Datetime, caseData.PhotoUrl).Scan(&createdCaseID)
+	if err != nil {
+		return 0, utils.ErrNormalizer(utils.ErrorPair{Message: utils.ScanErr, Err: err})
+	}
+
+	return createdCaseID, nil
+}
+
+func (u unmatchedCaseRepo) GetAll(ctx context.Context, cursor int) (models.UnmatchedCaseCursor, error) {
+	var cases []models.UnmatchedCase
+	var nextCursor null.Int
+
+	casesGetQuery := `SELECT id, camera_id, transport, violation_id, violation_value, level, datetime, photo_url, created_at
+					  FROM unmatched_cases
+					  WHERE id >= $1
+					  ORDER BY id LIMIT $2;`
+
+	err := sqlx.SelectContext(ctx, executor(ctx, u.db), &cases, casesGetQuery, cursor, u.casesPerRequest+1)
+	if err != nil {
+		return models.UnmatchedCaseCursor{}, utils.ErrNormalizer(utils.ErrorPair{Message: utils.QueryRrr, Err: err})
+	}
+
+	if len(cases) == u.casesPerRequest+1 {
+		nextCursor = null.IntFrom(int64(cases[len(cases)-1].ID))
+		cases = cases[:len(cases)-1]
+	}
+
+	return models.UnmatchedCaseCursor{Cases: cases, Cursor: nextCursor}, nil
+}
+
+// Promote переносит отложенный случай в cases в одной транзакции: при необходимости создает контакты
+// владельца, сохраняет случай с новым номером транспорта и удаляет его из unmatched_cases
+func (u unmatchedCaseRepo) Promote(ctx context.Context, resolve models.UnmatchedCaseResolve) (int, error) {
+	var caseData models.UnmatchedCase
+	var createdCaseID int
+
+	caseGetQuery := `SELECT id, camera_id, transport, violation_id, violation_value, level, datetime, photo_url, created_at
+					 FROM unmatched_cases
+					 WHERE id = $1
+					 FOR UPDATE;`
+
+	contactCreateQuery := `INSERT INTO contacts (transport, contacts)
+						   VALUES ($1, $2);`
+
+	caseCreateQuery := `INSERT INTO cases (camera_id, transport, violation_id, violation_value, level, current_level, datetime, photo_url)
+						VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+						RETURNING id;`
+
+	caseDeleteQuery := `DELETE FROM unmatched_cases WHERE id = $1;`
+
+	err := withTx(ctx, u.db, func(ctx context.Context) error {
+		tx := executor(ctx, u.db)
+
+		err := tx.QueryRowxContext(ctx, caseGetQuery, resolve.UnmatchedID).StructScan(&caseData)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return customErrors.NoRowsUnmatchedCaseErr
+			}
+			return utils.ErrNormalizer(utils.ErrorPair{Message: utils.ScanErr, Err: err})
+		}
+
+		if resolve.Transport != "" {
+			caseData.Transport = resolve.Transport
+		}
+
+		if resolve.Contacts != nil {
+			userContactsJSON, err := json.Marshal(resolve.Contacts)
+			if err != nil {
+				return err
+			}
+
+			_, err = tx.ExecContext(ctx, contactCreateQuery, caseData.Transport, userContactsJSON)
+			if err != nil {
+				var pqErr *pq.Error
+				if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+					return customErrors.UniqueContactErr
+				}
+				return utils.ErrNormalizer(utils.ErrorPair{Message: utils.ExecErr, Err: err})
+			}
+		}
+
+		err = tx.QueryRowxContext(ctx, caseCreateQuery,
+			caseData.CameraID, caseData.Transport, caseData.ViolationID, caseData.ViolationValue,
+			caseData.Level, caseData.Level, caseData.Datetime, caseData.PhotoUrl).Scan(&createdCaseID)
+		if err != nil {
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) && pqErr.Code == "23503" && pqErr.Constraint == "fk_transport" {
+				return customErrors.NoRowsContactErr
+			}
+			return utils.ErrNormalizer(utils.ErrorPair{Message: utils.ScanErr, Err: err})
+		}
+
+		if _, err = tx.ExecContext(ctx, caseDeleteQuery, resolve.UnmatchedID); err != nil {
+			return utils.ErrNormalizer(utils.ErrorPair{Message: utils.ExecErr, Err: err})
+		}
+
+		return u.events.Create(ctx, models.CaseEventCreate{CaseID: createdCaseID, Type: models.CaseEventCreated})
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return createdCaseID, nil
+}
@@ -1,8 +1,13 @@
 package repository
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"github.com/gl1n0m3c/IT_LAB_INIT/internal/models"
+	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/utils"
+	customErrors "github.com/gl1n0m3c/IT_LAB_INIT/pkg/utils/customerr"
 	"github.com/gofrs/uuid"
 	"github.com/jmoiron/sqlx"
 	"github.com/lib/pq"
@@ -80,3 +85,23 @@ func (v violationRepo) Create(violations []models.Violation) (int, error) {
 
 	return accepted, nil
 }
+
+func (v violationRepo) Get(ctx context.Context, violationID string) (models.Violation, error) {
+	var violation models.Violation
+
+	violationGetQuery := `SELECT type, amount
+						  FROM violations
+						  WHERE id=$1;`
+
+	err := executor(ctx, v.db).QueryRowxContext(ctx, violationGetQuery, violationID).Scan(&violation.Type, &violation.Amount)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return models.Violation{}, customErrors.NoRowsViolationErr
+		default:
+			return models.Violation{}, utils.ErrNormalizer(utils.ErrorPair{Message: utils.ScanErr, Err: err})
+		}
+	}
+
+	return violation, nil
+}
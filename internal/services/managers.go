@@ -7,28 +7,43 @@ import (
 	"github.com/gl1n0m3c/IT_LAB_INIT/internal/repository"
 	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/config"
 	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/log"
+	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/notifier"
+	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/plates"
 	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/utils/responses"
 	"github.com/spf13/viper"
 	"time"
 )
 
 type managerService struct {
-	caseRepo        repository.Cases
-	specialistsRepo repository.Specialists
-	dbResponseTime  time.Duration
-	logger          *log.Logs
+	caseRepo         repository.Cases
+	specialistsRepo  repository.Specialists
+	unmatchedRepo    repository.UnmatchedCases
+	reasonRepo       repository.RatingReasons
+	notificationRepo repository.Notifications
+	maxLevel         int
+	dbResponseTime   time.Duration
+	logger           *log.Logs
 }
 
 func InitManagerService(
 	caseRepo repository.Cases,
 	specialistsRepo repository.Specialists,
+	unmatchedRepo repository.UnmatchedCases,
+	reasonRepo repository.RatingReasons,
+	notificationRepo repository.Notifications,
 	logger *log.Logs,
 ) Managers {
+	dbResponseTime := time.Duration(viper.GetInt(config.DBResponseTime)) * time.Second
+
 	return managerService{
-		caseRepo:        caseRepo,
-		specialistsRepo: specialistsRepo,
-		dbResponseTime:  time.Duration(viper.GetInt(config.DBResponseTime)) * time.Second,
-		logger:          logger,
+		caseRepo:         caseRepo,
+		specialistsRepo:  specialistsRepo,
+		unmatchedRepo:    unmatchedRepo,
+		reasonRepo:       reasonRepo,
+		notificationRepo: notificationRepo,
+		maxLevel:         maxCaseLevel(),
+		dbResponseTime:   dbResponseTime,
+		logger:           logger,
 	}
 }
 
@@ -61,3 +76,268 @@ func (m managerService) GetSpecialistRating(ctx context.Context, timeStart, time
 
 	return specialists, nil
 }
+
+func (m managerService) GetUnmatchedCases(ctx context.Context, cursor int) (models.UnmatchedCaseCursor, error) {
+	ctx, cansel := context.WithTimeout(ctx, m.dbResponseTime)
+	defer cansel()
+
+	cases, err := m.unmatchedRepo.GetAll(ctx, cursor)
+	if err != nil {
+		m.logger.ErrorLogger.Error().Msg(err.Error())
+		return models.UnmatchedCaseCursor{}, err
+	}
+
+	m.logger.InfoLogger.Info().Msg(fmt.Sprintf(responses.ResponseSuccessGet, "unmatched_cases"))
+
+	return cases, nil
+}
+
+// ResolveUnmatchedCase привязывает отложенный случай к существующим или новым контактам
+// и переносит его в cases, после чего случай проходит обычную проверку специалистами
+func (m managerService) ResolveUnmatchedCase(ctx context.Context, resolve models.UnmatchedCaseResolve) (int, error) {
+	ctx, cansel := context.WithTimeout(ctx, m.dbResponseTime)
+	defer cansel()
+
+	if resolve.Transport != "" {
+		transport, err := plates.Normalize(resolve.Transport)
+		if err != nil {
+			return 0, err
+		}
+		resolve.Transport = transport
+	}
+	if len(resolve.Contacts) == 0 {
+		resolve.Contacts = nil
+	}
+
+	createdCaseID, err := m.unmatchedRepo.Promote(ctx, resolve)
+	if err != nil {
+		m.logger.ErrorLogger.Error().Msg(err.Error())
+		return 0, err
+	}
+
+	m.logger.InfoLogger.Info().Msg(fmt.Sprintf(responses.ResponseSuccessCreate, "case", createdCaseID))
+
+	return createdCaseID, nil
+}
+
+func (m managerService) GetReviewCases(ctx context.Context, cursor int) (models.CaseCursor, error) {
+	ctx, cansel := context.WithTimeout(ctx, m.dbResponseTime)
+	defer cansel()
+
+	cases, err := m.caseRepo.GetReviewCases(ctx, cursor)
+	if err != nil {
+		m.logger.ErrorLogger.Error().Msg(err.Error())
+		return models.CaseCursor{}, err
+	}
+
+	m.logger.InfoLogger.Info().Msg(fmt.Sprintf(responses.ResponseSuccessGet, "review_cases"))
+
+	return cases, nil
+}
+
+func (m managerService) GetCaseVotes(ctx context.Context, caseID int) ([]models.CaseVoteFul, error) {
+	ctx, cansel := context.WithTimeout(ctx, m.dbResponseTime)
+	defer cansel()
+
+	votes, err := m.caseRepo.GetCaseVotes(ctx, caseID)
+	if err != nil {
+		m.logger.ErrorLogger.Error().Msg(err.Error())
+		return nil, err
+	}
+
+	m.logger.InfoLogger.Info().Msg(fmt.Sprintf(responses.ResponseSuccessGet, "case_votes"))
+
+	return votes, nil
+}
+
+// ResolveReviewCase закрывает случай решением руководителя и, как при консенсусе специалистов,
+// ставит в очередь уведомление о штрафе, если нарушение подтверждено
+func (m managerService) ResolveReviewCase(ctx context.Context, managerID int, decision models.CaseReviewDecision) error {
+	ctx, cansel := context.WithTimeout(ctx, m.dbResponseTime)
+	defer cansel()
+
+	err := m.caseRepo.ResolveReviewCase(ctx, managerID, decision)
+	if err != nil {
+		m.logger.ErrorLogger.Error().Msg(err.Error())
+		return err
+	}
+
+	m.logger.InfoLogger.Info().Msg(fmt.Sprintf(responses.ResponseSuccessUpdate, "review_case"))
+
+	return nil
+}
+
+// OverrideCase пересматривает закрытый случай. Если нарушение было подтверждено, в очередь ставится
+// уведомление об отмене штрафа, если подтверждено новым решением - уведомление о штрафе
+func (m managerService) OverrideCase(ctx context.Context, managerID int, override models.CaseOverrideCreate) (models.CaseOverride, error) {
+	ctx, cansel := context.WithTimeout(ctx, m.dbResponseTime)
+	defer cansel()
+
+	caseOverride, err := m.caseRepo.OverrideCase(ctx, managerID, override, m.maxLevel)
+	if err != nil {
+		m.logger.ErrorLogger.Error().Msg(err.Error())
+		return models.CaseOverride{}, err
+	}
+
+	m.logger.InfoLogger.Info().Msg(fmt.Sprintf(responses.ResponseSuccessCreate, "case_override", caseOverride.ID))
+
+	return caseOverride, nil
+}
+
+func (m managerService) GetCameraSkipStats(ctx context.Context) ([]models.CameraSkipStats, error) {
+	ctx, cansel := context.WithTimeout(ctx, m.dbResponseTime)
+	defer cansel()
+
+	stats, err := m.caseRepo.GetCameraSkipStats(ctx)
+	if err != nil {
+		m.logger.ErrorLogger.Error().Msg(err.Error())
+		return nil, err
+	}
+
+	m.logger.InfoLogger.Info().Msg(fmt.Sprintf(responses.ResponseSuccessGet, "camera_skip_stats"))
+
+	return stats, nil
+}
+
+func (m managerService) CreateRatingReason(ctx context.Context, reason models.RatingReasonCreate) error {
+	ctx, cansel := context.WithTimeout(ctx, m.dbResponseTime)
+	defer cansel()
+
+	err := m.reasonRepo.Create(ctx, reason)
+	if err != nil {
+		m.logger.ErrorLogger.Error().Msg(err.Error())
+		return err
+	}
+
+	m.logger.InfoLogger.Info().Msg(fmt.Sprintf(responses.ResponseSuccessUpdate, "rating_reason "+reason.Code))
+
+	return nil
+}
+
+func (m managerService) GetRatingReasons(ctx context.Context) ([]models.RatingReason, error) {
+	ctx, cansel := context.WithTimeout(ctx, m.dbResponseTime)
+	defer cansel()
+
+	reasons, err := m.reasonRepo.GetAll(ctx, false)
+	if err != nil {
+		m.logger.ErrorLogger.Error().Msg(err.Error())
+		return nil, err
+	}
+
+	m.logger.InfoLogger.Info().Msg(fmt.Sprintf(responses.ResponseSuccessGet, "rating_reasons"))
+
+	return reasons, nil
+}
+
+func (m managerService) UpdateRatingReason(ctx context.Context, reason models.RatingReasonUpdate) error {
+	ctx, cansel := context.WithTimeout(ctx, m.dbResponseTime)
+	defer cansel()
+
+	err := m.reasonRepo.Update(ctx, reason)
+	if err != nil {
+		m.logger.ErrorLogger.Error().Msg(err.Error())
+		return err
+	}
+
+	m.logger.InfoLogger.Info().Msg(fmt.Sprintf(responses.ResponseSuccessUpdate, "rating_reason"))
+
+	return nil
+}
+
+// CreateControlCase добавляет в очередь специалистов случай с известным решением для проверки их точности
+func (m managerService) CreateControlCase(ctx context.Context, control models.ControlCaseCreate) (int, error) {
+	ctx, cansel := context.WithTimeout(ctx, m.dbResponseTime)
+	defer cansel()
+
+	transport, err := plates.Normalize(control.Transport)
+	if err != nil {
+		return 0, err
+	}
+
+	caseData := models.CaseBase{
+		CameraID:       control.CameraID,
+		Transport:      transport,
+		ViolationID:    control.ViolationID,
+		ViolationValue: control.ViolationValue,
+		Level:          control.Level,
+		CurrentLevel:   control.Level,
+		Datetime:       control.Datetime,
+		PhotoUrl:       control.PhotoUrl,
+	}
+
+	createdCaseID, err := m.caseRepo.CreateControlCase(ctx, caseData, control.Choice)
+	if err != nil {
+		m.logger.ErrorLogger.Error().Msg(err.Error())
+		return 0, err
+	}
+
+	m.logger.InfoLogger.Info().Msg(fmt.Sprintf(responses.ResponseSuccessCreate, "control_case", createdCaseID))
+
+	return createdCaseID, nil
+}
+
+func (m managerService) GetDeadNotifications(ctx context.Context, cursor int) (models.NotificationCursor, error) {
+	ctx, cansel := context.WithTimeout(ctx, m.dbResponseTime)
+	defer cansel()
+
+	notifications, err := m.notificationRepo.GetDead(ctx, cursor)
+	if err != nil {
+		m.logger.ErrorLogger.Error().Msg(err.Error())
+		return models.NotificationCursor{}, err
+	}
+
+	m.logger.InfoLogger.Info().Msg(fmt.Sprintf(responses.ResponseSuccessGet, "dead_notifications"))
+
+	return notifications, nil
+}
+
+// RetryNotification возвращает в очередь уведомление, которое не удалось отправить за все попытки
+func (m managerService) RetryNotification(ctx context.Context, notificationID int) error {
+	ctx, cansel := context.WithTimeout(ctx, m.dbResponseTime)
+	defer cansel()
+
+	err := m.notificationRepo.Retry(ctx, notificationID)
+	if err != nil {
+		m.logger.ErrorLogger.Error().Msg(err.Error())
+		return err
+	}
+
+	m.logger.InfoLogger.Info().Msg(fmt.Sprintf(responses.ResponseSuccessUpdate, "notification"))
+
+	return nil
+}
+
+// PreviewNotification составляет уведомление kind по случаю так же, как при отправке, но не отправляет его.
+// Если language не указан, используется язык владельца транспорта
+func (m managerService) PreviewNotification(ctx context.Context, caseID int, kind, language string) (models.NotificationPreview, error) {
+	ctx, cansel := context.WithTimeout(ctx, m.dbResponseTime)
+	defer cansel()
+
+	fineData, err := m.caseRepo.GetFineData(ctx, caseID)
+	if err != nil {
+		m.logger.ErrorLogger.Error().Msg(err.Error())
+		return models.NotificationPreview{}, err
+	}
+
+	if language == "" {
+		language = notifier.Language(fineData.Contacts)
+	}
+
+	message, err := notifier.ComposeIn(kind, language, fineData)
+	if err != nil {
+		m.logger.ErrorLogger.Error().Msg(err.Error())
+		return models.NotificationPreview{}, err
+	}
+
+	m.logger.InfoLogger.Info().Msg(fmt.Sprintf(responses.ResponseSuccessGet, "notification_preview"))
+
+	return models.NotificationPreview{
+		CaseID:   caseID,
+		Kind:     message.Kind,
+		Language: message.Language,
+		Subject:  message.Subject,
+		Text:     message.Body,
+		HTML:     message.HTML,
+		Photo:    message.Photo,
+	}, nil
+}
@@ -2,12 +2,14 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/gl1n0m3c/IT_LAB_INIT/internal/models"
 	"github.com/gl1n0m3c/IT_LAB_INIT/internal/repository"
 	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/config"
 	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/log"
 	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/utils"
+	customErrors "github.com/gl1n0m3c/IT_LAB_INIT/pkg/utils/customerr"
 	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/utils/responses"
 	"github.com/spf13/viper"
 	"time"
@@ -18,6 +20,10 @@ type publicService struct {
 	specialistRepo repository.Specialists
 	cameraRepo     repository.Cameras
 	caseRepo       repository.Cases
+	violationRepo  repository.Violations
+	contactRepo    repository.Contacts
+	unmatchedRepo  repository.UnmatchedCases
+	transactor     repository.Transactor
 	dbResponseTime time.Duration
 	logger         *log.Logs
 }
@@ -27,6 +33,10 @@ func InitPublicService(
 	specialistRepo repository.Specialists,
 	cameraRepo repository.Cameras,
 	caseRepo repository.Cases,
+	violationRepo repository.Violations,
+	contactRepo repository.Contacts,
+	unmatchedRepo repository.UnmatchedCases,
+	transactor repository.Transactor,
 	logger *log.Logs,
 ) Public {
 	return publicService{
@@ -34,6 +44,10 @@ func InitPublicService(
 		specialistRepo: specialistRepo,
 		cameraRepo:     cameraRepo,
 		caseRepo:       caseRepo,
+		violationRepo:  violationRepo,
+		contactRepo:    contactRepo,
+		unmatchedRepo:  unmatchedRepo,
+		transactor:     transactor,
 		dbResponseTime: time.Duration(viper.GetInt(config.DBResponseTime)) * time.Second,
 		logger:         logger,
 	}
@@ -111,18 +125,92 @@ func (p publicService) CameraDelete(ctx context.Context, cameraID string) error
 	return nil
 }
 
-func (p publicService) CaseCreate(ctx context.Context, caseData models.CaseBase) (int, error) {
+func (p publicService) CaseCreate(ctx context.Context, caseData models.CaseBase) (models.CaseCreated, error) {
 	ctx, cansel := context.WithTimeout(ctx, p.dbResponseTime)
 	defer cansel()
 
+	err := p.validateCaseReferences(ctx, caseData)
+	if onlyTransportMissing(err) {
+		return p.caseParkUnmatched(ctx, caseData)
+	}
+	if err != nil {
+		p.logger.ErrorLogger.Error().Msg(err.Error())
+		return models.CaseCreated{}, err
+	}
+
 	createdCaseID, err := p.caseRepo.CreateCase(ctx, caseData)
 	if err != nil {
 		p.logger.ErrorLogger.Error().Msg(err.Error())
-		return 0, err
+		return models.CaseCreated{}, err
 	}
 
 	p.logger.InfoLogger.Info().Msg(fmt.Sprintf(responses.ResponseSuccessCreate, "case", createdCaseID))
-	return createdCaseID, nil
+	return models.CaseCreated{ID: createdCaseID}, nil
+}
+
+func (p publicService) CaseCreateBatch(ctx context.Context, cases []models.CaseBase) ([]models.CaseCreated, []error, error) {
+	ctx, cansel := context.WithTimeout(ctx, p.dbResponseTime)
+	defer cansel()
+
+	created := make([]models.CaseCreated, len(cases))
+	errs := make([]error, len(cases))
+	var validIndexes []int
+
+	// Отложенные и созданные случаи пакета фиксируются одной транзакцией:
+	// при ошибке базы не остается частично загруженного пакета
+	err := p.transactor.WithTx(ctx, func(ctx context.Context) error {
+		// В репозиторий передаются только случаи, все ссылки которых существуют,
+		// случаи без контактов владельца откладываются
+		var validCases []models.CaseBase
+		for i, caseData := range cases {
+			err := p.validateCaseReferences(ctx, caseData)
+			var refErr customErrors.MissingReferencesErr
+			switch {
+			case onlyTransportMissing(err):
+				created[i], err = p.caseParkUnmatched(ctx, caseData)
+				if err != nil {
+					return err
+				}
+			case errors.As(err, &refErr):
+				errs[i] = err
+				p.logger.ErrorLogger.Error().Msg(err.Error())
+			case err != nil:
+				return err
+			default:
+				validCases = append(validCases, caseData)
+				validIndexes = append(validIndexes, i)
+			}
+		}
+
+		if len(validCases) == 0 {
+			return nil
+		}
+
+		createdIDs, createErrs, err := p.caseRepo.CreateCases(ctx, validCases)
+		if err != nil {
+			return err
+		}
+
+		for j, i := range validIndexes {
+			created[i].ID = createdIDs[j]
+			errs[i] = createErrs[j]
+		}
+		return nil
+	})
+	if err != nil {
+		p.logger.ErrorLogger.Error().Msg(err.Error())
+		return nil, nil, err
+	}
+
+	for _, i := range validIndexes {
+		if errs[i] != nil {
+			p.logger.ErrorLogger.Error().Msg(errs[i].Error())
+			continue
+		}
+		p.logger.InfoLogger.Info().Msg(fmt.Sprintf(responses.ResponseSuccessCreate, "case", created[i].ID))
+	}
+
+	return created, errs, nil
 }
 
 func (p publicService) CaseDelete(ctx context.Context, caseID int) error {
@@ -138,3 +226,67 @@ func (p publicService) CaseDelete(ctx context.Context, caseID int) error {
 	p.logger.InfoLogger.Info().Msg(fmt.Sprintf(responses.ResponseSuccessDelete, "camera", caseID))
 	return nil
 }
+
+// caseParkUnmatched откладывает случай, для транспорта которого нет контактов владельца,
+// чтобы менеджер мог привязать или создать контакты
+func (p publicService) caseParkUnmatched(ctx context.Context, caseData models.CaseBase) (models.CaseCreated, error) {
+	unmatchedCaseID, err := p.unmatchedRepo.Create(ctx, caseData)
+	if err != nil {
+		p.logger.ErrorLogger.Error().Msg(err.Error())
+		return models.CaseCreated{}, err
+	}
+
+	p.logger.InfoLogger.Info().Msg(fmt.Sprintf(responses.ResponseSuccessCreate, "unmatched_case", unmatchedCaseID))
+	return models.CaseCreated{ID: unmatchedCaseID, Unmatched: true}, nil
+}
+
+// onlyTransportMissing сообщает, что из ссылок случая отсутствуют только контакты владельца транспорта
+func onlyTransportMissing(err error) bool {
+	var refErr customErrors.MissingReferencesErr
+	if !errors.As(err, &refErr) {
+		return false
+	}
+
+	for _, reference := range refErr.References {
+		if reference.Entity != customErrors.EntityTransport {
+			return false
+		}
+	}
+	return len(refErr.References) > 0
+}
+
+// validateCaseReferences проверяет, что камера, нарушение и транспорт случая существуют,
+// и возвращает MissingReferencesErr со всеми отсутствующими сущностями
+func (p publicService) validateCaseReferences(ctx context.Context, caseData models.CaseBase) error {
+	var missing []customErrors.MissingReference
+
+	_, err := p.cameraRepo.Get(ctx, caseData.CameraID)
+	switch {
+	case errors.Is(err, customErrors.NoRowsCameraErr):
+		missing = append(missing, customErrors.MissingReference{Entity: customErrors.EntityCamera, ID: caseData.CameraID})
+	case err != nil:
+		return err
+	}
+
+	_, err = p.violationRepo.Get(ctx, caseData.ViolationID)
+	switch {
+	case errors.Is(err, customErrors.NoRowsViolationErr):
+		missing = append(missing, customErrors.MissingReference{Entity: customErrors.EntityViolation, ID: caseData.ViolationID})
+	case err != nil:
+		return err
+	}
+
+	_, err = p.contactRepo.Get(ctx, caseData.Transport)
+	switch {
+	case errors.Is(err, customErrors.NoRowsContactErr):
+		missing = append(missing, customErrors.MissingReference{Entity: customErrors.EntityTransport, ID: caseData.Transport})
+	case err != nil:
+		return err
+	}
+
+	if len(missing) > 0 {
+		return customErrors.MissingReferencesErr{References: missing}
+	}
+
+	return nil
+}
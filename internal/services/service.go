@@ -9,6 +9,26 @@ import (
 type Managers interface {
 	GetFulCaseByID(ctx context.Context, caseID int) (models.CaseFul, error)
 	GetSpecialistRating(ctx context.Context, timeStart, timeEnd time.Time, cursor int) (models.RatingSpecialistCountCursor, error)
+
+	GetUnmatchedCases(ctx context.Context, cursor int) (models.UnmatchedCaseCursor, error)
+	ResolveUnmatchedCase(ctx context.Context, resolve models.UnmatchedCaseResolve) (int, error)
+
+	GetReviewCases(ctx context.Context, cursor int) (models.CaseCursor, error)
+	GetCaseVotes(ctx context.Context, caseID int) ([]models.CaseVoteFul, error)
+	ResolveReviewCase(ctx context.Context, managerID int, decision models.CaseReviewDecision) error
+	OverrideCase(ctx context.Context, managerID int, override models.CaseOverrideCreate) (models.CaseOverride, error)
+
+	GetCameraSkipStats(ctx context.Context) ([]models.CameraSkipStats, error)
+
+	CreateRatingReason(ctx context.Context, reason models.RatingReasonCreate) error
+	GetRatingReasons(ctx context.Context) ([]models.RatingReason, error)
+	UpdateRatingReason(ctx context.Context, reason models.RatingReasonUpdate) error
+
+	CreateControlCase(ctx context.Context, control models.ControlCaseCreate) (int, error)
+
+	GetDeadNotifications(ctx context.Context, cursor int) (models.NotificationCursor, error)
+	RetryNotification(ctx context.Context, notificationID int) error
+	PreviewNotification(ctx context.Context, caseID int, kind, language string) (models.NotificationPreview, error)
 }
 
 type Public interface {
@@ -20,7 +40,8 @@ type Public interface {
 	CameraCreate(ctx context.Context, camera models.CameraBase) (string, error)
 	CameraDelete(ctx context.Context, cameraID string) error
 
-	CaseCreate(ctx context.Context, caseData models.CaseBase) (int, error)
+	CaseCreate(ctx context.Context, caseData models.CaseBase) (models.CaseCreated, error)
+	CaseCreateBatch(ctx context.Context, cases []models.CaseBase) ([]models.CaseCreated, []error, error)
 	CaseDelete(ctx context.Context, caseID int) error
 }
 
@@ -30,7 +51,10 @@ type Specialists interface {
 
 	GetRating(ctx context.Context) ([]models.RatingSpecialistFul, error)
 	GetCasesByLevel(ctx context.Context, specialistID, cursor int) (models.CaseCursor, error)
+	TakeNextCase(ctx context.Context, specialistID int) (models.CaseReservation, error)
 
+	GetRatingReasons(ctx context.Context) ([]models.RatingReason, error)
 	CreateRated(ctx context.Context, rated models.RatedBase) (int, error)
+	SkipCase(ctx context.Context, skip models.CaseSkip) (int, error)
 	GetRatedSolved(ctx context.Context, specialistID, cursor int) (models.RatedCursor, error)
 }
@@ -2,12 +2,12 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/gl1n0m3c/IT_LAB_INIT/internal/models"
 	"github.com/gl1n0m3c/IT_LAB_INIT/internal/repository"
 	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/config"
 	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/log"
-	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/sender"
 	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/utils"
 	customErrors "github.com/gl1n0m3c/IT_LAB_INIT/pkg/utils/customerr"
 	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/utils/responses"
@@ -20,7 +20,10 @@ import (
 type specialistService struct {
 	specialistRepo repository.Specialists
 	caseRepo       repository.Cases
+	reasonRepo     repository.RatingReasons
 	k              int
+	maxLevel       int
+	reservationTTL time.Duration
 	dbResponseTime time.Duration
 	logger         *log.Logs
 }
@@ -28,17 +31,44 @@ type specialistService struct {
 func InitSpecialistService(
 	specialistRepo repository.Specialists,
 	caseRepo repository.Cases,
+	reasonRepo repository.RatingReasons,
 	logger *log.Logs,
 ) Specialists {
+	dbResponseTime := time.Duration(viper.GetInt(config.DBResponseTime)) * time.Second
+
 	return specialistService{
 		specialistRepo: specialistRepo,
 		caseRepo:       caseRepo,
+		reasonRepo:     reasonRepo,
 		k:              viper.GetInt(config.K),
-		dbResponseTime: time.Duration(viper.GetInt(config.DBResponseTime)) * time.Second,
+		maxLevel:       maxCaseLevel(),
+		reservationTTL: reservationTTL(),
+		dbResponseTime: dbResponseTime,
 		logger:         logger,
 	}
 }
 
+const (
+	defaultCaseReservationTTL = 15 * time.Minute
+	defaultMaxCaseLevel       = 5
+)
+
+func maxCaseLevel() int {
+	maxLevel := viper.GetInt(config.MaxCaseLevel)
+	if maxLevel <= 0 {
+		return defaultMaxCaseLevel
+	}
+	return maxLevel
+}
+
+func reservationTTL() time.Duration {
+	ttl := time.Duration(viper.GetInt(config.CaseReservationTTL)) * time.Minute
+	if ttl <= 0 {
+		return defaultCaseReservationTTL
+	}
+	return ttl
+}
+
 func (s specialistService) GetMe(ctx context.Context, specialistID int) (models.Specialist, error) {
 	ctx, cansel := context.WithTimeout(ctx, s.dbResponseTime)
 	defer cansel()
@@ -130,79 +160,79 @@ func (s specialistService) CreateRated(ctx context.Context, rated models.RatedBa
 		return 0, customErrors.UserUnverified
 	}
 
-	caseCtx1, caseCansel1 := context.WithTimeout(ctx, s.dbResponseTime)
-	defer caseCansel1()
+	caseCtx, caseCansel := context.WithTimeout(ctx, s.dbResponseTime)
+	defer caseCansel()
 
-	// Проверка, что уровень специалиста совпадает с уровнем кейса + кейс еще не разрешен + проверка на количество оценок
-	level, numberOfRated, numberOfTrue, isSolved, err := s.caseRepo.GetCaseLevelSolvedRatingsTrueByID(caseCtx1, rated.CaseID)
+	// Проверка уровня и статуса случая, сохранение оценки, проверка на консенсус и постановка уведомления
+	// о штрафе в очередь выполняются в одной транзакции
+	resolution, err := s.caseRepo.CreateRatedConsensus(caseCtx, rated, specialist.Level, s.k, s.maxLevel)
 	if err != nil {
-		s.logger.ErrorLogger.Error().Msg(err.Error())
+		switch {
+		case errors.Is(err, customErrors.CaseAlreadySolved), errors.Is(err, customErrors.UserBadLevel),
+			errors.Is(err, customErrors.NoRowsRatingReasonErr), errors.Is(err, customErrors.InactiveRatingReasonErr):
+			s.logger.ErrorLogger.Info().Msg(err.Error())
+		default:
+			s.logger.ErrorLogger.Error().Msg(err.Error())
+		}
 		return 0, err
 	}
-	if isSolved || numberOfRated >= s.k {
-		s.logger.ErrorLogger.Info().Msg(customErrors.CaseAlreadySolved.Error())
-		return 0, customErrors.CaseAlreadySolved
-	}
-	if level != specialist.Level {
-		s.logger.ErrorLogger.Info().Msg(customErrors.UserBadLevel.Error())
-		return 0, customErrors.UserBadLevel
-	}
+	createdRatedID := resolution.RatedID
 
-	caseCtx2, caseCansel2 := context.WithTimeout(ctx, s.dbResponseTime)
-	defer caseCansel2()
+	s.logger.InfoLogger.Info().Msg(fmt.Sprintf(responses.ResponseSuccessCreate, "rated_case", createdRatedID))
 
-	createdRatedID, err := s.caseRepo.CreateRated(caseCtx2, rated)
+	return createdRatedID, nil
+}
+
+// GetRatingReasons возвращает причины, которые можно указать в оценке
+func (s specialistService) GetRatingReasons(ctx context.Context) ([]models.RatingReason, error) {
+	ctx, cansel := context.WithTimeout(ctx, s.dbResponseTime)
+	defer cansel()
+
+	reasons, err := s.reasonRepo.GetAll(ctx, true)
 	if err != nil {
 		s.logger.ErrorLogger.Error().Msg(err.Error())
-		return 0, err
+		return nil, err
 	}
 
-	// Проверка на консенсус
-	if s.k-1 == numberOfRated {
-		if (numberOfRated == numberOfTrue && rated.Choice) || (numberOfTrue == 0 && !rated.Choice) {
-			var rightChoice bool
-			if numberOfRated == numberOfTrue {
-				rightChoice = true
-			}
+	s.logger.InfoLogger.Info().Msg(fmt.Sprintf(responses.ResponseSuccessGet, "rating_reasons"))
 
-			updateCtx, updateCansel := context.WithTimeout(ctx, s.dbResponseTime)
-			defer updateCansel()
-
-			err := s.caseRepo.UpdateCaseSetSolved(updateCtx, rated.CaseID, rightChoice)
-			if err != nil {
-				s.logger.ErrorLogger.Error().Msg(err.Error())
-				return 0, err
-			}
+	return reasons, nil
+}
 
-			fineDataCtx, fineDataCansel := context.WithTimeout(ctx, s.dbResponseTime)
-			defer fineDataCansel()
+// SkipCase сохраняет пропуск случая, который специалист не может оценить. Пропуск не влияет на консенсус и рейтинг
+func (s specialistService) SkipCase(ctx context.Context, skip models.CaseSkip) (int, error) {
+	specCtx, specCansel := context.WithTimeout(ctx, s.dbResponseTime)
+	defer specCansel()
 
-			fineData, err := s.caseRepo.GetFineData(fineDataCtx, rated.CaseID)
-			if err != nil {
-				s.logger.ErrorLogger.Error().Msg(err.Error())
-				return 0, err
-			}
+	// Проверка, что аккаунт специалиста подтвержден
+	specialist, err := s.specialistRepo.GetByID(specCtx, skip.SpecialistID)
+	if err != nil {
+		s.logger.ErrorLogger.Error().Msg(err.Error())
+		return 0, err
+	}
+	if !specialist.IsVerified {
+		s.logger.ErrorLogger.Info().Msg(customErrors.UserUnverified.Error())
+		return 0, customErrors.UserUnverified
+	}
 
-			err = sender.MailSender(fineData)
-			if err != nil {
-				s.logger.ErrorLogger.Error().Msg(err.Error())
-				return 0, err
-			}
-		} else {
-			updateCtx, updateCansel := context.WithTimeout(ctx, s.dbResponseTime)
-			defer updateCansel()
+	ctx, cansel := context.WithTimeout(ctx, s.dbResponseTime)
+	defer cansel()
 
-			err := s.caseRepo.UpdateCaseLevel(updateCtx, rated.CaseID, level+1)
-			if err != nil {
-				s.logger.ErrorLogger.Error().Msg(err.Error())
-				return 0, err
-			}
+	createdSkipID, err := s.caseRepo.SkipCase(ctx, skip, specialist.Level)
+	if err != nil {
+		switch {
+		case errors.Is(err, customErrors.CaseAlreadySolved), errors.Is(err, customErrors.UserBadLevel),
+			errors.Is(err, customErrors.UniqueRatedErr), errors.Is(err, customErrors.UniqueSkipErr):
+			s.logger.ErrorLogger.Info().Msg(err.Error())
+		default:
+			s.logger.ErrorLogger.Error().Msg(err.Error())
 		}
+		return 0, err
 	}
 
-	s.logger.InfoLogger.Info().Msg(fmt.Sprintf(responses.ResponseSuccessCreate, "rated_case", createdRatedID))
+	s.logger.InfoLogger.Info().Msg(fmt.Sprintf(responses.ResponseSuccessCreate, "case_skip", createdSkipID))
 
-	return createdRatedID, nil
+	return createdSkipID, nil
 }
 
 func (s specialistService) GetCasesByLevel(ctx context.Context, specialistID, cursor int) (models.CaseCursor, error) {
@@ -235,6 +265,39 @@ func (s specialistService) GetCasesByLevel(ctx context.Context, specialistID, cu
 	return cases, nil
 }
 
+func (s specialistService) TakeNextCase(ctx context.Context, specialistID int) (models.CaseReservation, error) {
+	specCtx, specCansel := context.WithTimeout(ctx, s.dbResponseTime)
+	defer specCansel()
+
+	// Проверка, что аккаунт специалиста подтвержден
+	specialist, err := s.specialistRepo.GetByID(specCtx, specialistID)
+	if err != nil {
+		s.logger.ErrorLogger.Error().Msg(err.Error())
+		return models.CaseReservation{}, err
+	}
+	if !specialist.IsVerified {
+		s.logger.ErrorLogger.Info().Msg(customErrors.UserUnverified.Error())
+		return models.CaseReservation{}, customErrors.UserUnverified
+	}
+
+	ctx, cansel := context.WithTimeout(ctx, s.dbResponseTime)
+	defer cansel()
+
+	reservation, err := s.caseRepo.ReserveNextCase(ctx, specialist.ID, specialist.Level, s.reservationTTL)
+	if err != nil {
+		if errors.Is(err, customErrors.NoCasesToReserve) {
+			s.logger.ErrorLogger.Info().Msg(err.Error())
+		} else {
+			s.logger.ErrorLogger.Error().Msg(err.Error())
+		}
+		return models.CaseReservation{}, err
+	}
+
+	s.logger.InfoLogger.Info().Msg(fmt.Sprintf(responses.ResponseSuccessGet, "case_reservation"))
+
+	return reservation, nil
+}
+
 func (s specialistService) GetRatedSolved(ctx context.Context, specialistID, cursor int) (models.RatedCursor, error) {
 	specCtx, specCansel := context.WithTimeout(ctx, s.dbResponseTime)
 	defer specCansel()
@@ -30,11 +30,35 @@ const (
 
 	EntitiesPerRequest = "ENTITIES_PER_REQUEST"
 
+	DecoderMaxDepth = "DECODER_MAX_DEPTH"
+	DecoderMaxSize  = "DECODER_MAX_SIZE"
+	DecoderMaxKeys  = "DECODER_MAX_KEYS"
+
+	BatchMaxItems = "BATCH_MAX_ITEMS"
+
+	CaseReservationTTL = "CASE_RESERVATION_TTL"
+	MaxCaseLevel       = "MAX_CASE_LEVEL"
+
+	NotifyInterval      = "NOTIFY_INTERVAL"
+	NotifyBatchSize     = "NOTIFY_BATCH_SIZE"
+	NotifyMaxAttempts   = "NOTIFY_MAX_ATTEMPTS"
+	NotifyRetryDelay    = "NOTIFY_RETRY_DELAY"
+	NotifyMaxRetryDelay = "NOTIFY_MAX_RETRY_DELAY"
+	NotifyChannels      = "NOTIFY_CHANNELS"
+	NotifyLanguage      = "NOTIFY_LANGUAGE"
+
 	Mail         = "MAIL"
 	MailPassword = "MAIL_PASSWORD"
 	MailHost     = "MAIL_HOST"
 	MailPort     = "MAIL_PORT"
 
+	SMSGatewayURL   = "SMS_GATEWAY_URL"
+	SMSGatewayToken = "SMS_GATEWAY_TOKEN"
+
+	WebhookURL        = "WEBHOOK_URL"
+	WebhookToken      = "WEBHOOK_TOKEN"
+	WebhookContactKey = "WEBHOOK_CONTACT_KEY"
+
 	JaegerHost = "JAEGER_HOST"
 	JaegerPort = "JAEGER_PORT"
 )
@@ -0,0 +1,38 @@
+package notifier
+
+import (
+	"context"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/models"
+	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/sender"
+)
+
+// EmailNotifier отправляет уведомление письмом с текстовой и HTML версиями и фото нарушения через почтовый сервер из конфига
+type EmailNotifier struct{}
+
+func InitEmailNotifier() EmailNotifier {
+	return EmailNotifier{}
+}
+
+func (e EmailNotifier) Channel() string {
+	return models.ChannelEmail
+}
+
+func (e EmailNotifier) ContactKey() string {
+	return models.ContactEmail
+}
+
+func (e EmailNotifier) Notify(_ context.Context, recipient string, message Message) error {
+	sender.InitEmailConfig()
+
+	m := sender.NewMessage(message.Subject, message.Body)
+	m.HTML = message.HTML
+	m.To = []string{recipient}
+
+	if message.Photo != "" {
+		if err := m.AttachFile(".." + message.Photo); err != nil {
+			return err
+		}
+	}
+
+	return sender.New().Send(m)
+}
@@ -0,0 +1,42 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Время ожидания ответа шлюза SMS и вебхука
+const httpTimeout = 10 * time.Second
+
+// postJSON отправляет payload в формате JSON и считает ошибкой любой ответ, кроме 2xx
+func postJSON(ctx context.Context, client *http.Client, url, token string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s ответил со статусом %d", url, resp.StatusCode)
+	}
+
+	return nil
+}
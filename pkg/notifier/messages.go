@@ -0,0 +1,227 @@
+package notifier
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/models"
+	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/config"
+	customErrors "github.com/gl1n0m3c/IT_LAB_INIT/pkg/utils/customerr"
+	"github.com/spf13/viper"
+	htmlTemplate "html/template"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	textTemplate "text/template"
+)
+
+// Шаблоны уведомлений лежат в templates/<язык>/<вид уведомления>/<тип нарушения>.txt и .html.
+// Текстовый шаблон задает и тему письма в блоке subject. Если для типа нарушения нет своего шаблона,
+// используется default, если нет шаблонов на языке получателя - язык по умолчанию
+//
+//go:embed templates
+var templates embed.FS
+
+const (
+	defaultLanguage = "ru"
+	defaultTemplate = "default"
+)
+
+// locale - правила форматирования данных штрафа для языка
+type locale struct {
+	dateLayout string
+}
+
+var locales = map[string]locale{
+	"ru": {dateLayout: "02.01.2006 15:04 MST"},
+	"en": {dateLayout: "January 2, 2006, 15:04 MST"},
+}
+
+// Message - уведомление нарушителю. Body - текст сообщения, HTML - его версия для писем,
+// Photo - путь к фото нарушения, который прикладывается к письму, Fine - данные штрафа, по которым составлено сообщение
+type Message struct {
+	Kind     string
+	Language string
+	Subject  string
+	Body     string
+	HTML     string
+	Photo    string
+	Fine     models.FineData
+}
+
+// templateData - данные штрафа, подготовленные для шаблона
+type templateData struct {
+	Kind           string
+	ViolationType  string
+	ViolationValue string
+	Amount         int
+	Date           string
+	Coordinates    string
+	MapURL         string
+	HasPhoto       bool
+}
+
+// Languages возвращает языки, на которых есть шаблоны уведомлений
+func Languages() []string {
+	languages := make([]string, 0, len(locales))
+	for language := range locales {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// Language выбирает язык уведомления: язык из контактов владельца, если для него есть шаблоны,
+// иначе NOTIFY_LANGUAGE, а если он не задан - русский
+func Language(contacts map[string]string) string {
+	if language := strings.ToLower(strings.TrimSpace(contacts[models.ContactLanguage])); language != "" {
+		if _, ok := locales[language]; ok {
+			return language
+		}
+	}
+
+	if language := viper.GetString(config.NotifyLanguage); language != "" {
+		if _, ok := locales[language]; ok {
+			return language
+		}
+	}
+
+	return defaultLanguage
+}
+
+// Compose составляет сообщение вида kind по данным штрафа на языке владельца транспорта
+func Compose(kind string, fineData models.FineData) (Message, error) {
+	return ComposeIn(kind, Language(fineData.Contacts), fineData)
+}
+
+// ComposeIn составляет сообщение вида kind по данным штрафа на языке language
+func ComposeIn(kind, language string, fineData models.FineData) (Message, error) {
+	loc, ok := locales[language]
+	if !ok {
+		return Message{}, fmt.Errorf("%w: %s", customErrors.UnknownLanguageErr, language)
+	}
+	if kind != models.NotificationFine && kind != models.NotificationFineCancel {
+		return Message{}, fmt.Errorf("%w: %s", customErrors.UnknownNotificationKindErr, kind)
+	}
+
+	message := Message{Kind: kind, Language: language, Fine: fineData}
+	if kind == models.NotificationFine {
+		message.Photo = fineData.PhotoUrl
+	}
+
+	data := templateData{
+		Kind:           kind,
+		ViolationType:  fineData.Violation.Type,
+		ViolationValue: fineData.ViolationValue,
+		Amount:         fineData.Violation.Amount,
+		Date:           fineData.Date.Format(loc.dateLayout),
+		HasPhoto:       message.Photo != "",
+	}
+	data.Coordinates, data.MapURL = formatCoordinates(fineData.Coordinated)
+
+	textPath, htmlPath := templatePaths(kind, language, fineData.Violation.Type)
+
+	text, err := textTemplate.ParseFS(templates, textPath)
+	if err != nil {
+		return Message{}, err
+	}
+
+	var subject, body bytes.Buffer
+	if err = text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return Message{}, err
+	}
+	if err = text.Execute(&body, data); err != nil {
+		return Message{}, err
+	}
+
+	html, err := htmlTemplate.ParseFS(templates, htmlPath)
+	if err != nil {
+		return Message{}, err
+	}
+
+	var htmlBody bytes.Buffer
+	if err = html.Execute(&htmlBody, data); err != nil {
+		return Message{}, err
+	}
+
+	message.Subject = strings.TrimSpace(subject.String())
+	message.Body = strings.TrimSpace(body.String())
+	message.HTML = htmlBody.String()
+
+	return message, nil
+}
+
+// templatePaths находит шаблоны для типа нарушения, а если их нет - шаблоны по умолчанию.
+// Если на языке language нет шаблонов этого вида, используются шаблоны языка по умолчанию
+func templatePaths(kind, language, violationType string) (string, string) {
+	name := templateName(violationType)
+
+	for _, lang := range []string{language, defaultLanguage} {
+		for _, candidate := range []string{name, defaultTemplate} {
+			base := path.Join("templates", lang, kind, candidate)
+			if _, err := fs.Stat(templates, base+".txt"); err != nil {
+				continue
+			}
+			if _, err := fs.Stat(templates, base+".html"); err != nil {
+				continue
+			}
+			return base + ".txt", base + ".html"
+		}
+	}
+
+	base := path.Join("templates", defaultLanguage, kind, defaultTemplate)
+	return base + ".txt", base + ".html"
+}
+
+// Латинская запись кириллических букв для имен файлов шаблонов
+var translit = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh", 'з': "z", 'и': "i", 'й': "y",
+	'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f",
+	'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+}
+
+// templateName приводит тип нарушения к имени файла шаблона: "No Parking" -> "no_parking",
+// кириллица записывается латиницей: "Превышение скорости" -> "prevyshenie_skorosti"
+func templateName(violationType string) string {
+	var name strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(violationType)) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_':
+			name.WriteRune(r)
+		default:
+			if latin, ok := translit[r]; ok {
+				name.WriteString(latin)
+			} else {
+				name.WriteRune('_')
+			}
+		}
+	}
+
+	if name.Len() == 0 {
+		return defaultTemplate
+	}
+	return name.String()
+}
+
+// formatCoordinates форматирует координаты камеры "широта,долгота" и возвращает ссылку на карту.
+// Если координаты не удалось разобрать, они возвращаются как есть и без ссылки
+func formatCoordinates(coordinates string) (string, string) {
+	latStr, lonStr, ok := strings.Cut(coordinates, ",")
+	if !ok {
+		return strings.TrimSpace(coordinates), ""
+	}
+
+	lat, latErr := strconv.ParseFloat(strings.TrimSpace(latStr), 64)
+	lon, lonErr := strconv.ParseFloat(strings.TrimSpace(lonStr), 64)
+	if latErr != nil || lonErr != nil || lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+		return strings.TrimSpace(coordinates), ""
+	}
+
+	latText := strconv.FormatFloat(lat, 'f', -1, 64)
+	lonText := strconv.FormatFloat(lon, 'f', -1, 64)
+
+	return latText + ", " + lonText,
+		fmt.Sprintf("https://www.openstreetmap.org/?mlat=%s&mlon=%s#map=17/%s/%s", latText, lonText, latText, lonText)
+}
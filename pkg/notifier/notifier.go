@@ -0,0 +1,95 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/models"
+	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/config"
+	"github.com/guregu/null"
+	"github.com/spf13/viper"
+	"strings"
+)
+
+var ErrNoContacts = errors.New("у владельца транспорта нет контактов ни для одного из каналов уведомлений")
+
+// Notifier доставляет уведомление по одному каналу. ContactKey - ключ контакта в таблице contacts,
+// по которому канал находит получателя
+type Notifier interface {
+	Channel() string
+	ContactKey() string
+	Notify(ctx context.Context, recipient string, message Message) error
+}
+
+// Policy выбирает каналы доставки по контактам нарушителя: каналы перебираются в порядке из NOTIFY_CHANNELS,
+// пропускаются каналы, для которых у нарушителя нет контакта, уведомление считается доставленным
+// по первому успешному каналу
+type Policy struct {
+	notifiers []Notifier
+}
+
+// InitPolicy создает политику из notifiers, включенных в NOTIFY_CHANNELS. Если список каналов не задан,
+// уведомления отправляются только на почту
+func InitPolicy(notifiers ...Notifier) Policy {
+	byChannel := make(map[string]Notifier, len(notifiers))
+	for _, notifier := range notifiers {
+		byChannel[notifier.Channel()] = notifier
+	}
+
+	channels := viper.GetString(config.NotifyChannels)
+	if strings.TrimSpace(channels) == "" {
+		channels = models.ChannelEmail
+	}
+
+	var policy Policy
+	for _, channel := range strings.Split(channels, ",") {
+		if notifier, ok := byChannel[strings.TrimSpace(channel)]; ok {
+			policy.notifiers = append(policy.notifiers, notifier)
+		}
+	}
+
+	return policy
+}
+
+// Channels возвращает включенные каналы в порядке перебора
+func (p Policy) Channels() []string {
+	channels := make([]string, len(p.notifiers))
+	for i, notifier := range p.notifiers {
+		channels[i] = notifier.Channel()
+	}
+	return channels
+}
+
+// Deliver отправляет сообщение по каналам политики и возвращает результат каждой попытки.
+// Ошибка возвращается, если сообщение не доставлено ни по одному каналу
+func (p Policy) Deliver(ctx context.Context, message Message) ([]models.NotificationDelivery, error) {
+	var (
+		deliveries []models.NotificationDelivery
+		errs       []error
+	)
+
+	for _, notifier := range p.notifiers {
+		recipient := strings.TrimSpace(message.Fine.Contacts[notifier.ContactKey()])
+		if recipient == "" {
+			continue
+		}
+
+		delivery := models.NotificationDelivery{Channel: notifier.Channel(), Recipient: recipient}
+
+		err := notifier.Notify(ctx, recipient, message)
+		if err == nil {
+			delivery.Delivered = true
+			return append(deliveries, delivery), nil
+		}
+
+		delivery.Error = null.StringFrom(err.Error())
+		deliveries = append(deliveries, delivery)
+		errs = append(errs, fmt.Errorf("%s: %w", notifier.Channel(), err))
+	}
+
+	if len(deliveries) == 0 {
+		return nil, ErrNoContacts
+	}
+
+	return deliveries, errors.Join(errs...)
+}
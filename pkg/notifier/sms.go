@@ -0,0 +1,45 @@
+package notifier
+
+import (
+	"context"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/models"
+	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/config"
+	"github.com/spf13/viper"
+	"net/http"
+)
+
+// SMSNotifier отправляет уведомление через HTTP шлюз SMS на номер телефона нарушителя
+type SMSNotifier struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+// smsRequest - запрос к шлюзу SMS
+type smsRequest struct {
+	To   string `json:"to"`
+	Text string `json:"text"`
+}
+
+func InitSMSNotifier() SMSNotifier {
+	return SMSNotifier{
+		url:    viper.GetString(config.SMSGatewayURL),
+		token:  viper.GetString(config.SMSGatewayToken),
+		client: &http.Client{Timeout: httpTimeout},
+	}
+}
+
+func (s SMSNotifier) Channel() string {
+	return models.ChannelSMS
+}
+
+func (s SMSNotifier) ContactKey() string {
+	return models.ContactPhone
+}
+
+func (s SMSNotifier) Notify(ctx context.Context, recipient string, message Message) error {
+	return postJSON(ctx, s.client, s.url, s.token, smsRequest{
+		To:   recipient,
+		Text: message.Subject + "\n" + message.Body,
+	})
+}
@@ -0,0 +1,40 @@
+package tests
+
+import (
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/models"
+	"time"
+)
+
+const (
+	gatewayToken = "test-token"
+	mailFrom     = "fines@example.com"
+
+	// Фото для вложения, путь указан относительно родительского каталога
+	testPhoto = "/tests/testdata/violation.png"
+
+	// Ссылка на карту для координат fineData
+	mapURL = "https://www.openstreetmap.org/?mlat=55.7558&amp;mlon=37.6173#map=17/55.7558/37.6173"
+
+	// Значение нарушения, которое в HTML версии письма должно быть экранировано
+	unsafeViolationValue = "<script>alert(1)</script>"
+)
+
+// Данные штрафа владельца, у которого есть почта, телефон и id_vk
+var fineData = models.FineData{
+	Violation: models.Violation{Type: "Speeding", Amount: 500},
+	Contacts: map[string]string{
+		models.ContactEmail: "owner@example.com",
+		models.ContactPhone: "79031234567",
+		models.ContactVK:    "123123123",
+	},
+	PhotoUrl:       "/static/photo.jpg",
+	Coordinated:    "55.7558, 37.6173",
+	ViolationValue: "90 км/ч",
+	Date:           time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC),
+}
+
+// Темы писем о штрафе для кириллических типов нарушений: со своим шаблоном и без него
+var cyrillicSubjects = map[string]string{
+	"Превышение скорости": "Штраф за превышение скорости",
+	"Проезд на красный":   "Уведомление о правонарушении",
+}
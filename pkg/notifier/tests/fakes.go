@@ -0,0 +1,35 @@
+package tests
+
+import (
+	"context"
+	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/notifier"
+)
+
+// FakeNotifier - канал для тестов, который доставляет уведомления по каналу Name на контакт Key
+// или возвращает Err. Все переданные каналу сообщения сохраняются в Messages, если он задан,
+// а OnNotify, если задан, вызывается при каждой отправке
+type FakeNotifier struct {
+	Name     string
+	Key      string
+	Err      error
+	Messages *[]notifier.Message
+	OnNotify func()
+}
+
+func (f FakeNotifier) Channel() string {
+	return f.Name
+}
+
+func (f FakeNotifier) ContactKey() string {
+	return f.Key
+}
+
+func (f FakeNotifier) Notify(_ context.Context, _ string, message notifier.Message) error {
+	if f.Messages != nil {
+		*f.Messages = append(*f.Messages, message)
+	}
+	if f.OnNotify != nil {
+		f.OnNotify()
+	}
+	return f.Err
+}
@@ -0,0 +1,311 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/models"
+	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/config"
+	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/notifier"
+	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/smtpsink"
+	customErrors "github.com/gl1n0m3c/IT_LAB_INIT/pkg/utils/customerr"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/mail"
+	"net/textproto"
+	"os"
+	"strings"
+	"testing"
+)
+
+func compose(t *testing.T, kind string) notifier.Message {
+	message, err := notifier.Compose(kind, fineData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return message
+}
+
+func TestPolicy(t *testing.T) {
+	var webhookMessages []notifier.Message
+	email := FakeNotifier{Name: models.ChannelEmail, Key: models.ContactEmail, Err: errors.New("почтовый сервер недоступен")}
+	sms := FakeNotifier{Name: models.ChannelSMS, Key: models.ContactPhone}
+	webhook := FakeNotifier{Name: models.ChannelWebhook, Key: "telegram", Messages: &webhookMessages}
+
+	viper.Set(config.NotifyChannels, "webhook, email,unknown,sms")
+	defer viper.Set(config.NotifyChannels, "")
+
+	policy := notifier.InitPolicy(email, sms, webhook)
+	assert.Equal(t, []string{models.ChannelWebhook, models.ChannelEmail, models.ChannelSMS}, policy.Channels())
+
+	// У владельца нет контакта для вебхука, почта не отвечает, уведомление доставляется по SMS
+	deliveries, err := policy.Deliver(context.Background(), compose(t, models.NotificationFine))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if assert.Len(t, deliveries, 2) {
+		assert.Equal(t, models.ChannelEmail, deliveries[0].Channel)
+		assert.Equal(t, fineData.Contacts[models.ContactEmail], deliveries[0].Recipient)
+		assert.False(t, deliveries[0].Delivered)
+		assert.True(t, deliveries[0].Error.Valid)
+
+		assert.Equal(t, models.ChannelSMS, deliveries[1].Channel)
+		assert.Equal(t, fineData.Contacts[models.ContactPhone], deliveries[1].Recipient)
+		assert.True(t, deliveries[1].Delivered)
+	}
+	assert.Empty(t, webhookMessages)
+
+	// Без контактов для включенных каналов уведомление не отправляется
+	message := compose(t, models.NotificationFine)
+	message.Fine.Contacts = map[string]string{models.ContactVK: "123123123"}
+	deliveries, err = policy.Deliver(context.Background(), message)
+	assert.ErrorIs(t, err, notifier.ErrNoContacts)
+	assert.Empty(t, deliveries)
+
+	// Если не доставлено ни по одному каналу, возвращаются ошибки всех каналов
+	viper.Set(config.NotifyChannels, "")
+	deliveries, err = notifier.InitPolicy(email, sms, webhook).Deliver(context.Background(), compose(t, models.NotificationFine))
+	assert.Error(t, err)
+	assert.Len(t, deliveries, 1)
+}
+
+func TestCompose(t *testing.T) {
+	fine := compose(t, models.NotificationFine)
+	assert.Equal(t, "ru", fine.Language)
+	assert.Equal(t, "Уведомление о правонарушении", fine.Subject)
+	assert.Equal(t, fineData.PhotoUrl, fine.Photo)
+	assert.Contains(t, fine.Body, "500 руб.")
+	assert.Contains(t, fine.Body, "15.03.2024 12:00 UTC")
+	assert.Contains(t, fine.Body, "55.7558, 37.6173")
+	assert.Contains(t, fine.HTML, `<a href="`+mapURL+`">`)
+	assert.NotContains(t, fine.Body, "2024-03-15")
+
+	cancel := compose(t, models.NotificationFineCancel)
+	assert.Equal(t, "Отмена штрафа", cancel.Subject)
+	assert.Empty(t, cancel.Photo)
+
+	// Язык берется из контактов владельца
+	english := fineData
+	english.Contacts = map[string]string{models.ContactLanguage: "EN"}
+	fine, err := notifier.Compose(models.NotificationFine, english)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "en", fine.Language)
+	assert.Equal(t, "Traffic violation notice", fine.Subject)
+	assert.Contains(t, fine.Body, "March 15, 2024, 12:00 UTC")
+
+	// Для парковки есть свой шаблон, HTML версия экранирует данные случая
+	parking := fineData
+	parking.Violation.Type = "Parking"
+	parking.ViolationValue = unsafeViolationValue
+	parking.Coordinated = ""
+	fine, err = notifier.ComposeIn(models.NotificationFine, "en", parking)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "Parking fine", fine.Subject)
+	assert.Contains(t, fine.Body, unsafeViolationValue)
+	assert.NotContains(t, fine.HTML, unsafeViolationValue)
+	assert.Contains(t, fine.Body, "not specified")
+
+	// Кириллические типы нарушений различаются: у превышения скорости свой шаблон, у проезда на красный - нет
+	for violationType, subject := range cyrillicSubjects {
+		cyrillic := fineData
+		cyrillic.Violation.Type = violationType
+		fine, err = notifier.ComposeIn(models.NotificationFine, "ru", cyrillic)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, subject, fine.Subject, violationType)
+	}
+
+	_, err = notifier.Compose("unknown", fineData)
+	assert.ErrorIs(t, err, customErrors.UnknownNotificationKindErr)
+
+	_, err = notifier.ComposeIn(models.NotificationFine, "de", fineData)
+	assert.ErrorIs(t, err, customErrors.UnknownLanguageErr)
+	assert.Equal(t, []string{"en", "ru"}, notifier.Languages())
+}
+
+func TestSMSNotifier(t *testing.T) {
+	var (
+		request       map[string]string
+		authorization string
+		status        = http.StatusOK
+	)
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		w.WriteHeader(status)
+	}))
+	defer gateway.Close()
+
+	viper.Set(config.SMSGatewayURL, gateway.URL)
+	viper.Set(config.SMSGatewayToken, gatewayToken)
+	defer viper.Set(config.SMSGatewayURL, "")
+	defer viper.Set(config.SMSGatewayToken, "")
+
+	sms := notifier.InitSMSNotifier()
+	message := compose(t, models.NotificationFineCancel)
+
+	err := sms.Notify(context.Background(), fineData.Contacts[models.ContactPhone], message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "Bearer "+gatewayToken, authorization)
+	assert.Equal(t, fineData.Contacts[models.ContactPhone], request["to"])
+	assert.Contains(t, request["text"], message.Subject)
+
+	status = http.StatusBadGateway
+	err = sms.Notify(context.Background(), fineData.Contacts[models.ContactPhone], message)
+	assert.Error(t, err)
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var payload notifier.WebhookPayload
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+	}))
+	defer hook.Close()
+
+	viper.Set(config.WebhookURL, hook.URL)
+	defer viper.Set(config.WebhookURL, "")
+
+	webhook := notifier.InitWebhookNotifier()
+	assert.Equal(t, models.ContactVK, webhook.ContactKey())
+
+	err := webhook.Notify(context.Background(), fineData.Contacts[models.ContactVK], compose(t, models.NotificationFine))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, models.NotificationFine, payload.Kind)
+	assert.Equal(t, fineData.Contacts[models.ContactVK], payload.Recipient)
+	assert.Equal(t, fineData.Violation.Amount, payload.Amount)
+	assert.Equal(t, fineData.PhotoUrl, payload.PhotoUrl)
+	assert.True(t, fineData.Date.Equal(payload.Date))
+}
+
+// TestEmailNotifier отправляет письмо о штрафе в локальный smtpsink и разбирает его как почтовый клиент
+func TestEmailNotifier(t *testing.T) {
+	server, err := smtpsink.Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go server.Serve()
+	defer server.Close()
+
+	host, port, err := net.SplitHostPort(server.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	viper.Set(config.Mail, mailFrom)
+	viper.Set(config.MailHost, host)
+	viper.Set(config.MailPort, port)
+	defer viper.Set(config.Mail, "")
+	defer viper.Set(config.MailHost, "")
+	defer viper.Set(config.MailPort, "")
+
+	message := compose(t, models.NotificationFine)
+	// Путь к фото берется относительно родительского каталога, как у фото случаев
+	message.Photo = testPhoto
+	err = notifier.InitEmailNotifier().Notify(context.Background(), fineData.Contacts[models.ContactEmail], message)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	messages := server.Messages()
+	if !assert.Len(t, messages, 1) {
+		return
+	}
+	assert.Equal(t, mailFrom, messages[0].From)
+	assert.Equal(t, []string{fineData.Contacts[models.ContactEmail]}, messages[0].To)
+
+	received, err := mail.ReadMessage(bytes.NewReader(messages[0].Data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(received.Header.Get("Subject"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, message.Subject, subject)
+
+	parts := readParts(t, received.Header.Get("Content-Type"), received.Body)
+	if !assert.Len(t, parts, 2) {
+		return
+	}
+
+	alternative := readParts(t, parts[0].Header.Get("Content-Type"), bytes.NewReader(parts[0].body))
+	if assert.Len(t, alternative, 2) {
+		assert.Equal(t, "text/plain; charset=utf-8", alternative[0].Header.Get("Content-Type"))
+		assert.Contains(t, string(alternative[0].body), "Вам назначается штраф")
+		assert.Equal(t, "text/html; charset=utf-8", alternative[1].Header.Get("Content-Type"))
+		assert.Contains(t, string(alternative[1].body), "<html")
+	}
+
+	attachment := parts[1]
+	mediaType, params, err := mime.ParseMediaType(attachment.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "image/png", mediaType)
+	assert.Equal(t, "violation.png", params["name"])
+	_, params, err = mime.ParseMediaType(attachment.Header.Get("Content-Disposition"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "violation.png", params["filename"])
+
+	photo, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(string(attachment.body), "\r\n", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := os.ReadFile(".." + testPhoto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, expected, photo)
+}
+
+// part - часть MIME письма с прочитанным содержимым
+type part struct {
+	Header textproto.MIMEHeader
+	body   []byte
+}
+
+// readParts разбирает составную часть письма с типом contentType
+func readParts(t *testing.T, contentType string, r io.Reader) []part {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(mediaType, "multipart/") {
+		t.Fatalf("ожидалась составная часть, получено %s", mediaType)
+	}
+
+	var parts []part
+	reader := multipart.NewReader(r, params["boundary"])
+	for {
+		p, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return parts
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := io.ReadAll(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		parts = append(parts, part{Header: p.Header, body: body})
+	}
+}
@@ -0,0 +1,70 @@
+package notifier
+
+import (
+	"context"
+	"github.com/gl1n0m3c/IT_LAB_INIT/internal/models"
+	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/config"
+	"github.com/spf13/viper"
+	"net/http"
+	"time"
+)
+
+// WebhookNotifier передает уведомление внешнему сервису (например, боту в мессенджере) запросом на WEBHOOK_URL.
+// Получатель берется из контакта WEBHOOK_CONTACT_KEY, по умолчанию - из id_vk
+type WebhookNotifier struct {
+	url        string
+	token      string
+	contactKey string
+	client     *http.Client
+}
+
+// WebhookPayload - тело запроса вебхука
+type WebhookPayload struct {
+	Kind           string    `json:"kind"`
+	Recipient      string    `json:"recipient"`
+	Subject        string    `json:"subject"`
+	Text           string    `json:"text"`
+	Amount         int       `json:"amount"`
+	ViolationType  string    `json:"violation_type"`
+	ViolationValue string    `json:"violation_value"`
+	Coordinates    string    `json:"coordinates"`
+	PhotoUrl       string    `json:"photo_url"`
+	Date           time.Time `json:"date"`
+}
+
+func InitWebhookNotifier() WebhookNotifier {
+	contactKey := viper.GetString(config.WebhookContactKey)
+	if contactKey == "" {
+		contactKey = models.ContactVK
+	}
+
+	return WebhookNotifier{
+		url:        viper.GetString(config.WebhookURL),
+		token:      viper.GetString(config.WebhookToken),
+		contactKey: contactKey,
+		client:     &http.Client{Timeout: httpTimeout},
+	}
+}
+
+func (w WebhookNotifier) Channel() string {
+	return models.ChannelWebhook
+}
+
+func (w WebhookNotifier) ContactKey() string {
+	return w.contactKey
+}
+
+func (w WebhookNotifier) Notify(ctx context.Context, recipient string, message Message) error {
+	return postJSON(ctx, w.client, w.url, w.token, WebhookPayload{
+		Kind:           message.Kind,
+		Recipient:      recipient,
+		Subject:        message.Subject,
+		Text:           message.Body,
+		Amount:         message.Fine.Violation.Amount,
+		ViolationType:  message.Fine.Violation.Type,
+		ViolationValue: message.Fine.ViolationValue,
+		Coordinates:    message.Fine.Coordinated,
+		PhotoUrl:       message.Fine.PhotoUrl,
+		Date:           message.Fine.Date,
+	})
+}
@@ -0,0 +1,85 @@
+package plates
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	ErrEmpty     = errors.New("номер транспорта отсутствует")
+	ErrBadFormat = errors.New("номер не соответствует формату А000АА00(0)")
+	ErrBadNumber = errors.New("номер 000 не выдается")
+	ErrBadRegion = errors.New("недопустимый код региона")
+)
+
+// lookAlikes сопоставляет кириллическим буквам, допустимым в номерах, их латинские двойники
+var lookAlikes = map[rune]rune{
+	'А': 'A', 'В': 'B', 'Е': 'E', 'К': 'K', 'М': 'M', 'Н': 'H',
+	'О': 'O', 'Р': 'P', 'С': 'C', 'Т': 'T', 'У': 'Y', 'Х': 'X',
+}
+
+var (
+	// Серия из трех букв разделена номером: А000АА00(0)
+	platePattern = regexp.MustCompile(`^([ABEKMHOPCTYX])(\d{3})([ABEKMHOPCTYX]{2})(\d{2,3})$`)
+	// Некоторые камеры передают серию целиком перед номером: ААА00000(0)
+	seriesFirstPattern = regexp.MustCompile(`^([ABEKMHOPCTYX])([ABEKMHOPCTYX]{2})(\d{3})(\d{2,3})$`)
+)
+
+// Normalize приводит номер к каноническому виду: латинские заглавные буквы в порядке А000АА00(0).
+// Кириллические буквы заменяются латинскими двойниками, пробелы и дефисы отбрасываются
+func Normalize(plate string) (string, error) {
+	var sb strings.Builder
+	for _, r := range strings.ToUpper(plate) {
+		switch {
+		case r == ' ' || r == '-' || r == '|':
+			continue
+		case lookAlikes[r] != 0:
+			sb.WriteRune(lookAlikes[r])
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	canonical := sb.String()
+
+	if canonical == "" {
+		return "", ErrEmpty
+	}
+
+	var letter, number, series, region string
+	if parts := platePattern.FindStringSubmatch(canonical); parts != nil {
+		letter, number, series, region = parts[1], parts[2], parts[3], parts[4]
+	} else if parts := seriesFirstPattern.FindStringSubmatch(canonical); parts != nil {
+		letter, series, number, region = parts[1], parts[2], parts[3], parts[4]
+	} else {
+		return "", fmt.Errorf("%w: %s", ErrBadFormat, plate)
+	}
+
+	if number == "000" {
+		return "", fmt.Errorf("%w: %s", ErrBadNumber, plate)
+	}
+	if !validRegion(region) {
+		return "", fmt.Errorf("%w %s: %s", ErrBadRegion, region, plate)
+	}
+
+	return letter + number + series + region, nil
+}
+
+// FromParts собирает номер из серии (3 буквы), номера (3 цифры) и кода региона
+func FromParts(chars, numbers, region string) (string, error) {
+	return Normalize(chars + numbers + region)
+}
+
+// validRegion проверяет код региона: двузначные коды от 01 до 99,
+// трехзначные - дополнительные коды регионов, начинающиеся не с нуля, например 102 или 777
+func validRegion(region string) bool {
+	switch len(region) {
+	case 2:
+		return region != "00"
+	case 3:
+		return region[0] != '0' && region[1:] != "00"
+	default:
+		return false
+	}
+}
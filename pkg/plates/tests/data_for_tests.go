@@ -0,0 +1,37 @@
+package tests
+
+import "github.com/gl1n0m3c/IT_LAB_INIT/pkg/plates"
+
+var validPlates = []struct {
+	plate    string
+	expected string
+}{
+	{"A123BC77", "A123BC77"},
+	// Кириллица и латиница дают один и тот же номер
+	{"А123ВС77", "A123BC77"},
+	{"А123BC77", "A123BC77"},
+	{"а123вс77", "A123BC77"},
+	{"a 123 bc-77", "A123BC77"},
+	{"Х777ХХ777", "X777XX777"},
+	{"ЕКМ001199", "E001KM199"},
+	{"ABC12377", "A123BC77"},
+	{"O001OO01", "O001OO01"},
+}
+
+var invalidPlates = []struct {
+	plate string
+	err   error
+}{
+	{"", plates.ErrEmpty},
+	{" - ", plates.ErrEmpty},
+	// Буквы, которых нет в номерах
+	{"D456EF00", plates.ErrBadFormat},
+	{"Б123ВС77", plates.ErrBadFormat},
+	{"A12BC77", plates.ErrBadFormat},
+	{"A123BC7", plates.ErrBadFormat},
+	{"A123BC7777", plates.ErrBadFormat},
+	{"A000BC77", plates.ErrBadNumber},
+	{"A123BC00", plates.ErrBadRegion},
+	{"A123BC077", plates.ErrBadRegion},
+	{"A123BC100", plates.ErrBadRegion},
+}
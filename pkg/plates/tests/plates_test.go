@@ -0,0 +1,36 @@
+package tests
+
+import (
+	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/plates"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	for _, tc := range validPlates {
+		result, err := plates.Normalize(tc.plate)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.plate, err)
+		}
+		assert.Equal(t, tc.expected, result, tc.plate)
+	}
+}
+
+func TestNormalizeInvalid(t *testing.T) {
+	for _, tc := range invalidPlates {
+		_, err := plates.Normalize(tc.plate)
+		assert.ErrorIs(t, err, tc.err, tc.plate)
+	}
+}
+
+func TestFromParts(t *testing.T) {
+	result, err := plates.FromParts("АВС", "123", "77")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "A123BC77", result)
+
+	// Раньше пустая серия приводила к панике
+	_, err = plates.FromParts("", "123", "77")
+	assert.ErrorIs(t, err, plates.ErrBadFormat)
+}
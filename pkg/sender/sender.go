@@ -4,12 +4,15 @@ import (
 	"bytes"
 	"encoding/base64"
 	"fmt"
-	"github.com/gl1n0m3c/IT_LAB_INIT/internal/models"
 	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/config"
 	"github.com/spf13/viper"
+	"mime"
+	"mime/multipart"
 	"net/http"
 	"net/smtp"
+	"net/textproto"
 	"os"
+	"path/filepath"
 	"strings"
 )
 
@@ -31,14 +34,24 @@ type Sender struct {
 	auth smtp.Auth
 }
 
+// Message - письмо. Body - текстовая версия, HTML - необязательная HTML версия того же текста,
+// Attachment - вложение с именем AttachmentName и типом AttachmentType
 type Message struct {
-	To         []string
-	Subject    string
-	Body       string
-	Attachment []byte
+	To             []string
+	Subject        string
+	Body           string
+	HTML           string
+	Attachment     []byte
+	AttachmentName string
+	AttachmentType string
 }
 
+// New создает отправителя. Без пароля письма отправляются без авторизации, например в локальный smtpsink
 func New() *Sender {
+	if password == "" {
+		return &Sender{}
+	}
+
 	auth := smtp.PlainAuth("", mail, password, host)
 	return &Sender{auth}
 }
@@ -51,6 +64,8 @@ func NewMessage(s, b string) *Message {
 	return &Message{Subject: s, Body: b, Attachment: []byte{}}
 }
 
+// AttachFile прикладывает к письму файл src с его именем. Тип определяется по расширению,
+// а если расширение неизвестно - по содержимому
 func (m *Message) AttachFile(src string) error {
 	b, err := os.ReadFile(src)
 	if err != nil {
@@ -58,76 +73,83 @@ func (m *Message) AttachFile(src string) error {
 	}
 
 	m.Attachment = b
+	m.AttachmentName = filepath.Base(src)
+	m.AttachmentType = mime.TypeByExtension(filepath.Ext(src))
+	if m.AttachmentType == "" {
+		m.AttachmentType = http.DetectContentType(b)
+	}
 	return nil
 }
 
+// ToBytes собирает письмо в формате MIME: текстовая и HTML версии (multipart/alternative)
+// и вложение (multipart/mixed). Разделители частей генерируются случайно
 func (m *Message) ToBytes() []byte {
 	buf := bytes.NewBuffer(nil)
-	boundary := "skibidi-va-pa-pa"
+	mixed := multipart.NewWriter(buf)
 
 	// Заголовки письма
 	buf.WriteString(fmt.Sprintf("From: %s\r\n", mail))
 	buf.WriteString(fmt.Sprintf("To: %s\r\n", strings.Join(m.To, ", ")))
-	buf.WriteString(fmt.Sprintf("Subject: %s\r\n", m.Subject))
+	buf.WriteString(fmt.Sprintf("Subject: %s\r\n", mime.BEncoding.Encode("utf-8", m.Subject)))
 	buf.WriteString("MIME-Version: 1.0\r\n")
-	buf.WriteString(fmt.Sprintf("Content-Type: multipart/mixed; boundary=%s\r\n", boundary))
+	buf.WriteString(fmt.Sprintf("Content-Type: multipart/mixed; boundary=%s\r\n", mixed.Boundary()))
 	buf.WriteString("\r\n") // Пустая строка отделяет заголовки от тела
 
-	// Текстовое сообщение
-	buf.WriteString(fmt.Sprintf("--%s\r\n", boundary))
-	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
-	buf.WriteString("\r\n") // Пустая строка отделяет заголовки от тела
-	buf.WriteString(m.Body)
-	buf.WriteString("\r\n") // Заканчиваем тело сообщения
+	// Текст письма
+	if m.HTML == "" {
+		writeTextPart(mixed, "text/plain", m.Body)
+	} else {
+		alternativeBuf := bytes.NewBuffer(nil)
+		alternative := multipart.NewWriter(alternativeBuf)
+		writeTextPart(alternative, "text/plain", m.Body)
+		writeTextPart(alternative, "text/html", m.HTML)
+		alternative.Close()
+
+		part, _ := mixed.CreatePart(textproto.MIMEHeader{
+			"Content-Type": {mime.FormatMediaType("multipart/alternative", map[string]string{"boundary": alternative.Boundary()})},
+		})
+		part.Write(alternativeBuf.Bytes())
+	}
 
-	// Фото
+	// Вложение
 	if len(m.Attachment) > 0 {
-		buf.WriteString(fmt.Sprintf("--%s\r\n", boundary))
-		buf.WriteString(fmt.Sprintf("Content-Type: %s\r\n", http.DetectContentType(m.Attachment)))
-		buf.WriteString("Content-Transfer-Encoding: base64\r\n")
-		buf.WriteString("Content-Disposition: attachment; filename=\"photo.jpg\"\r\n")
-		buf.WriteString("\r\n") // Пустая строка отделяет заголовки от тела
-
-		b := make([]byte, base64.StdEncoding.EncodedLen(len(m.Attachment)))
-		base64.StdEncoding.Encode(b, m.Attachment)
-		buf.Write(b)
-		buf.WriteString("\r\n")
+		name := m.AttachmentName
+		if name == "" {
+			name = "attachment"
+		}
+		contentType := m.AttachmentType
+		if contentType == "" {
+			contentType = http.DetectContentType(m.Attachment)
+		}
+
+		part, _ := mixed.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {mime.FormatMediaType(contentType, map[string]string{"name": name})},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": name})},
+		})
+
+		encoded := base64.StdEncoding.EncodeToString(m.Attachment)
+		for len(encoded) > base64LineLength {
+			part.Write([]byte(encoded[:base64LineLength] + "\r\n"))
+			encoded = encoded[base64LineLength:]
+		}
+		part.Write([]byte(encoded + "\r\n"))
 	}
 
-	// Закрывающий boundary
-	buf.WriteString(fmt.Sprintf("--%s--\r\n", boundary))
+	// Закрывающий разделитель
+	mixed.Close()
 
 	return buf.Bytes()
 }
 
-func MailSender(fineData models.FineData) error {
-	if fineData.Mail == "" {
-		return fmt.Errorf("указана некорректная почта")
-	}
-
-	InitEmailConfig()
-
-	sender := New()
-
-	// Формирование сообщения о правонарушении
-	m := NewMessage(
-		"Уведомление о правонарушении",
-		fmt.Sprintf("Вам назначается штраф в размере %d рублей.\n"+
-			"Кординаты: %s\n"+
-			"Тип и занчение правонарушения: %s, %s\n"+
-			"Дата правонарушения: %s\n\n"+
-			"Фото происшествия прилагаются:",
-			fineData.Violation.Amount, fineData.Coordinated, fineData.Violation.Type, fineData.ViolationValue, fineData.Date,
-		),
-	)
-	// Указание адрессанта
-	m.To = []string{fineData.Mail}
-
-	// Загрузка фото
-	err := m.AttachFile(".." + fineData.PhotoUrl)
-	if err != nil {
-		return err
-	}
+// Длина строки base64 во вложении по RFC 2045
+const base64LineLength = 76
 
-	return sender.Send(m)
+// writeTextPart добавляет текстовую часть в кодировке UTF-8
+func writeTextPart(w *multipart.Writer, contentType, text string) {
+	part, _ := w.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType + "; charset=utf-8"},
+		"Content-Transfer-Encoding": {"8bit"},
+	})
+	part.Write([]byte(strings.ReplaceAll(strings.ReplaceAll(text, "\r\n", "\n"), "\n", "\r\n")))
 }
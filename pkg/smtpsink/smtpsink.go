@@ -0,0 +1,185 @@
+// Package smtpsink - SMTP сервер для локальной проверки уведомлений. Принимает письма без авторизации
+// и шифрования, хранит их в памяти и, если задан Dir, сохраняет в файлы .eml, никуда не отправляя
+package smtpsink
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Максимальный размер принимаемого письма
+const maxMessageSize = 32 << 20
+
+type Message struct {
+	From     string
+	To       []string
+	Data     []byte
+	Received time.Time
+}
+
+type Server struct {
+	// Dir - каталог для сохранения писем, пустой - письма хранятся только в памяти
+	Dir string
+	// OnMessage вызывается для каждого принятого письма
+	OnMessage func(message Message)
+
+	listener net.Listener
+	mu       sync.Mutex
+	messages []Message
+	wg       sync.WaitGroup
+}
+
+// Listen начинает принимать подключения на addr, письма обрабатываются после вызова Serve
+func Listen(addr string) (*Server, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return &Server{listener: listener}, nil
+}
+
+func (s *Server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Serve обслуживает подключения, пока сервер не закрыт
+func (s *Server) Serve() error {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.handle(conn)
+		}()
+	}
+}
+
+// Close перестает принимать подключения и ждет завершения текущих
+func (s *Server) Close() error {
+	err := s.listener.Close()
+	s.wg.Wait()
+	return err
+}
+
+// Messages возвращает принятые письма в порядке получения
+func (s *Server) Messages() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Message(nil), s.messages...)
+}
+
+func (s *Server) handle(netConn net.Conn) {
+	conn := textproto.NewConn(netConn)
+	defer conn.Close()
+
+	var message Message
+	reply := func(format string, args ...interface{}) bool {
+		return conn.PrintfLine(format, args...) == nil
+	}
+
+	if !reply("220 smtpsink ready") {
+		return
+	}
+
+	for {
+		line, err := conn.ReadLine()
+		if err != nil {
+			return
+		}
+
+		command, argument, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(command) {
+		case "EHLO":
+			if !reply("250-smtpsink") || !reply("250 8BITMIME") {
+				return
+			}
+			continue
+		case "HELO", "NOOP":
+		case "RSET":
+			message = Message{}
+		case "MAIL":
+			message = Message{From: address(argument)}
+		case "RCPT":
+			message.To = append(message.To, address(argument))
+		case "DATA":
+			if message.From == "" || len(message.To) == 0 {
+				if !reply("503 MAIL и RCPT должны предшествовать DATA") {
+					return
+				}
+				continue
+			}
+			if !reply("354 End data with <CR><LF>.<CR><LF>") {
+				return
+			}
+
+			message.Data, err = io.ReadAll(io.LimitReader(conn.DotReader(), maxMessageSize))
+			if err != nil {
+				return
+			}
+			message.Received = time.Now()
+
+			if err = s.store(message); err != nil {
+				if !reply("451 %v", err) {
+					return
+				}
+				continue
+			}
+			message = Message{}
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			if !reply("502 Команда не поддерживается") {
+				return
+			}
+			continue
+		}
+
+		if !reply("250 OK") {
+			return
+		}
+	}
+}
+
+func (s *Server) store(message Message) error {
+	s.mu.Lock()
+	s.messages = append(s.messages, message)
+	number := len(s.messages)
+	s.mu.Unlock()
+
+	if s.Dir != "" {
+		name := filepath.Join(s.Dir, fmt.Sprintf("%s-%d.eml", message.Received.Format("20060102-150405"), number))
+		if err := os.WriteFile(name, message.Data, 0644); err != nil {
+			return err
+		}
+	}
+
+	if s.OnMessage != nil {
+		s.OnMessage(message)
+	}
+
+	return nil
+}
+
+// address извлекает адрес из аргумента MAIL FROM:<...> или RCPT TO:<...>
+func address(argument string) string {
+	_, value, _ := strings.Cut(argument, ":")
+	value, _, _ = strings.Cut(strings.TrimSpace(value), " ")
+	return strings.Trim(value, "<>")
+}
@@ -0,0 +1,8 @@
+package tests
+
+const (
+	mailFrom    = "fines@example.com"
+	mailTo      = "owner@example.com"
+	mailSubject = "Уведомление о правонарушении"
+	mailBody    = "Вам назначается штраф в размере 500 рублей."
+)
@@ -0,0 +1,67 @@
+package tests
+
+import (
+	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/sender"
+	"github.com/gl1n0m3c/IT_LAB_INIT/pkg/smtpsink"
+	"github.com/stretchr/testify/assert"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func startSink(t *testing.T) *smtpsink.Server {
+	server, err := smtpsink.Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server.Dir = t.TempDir()
+
+	go server.Serve()
+	t.Cleanup(func() { server.Close() })
+
+	return server
+}
+
+// TestSinkReceivesMail отправляет письмо так же, как sender без пароля, и проверяет, что sink его сохранил
+func TestSinkReceivesMail(t *testing.T) {
+	server := startSink(t)
+
+	message := sender.NewMessage(mailSubject, mailBody)
+	message.To = []string{mailTo}
+
+	err := smtp.SendMail(server.Addr(), nil, mailFrom, message.To, message.ToBytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	messages := server.Messages()
+	if assert.Len(t, messages, 1) {
+		assert.Equal(t, mailFrom, messages[0].From)
+		assert.Equal(t, []string{mailTo}, messages[0].To)
+		assert.Contains(t, string(messages[0].Data), mailBody)
+	}
+
+	files, err := os.ReadDir(server.Dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if assert.Len(t, files, 1) {
+		data, err := os.ReadFile(filepath.Join(server.Dir, files[0].Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, messages[0].Data, data)
+	}
+}
+
+// TestSinkRejectsAuth проверяет, что sink не объявляет AUTH и письмо с авторизацией не отправляется
+func TestSinkRejectsAuth(t *testing.T) {
+	server := startSink(t)
+
+	auth := smtp.PlainAuth("", mailFrom, "password", "127.0.0.1")
+	err := smtp.SendMail(server.Addr(), auth, mailFrom, []string{mailTo}, []byte(mailBody))
+
+	assert.Error(t, err)
+	assert.Empty(t, server.Messages())
+}
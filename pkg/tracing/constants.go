@@ -12,8 +12,22 @@ const (
 	TimeFormatType = "error.query-time-format"
 
 	// Managers
-	GetFulCaseByIDType      = "error.get-ful-case-by-id"
-	GetSpecialistRatingType = "error.get-specialist-rating"
+	GetFulCaseByIDType       = "error.get-ful-case-by-id"
+	GetSpecialistRatingType  = "error.get-specialist-rating"
+	GetUnmatchedCasesType    = "error.get-unmatched-cases"
+	ResolveUnmatchedType     = "error.resolve-unmatched-case"
+	GetReviewCasesType       = "error.get-review-cases"
+	GetCaseVotesType         = "error.get-case-votes"
+	ResolveReviewCaseType    = "error.resolve-review-case"
+	OverrideCaseType         = "error.override-case"
+	GetCameraSkipStatsType   = "error.get-camera-skip-stats"
+	CreateRatingReasonType   = "error.create-rating-reason"
+	GetRatingReasonsType     = "error.get-rating-reasons"
+	UpdateRatingReasonType   = "error.update-rating-reason"
+	CreateControlCaseType    = "error.create-control-case"
+	GetDeadNotificationsType = "error.get-dead-notifications"
+	RetryNotificationType    = "error.retry-notification"
+	PreviewNotificationType  = "error.preview-notification"
 
 	// Public
 	ManagerLoginType       = "error.manager-login"
@@ -22,11 +36,14 @@ const (
 	CameraCreateType       = "error.camera-create"
 	CameraDeleteType       = "error.camera-delete"
 	CaseCreateType         = "error.case-create"
+	CaseCreateBatchType    = "error.case-create-batch"
 	RefreshType            = "error.refresh"
 
 	// Specialists
 	CreateRatedType     = "error.rated-create"
+	SkipCaseType        = "error.skip-case"
 	GetCasesByLevelType = "error.get-cases"
+	TakeNextCaseType    = "error.take-next-case"
 	GetRatingType       = "error.get-rating"
 	GetRatedSolvedType  = "error.get-rated-solved"
 	GetMeType           = "error.get-me"
@@ -37,8 +54,22 @@ const (
 	CallToService = "Call to service"
 
 	// Managers
-	GetFulCaseByID      = "Get ful case info by it's id"
-	GetSpecialistRating = "Get specialist rating"
+	GetFulCaseByID       = "Get ful case info by it's id"
+	GetSpecialistRating  = "Get specialist rating"
+	GetUnmatchedCases    = "Get unmatched cases"
+	ResolveUnmatched     = "Resolve unmatched case"
+	GetReviewCases       = "Get review cases"
+	GetCaseVotes         = "Get case votes"
+	ResolveReviewCase    = "Resolve review case"
+	OverrideCase         = "Override case"
+	GetCameraSkipStats   = "Get camera skip stats"
+	CreateRatingReason   = "Create rating reason"
+	GetRatingReasons     = "Get rating reasons"
+	UpdateRatingReason   = "Update rating reason"
+	CreateControlCase    = "Create control case"
+	GetDeadNotifications = "Get dead notifications"
+	RetryNotification    = "Retry notification"
+	PreviewNotification  = "Preview notification"
 
 	// Public
 	ManagerLogin       = "Manager login"
@@ -47,11 +78,14 @@ const (
 	CameraCreate       = "Camera create"
 	CameraDelete       = "Camera delete"
 	CaseCreate         = "Case create"
+	CaseCreateBatch    = "Case create batch"
 	Refresh            = "Refresh"
 
 	// Specialists
 	CreateRated     = "Create rated"
+	SkipCase        = "Skip case"
 	GetCasesByLevel = "Get cases by level"
+	TakeNextCase    = "Take next case"
 	GetRating       = "Get rating"
 	GetRatedSolved  = "Get rated solved"
 	GetMe           = "Get me"
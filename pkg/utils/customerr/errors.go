@@ -1,19 +1,72 @@
 package customerr
 
-import "errors"
+import (
+	"errors"
+	"strings"
+)
 
 var (
 	UniqueSpecialistErr = errors.New("Специалист с таким логином уже существует.")
 	UniqueRatedErr      = errors.New("Вы уже оценили этот кейс.")
+	UniqueSkipErr       = errors.New("Вы уже пропустили этот кейс.")
 	NeedToAuthorizeErr  = errors.New("Необходимо заново авторизоваться")
 
 	NoRowsCaseErr            = errors.New("Случай с таким id не найдена")
 	NoRowsSpecialistLoginErr = errors.New("Пользователь с таким логином не найден")
 	NoRowsSpecialistIDErr    = errors.New("Пользователь с таким id не найден")
 	NoRowsCameraErr          = errors.New("Камера с таким id не найдена")
+	NoRowsViolationErr       = errors.New("Нарушение с таким id не найдено")
+	NoRowsContactErr         = errors.New("Контакты владельца транспорта не найдены")
+	NoRowsUnmatchedCaseErr   = errors.New("Отложенный случай с таким id не найден")
+	NoRowsRatingReasonErr    = errors.New("Причина оценки с таким кодом не найдена")
+	NoRowsNotificationErr    = errors.New("Неотправленное уведомление с таким id не найдено")
+
+	UniqueContactErr      = errors.New("Контакты для этого транспорта уже существуют")
+	UniqueRatingReasonErr = errors.New("Причина оценки с таким кодом уже существует")
+
+	InactiveRatingReasonErr = errors.New("Причина оценки отключена")
 
 	UserUnverified = errors.New("Аккаунт пользователя не подтвержден")
 	UserBadLevel   = errors.New("Аккаунт пользователя имеет неподходящий уровень")
 
 	CaseAlreadySolved = errors.New("Данный кейс закрыт для отценивания")
+	NoCasesToReserve  = errors.New("Нет свободных случаев для оценивания")
+	CaseNotInReview   = errors.New("Случай не ожидает решения руководителя")
+	CaseNotSolved     = errors.New("Решение по случаю еще не принято")
+
+	UnknownCameraFormatErr = errors.New("Не удалось определить формат данных камеры")
+	NoPayloadErr           = errors.New("Данные камеры отсутствуют")
+	PayloadTooLargeErr     = errors.New("Данные камеры превышают допустимый размер")
+
+	BadBatchArchiveErr = errors.New("Архив со случаями некорректен")
+	BatchTooLargeErr   = errors.New("Превышено допустимое количество случаев в пакете")
+	BatchMismatchErr   = errors.New("Количество фото не совпадает с количеством данных камер")
+
+	UnknownNotificationKindErr = errors.New("Неизвестный вид уведомления")
+	UnknownLanguageErr         = errors.New("Шаблоны уведомлений на этом языке отсутствуют")
 )
+
+// Сущности, на которые ссылается случай
+const (
+	EntityCamera    = "camera"
+	EntityViolation = "violation"
+	EntityTransport = "transport"
+)
+
+type MissingReference struct {
+	Entity string `json:"entity"`
+	ID     string `json:"id"`
+}
+
+// MissingReferencesErr возвращается, если случай ссылается на несуществующие камеру, нарушение или транспорт
+type MissingReferencesErr struct {
+	References []MissingReference
+}
+
+func (e MissingReferencesErr) Error() string {
+	references := make([]string, 0, len(e.References))
+	for _, reference := range e.References {
+		references = append(references, reference.Entity+" "+reference.ID)
+	}
+	return "Случай ссылается на несуществующие сущности: " + strings.Join(references, ", ")
+}
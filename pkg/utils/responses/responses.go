@@ -1,5 +1,7 @@
 package responses
 
+import customErrors "github.com/gl1n0m3c/IT_LAB_INIT/pkg/utils/customerr"
+
 const (
 	Response400 = "Bad request: %s"
 	Response500 = "Internal server error"
@@ -11,10 +13,17 @@ const (
 	ResponseNoByteStringProvided = "Байтовая строка отсутствует"
 	ResponseNoPhotoProvided      = "Фото отсуствует"
 
-	ResponseBadFileSize   = "Ваш файл слишком большой"
-	ResponseBadPhotoFile  = "Вы загрузили не фото"
-	ResponseBadByteString = "Байтовая строка некорректна"
-	ResponseBadTime       = "Переданное время некорректно"
+	ResponseBadFileSize         = "Ваш файл слишком большой"
+	ResponseBadPhotoFile        = "Вы загрузили не фото"
+	ResponseBadByteString       = "Байтовая строка некорректна"
+	ResponseBadByteStringReason = "Байтовая строка некорректна: %s"
+	ResponseBadPayloadSize      = "Данные камеры превышают допустимый размер"
+	ResponseNoBatchPhoto        = "Для данных камеры отсутствует фото"
+	ResponseNoBatchPayload      = "Для фото отсутствуют данные камеры"
+	ResponseBadBatchFile        = "Неподдерживаемый файл в архиве: %s"
+	ResponseCaseNotCreated      = "Не удалось сохранить случай"
+	ResponseCaseUnmatched       = "Контакты владельца транспорта не найдены, случай ожидает привязки менеджером"
+	ResponseBadTime             = "Переданное время некорректно"
 
 	ResponseBadQuery = "Параметры запроса указаны некорректно"
 
@@ -29,10 +38,20 @@ type CreationStringResponse struct {
 	ID string `json:"id"`
 }
 
+type UnmatchedCaseResponse struct {
+	UnmatchedID int    `json:"unmatched_id"`
+	Message     string `json:"message"`
+}
+
 type MessageResponse struct {
 	Message string `json:"message"`
 }
 
+type MissingReferencesResponse struct {
+	Message string                          `json:"message"`
+	Missing []customErrors.MissingReference `json:"missing"`
+}
+
 type MessageDataResponse struct {
 	Message string `json:"message"`
 	Data    any    `json:"data"`
@@ -47,6 +66,13 @@ func NewMessageResponse(message string) MessageResponse {
 	return MessageResponse{Message: message}
 }
 
+func NewMissingReferencesResponse(err customErrors.MissingReferencesErr) MissingReferencesResponse {
+	return MissingReferencesResponse{
+		Message: err.Error(),
+		Missing: err.References,
+	}
+}
+
 func NewJWTRefreshResponse(JWT, RefreshToken string) JWTRefresh {
 	return JWTRefresh{
 		JWT:          JWT,
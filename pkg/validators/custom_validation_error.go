@@ -15,6 +15,8 @@ func CustomErrorMessage(err error) string {
 			switch e.Tag() {
 			case "required":
 				sb.WriteString(fmt.Sprintf("Поле %s является обязательным.", e.Field()))
+			case "oneof":
+				sb.WriteString(fmt.Sprintf("Поле %s должно принимать одно из значений: %s.", e.Field(), e.Param()))
 			case "password":
 				sb.WriteString("Пароль должен состоять минимум из 8 символов, заглавных и строчных букв.")
 			default: